@@ -0,0 +1,30 @@
+package day01
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"strconv"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes a calibration document of size lines, each with at least
+// one digit among letters and spelled out digits.
+func Generate(rng *rand.Rand, size int) []byte {
+	var b bytes.Buffer
+	for range size {
+		digit := rng.IntN(4)
+		for i := range gen.Between(rng, 2, 6) {
+			switch {
+			case i == digit || rng.IntN(4) == 0:
+				b.WriteString(strconv.Itoa(gen.Between(rng, 1, 9)))
+			case rng.IntN(2) == 0:
+				b.WriteString(spelledDigits[rng.IntN(len(spelledDigits))].word)
+			default:
+				b.WriteString(gen.Word(rng, gen.Between(rng, 1, 4), "abcdefghijklmnopqrstuvwxyz"))
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
@@ -1,12 +0,0 @@
-{
-    gsub(/one/, "on1ne");
-    gsub(/two/, "tw2wo");
-    gsub(/three/, "thre3hree");
-    gsub(/four/, "fou4our");
-    gsub(/five/, "fiv5ive");
-    gsub(/six/, "si6ix");
-    gsub(/seven/, "seve7even");
-    gsub(/eight/, "eigh8ight");
-    gsub(/nine/, "nin9ine");
-    print;
-}
\ No newline at end of file
@@ -1,34 +1,87 @@
-package main
+package day01
 
 import (
-	"bufio"
-	"fmt"
-	"os"
+	"context"
+	"io"
 	"strconv"
+	"strings"
 	"unicode"
+
+	"github.com/havill/AdventOfCode/parse"
 )
 
-func main() {
-	scanner := bufio.NewScanner(os.Stdin)
-	sum := 0
-	for scanner.Scan() {
-		line := scanner.Text()
-		firstDigit := ""
-		for _, r := range line {
-			if unicode.IsDigit(r) {
-				firstDigit = string(r)
-				break
-			}
+// the spelled out digits are replaced in this order, keeping the first and
+// last letters so that overlapping words like "eightwo" still match both
+var spelledDigits = []struct{ word, replacement string }{
+	{"one", "on1ne"},
+	{"two", "tw2wo"},
+	{"three", "thre3hree"},
+	{"four", "fou4our"},
+	{"five", "fiv5ive"},
+	{"six", "si6ix"},
+	{"seven", "seve7even"},
+	{"eight", "eigh8ight"},
+	{"nine", "nin9ine"},
+}
+
+func spellOutDigits(line string) string {
+	for _, d := range spelledDigits {
+		line = strings.ReplaceAll(line, d.word, d.replacement)
+	}
+	return line
+}
+
+func calibrationValue(line string) int {
+	firstDigit := ""
+	for _, r := range line {
+		if unicode.IsDigit(r) {
+			firstDigit = string(r)
+			break
+		}
+	}
+	lastDigit := ""
+	for i := len(line) - 1; i >= 0; i-- {
+		if unicode.IsDigit(rune(line[i])) {
+			lastDigit = string(line[i])
+			break
 		}
-		lastDigit := ""
-		for i := len(line) - 1; i >= 0; i-- {
-			if unicode.IsDigit(rune(line[i])) {
-				lastDigit = string(line[i])
-				break
+	}
+	calibrationValue, _ := strconv.Atoi(firstDigit + lastDigit)
+	return calibrationValue
+}
+
+// readDocument reads the lines of the calibration document, which in
+// strict mode may only have lower case letters and digits.
+func readDocument(r io.Reader) ([]string, error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	text := make([]string, len(lines))
+	for i, line := range lines {
+		if parse.Strict {
+			if err := line.Only("abcdefghijklmnopqrstuvwxyz0123456789"); err != nil {
+				return nil, err
 			}
 		}
-		calibrationValue, _ := strconv.Atoi(firstDigit + lastDigit)
-		sum += calibrationValue
+		text[i] = line.Text
+	}
+	return text, nil
+}
+
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	lines, err := readDocument(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	sum := 0
+	spelledSum := 0
+	for _, line := range lines {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		sum += calibrationValue(line)
+		spelledSum += calibrationValue(spellOutDigits(line))
 	}
-	fmt.Println(sum)
+	return sum, spelledSum, nil
 }
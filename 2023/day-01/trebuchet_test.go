@@ -0,0 +1,27 @@
+package day01
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+func writeDocument(lines []string) string {
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func FuzzReadDocument(f *testing.F) {
+	parsetest.Seed(f,
+		"two1nine\neightwothree\nabcone2threexyz\nxtwone3four\n",
+		"1abc2\n\nA-7\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, readDocument, writeDocument)
+	})
+}
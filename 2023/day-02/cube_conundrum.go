@@ -1,68 +1,99 @@
-package main
+package day02
 
 import (
-	"bufio"
-	"errors"
-	"fmt"
-	"os"
-	"regexp"
-	"strconv"
+	"context"
+	"io"
 	"strings"
+
+	"github.com/havill/AdventOfCode/parse"
 )
 
-func splitByColon(s string) (string, string, error) {
-	parts := strings.SplitN(s, ":", 2)
-	if len(parts) != 2 {
-		return "", "", errors.New("input string does not contain a colon")
+// cubes reads a handful of cubes such as " 3 blue, 4 red" into counts by
+// colour.
+func cubes(set parse.Line) (map[string]int, error) {
+	counts := make(map[string]int)
+	for _, handful := range set.Split(",") {
+		count, color, err := handful.Trim().Cut(" ")
+		if err != nil {
+			return nil, err
+		}
+		n, err := count.Int()
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSpace(color.Text)
+		if parse.Strict && name != "red" && name != "green" && name != "blue" {
+			return nil, color.Trim().Errorf("unknown colour %q", name)
+		}
+		counts[name] += n
 	}
-	return parts[0], parts[1], nil
+	return counts, nil
 }
 
-func extractInt(s string) (int, error) {
-	re := regexp.MustCompile(`\d+`)
-	match := re.FindStringSubmatch(s)
-	if match == nil {
-		return 0, errors.New("no integer found in string")
-	}
-	return strconv.Atoi(match[0])
+// game is one line of the record: its number and the cubes of each colour
+// in every handful that was shown.
+type game struct {
+	id   int
+	sets []map[string]int
 }
 
-func extractIntBeforeColor(set string, color string) (int, error) {
-	colorIndex := strings.Index(set, color)
-	if colorIndex == -1 {
-		return 0, errors.New("color not found in set")
+func parseGames(r io.Reader) ([]game, error) {
+	var games []game
+
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
 	}
-	commaIndex := strings.LastIndex(set[:colorIndex], ",")
-	if commaIndex == -1 {
-		return extractInt(set)
+	for _, line := range lines {
+		if line.Blank() {
+			continue
+		}
+		label, revealed, err := line.Cut(":")
+		if err != nil {
+			return nil, err
+		}
+		_, number, err := label.Trim().Cut(" ")
+		if err != nil {
+			return nil, err
+		}
+		var g game
+		if g.id, err = number.Int(); err != nil {
+			return nil, err
+		}
+		for _, set := range revealed.Split(";") {
+			counts, err := cubes(set)
+			if err != nil {
+				return nil, err
+			}
+			g.sets = append(g.sets, counts)
+		}
+		games = append(games, g)
 	}
-	substring := set[commaIndex+1 : colorIndex]
-	return extractInt(substring)
+	return games, nil
 }
 
-func main() {
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
 	sum := 0
 	power_sum := 0
 	max_red := 12
 	max_green := 13
 	max_blue := 14
 
-	scanner := bufio.NewScanner(os.Stdin)
-
-	for scanner.Scan() {
-		line := scanner.Text()
+	games, err := parseGames(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, g := range games {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
 		impossible := false
 		min_red := 0
 		min_green := 0
 		min_blue := 0
 
-		game, revealed, _ := splitByColon(line)
-		id, _ := extractInt(game)
-		tokens := strings.Split(revealed, ";")
-		for _, set := range tokens {
-			red, _ := extractIntBeforeColor(set, "red")
-			green, _ := extractIntBeforeColor(set, "green")
-			blue, _ := extractIntBeforeColor(set, "blue")
+		for _, counts := range g.sets {
+			red, green, blue := counts["red"], counts["green"], counts["blue"]
 			if red > max_red || green > max_green || blue > max_blue {
 				impossible = true
 			}
@@ -78,10 +109,9 @@ func main() {
 		}
 		power := min_red * min_green * min_blue
 		if !impossible {
-			sum += id
+			sum += g.id
 		}
 		power_sum += power
 	}
-	fmt.Println(sum)
-	fmt.Println(power_sum)
+	return sum, power_sum, nil
 }
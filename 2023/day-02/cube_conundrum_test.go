@@ -0,0 +1,42 @@
+package day02
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+func writeGames(games []game) string {
+	var b strings.Builder
+	for _, g := range games {
+		fmt.Fprintf(&b, "Game %d:", g.id)
+		for i, set := range g.sets {
+			if i > 0 {
+				b.WriteString(";")
+			}
+			for j, color := range slices.Sorted(maps.Keys(set)) {
+				if j > 0 {
+					b.WriteString(",")
+				}
+				fmt.Fprintf(&b, " %d %s", set[color], color)
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func FuzzParseGames(f *testing.F) {
+	parsetest.Seed(f,
+		"Game 1: 3 blue, 4 red; 1 red, 2 green, 6 blue; 2 green\n",
+		"Game 2: 1 blue, 2 green; 3 green, 4 blue, 1 red; 1 green, 1 blue\n\nGame 3: 8 green\n",
+		"Game 4:\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, parseGames, writeGames)
+	})
+}
@@ -0,0 +1,32 @@
+package day02
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes a record of size games, each of a few handfuls of cubes.
+func Generate(rng *rand.Rand, size int) []byte {
+	var b bytes.Buffer
+	for id := 1; id <= size; id++ {
+		fmt.Fprintf(&b, "Game %d:", id)
+		for i := range gen.Between(rng, 1, 6) {
+			if i > 0 {
+				b.WriteString(";")
+			}
+			colors := []string{"red", "green", "blue"}
+			rng.Shuffle(len(colors), func(i, j int) { colors[i], colors[j] = colors[j], colors[i] })
+			for j, color := range colors[:gen.Between(rng, 1, 3)] {
+				if j > 0 {
+					b.WriteString(",")
+				}
+				fmt.Fprintf(&b, " %d %s", gen.Between(rng, 1, 20), color)
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
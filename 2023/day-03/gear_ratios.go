@@ -1,13 +1,24 @@
-package main
+package day03
 
 import (
-	"bufio"
+	"context"
 	"fmt"
 	"io"
-	"os"
 	"strings"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/grid"
+)
+
+var (
+	parseLog  = aoc.Logger("parser")
+	searchLog = aoc.Logger("search")
 )
 
+// schematicRunes are the digits, the dots and every symbol that could be one
+// of the parts.
+const schematicRunes = ".0123456789!\"#$%&'()*+,-/:;<=>?@[\\]^_`{|}~"
+
 type NumberPosition struct {
 	StartXPosition int
 	EndXPosition   int
@@ -15,11 +26,6 @@ type NumberPosition struct {
 	Number         int
 }
 
-var positions []NumberPosition
-
-var maxXPosition int = 1000
-var maxYPosition int = 1000
-
 func findIntegers(line string, y int) []NumberPosition {
 	var positions []NumberPosition
 	var startPos, endPos, number int
@@ -33,49 +39,35 @@ func findIntegers(line string, y int) []NumberPosition {
 			reader.ReadByte() // skip non-digit character
 			continue
 		}
-		endPos = int(reader.Size()) - int(reader.Len())        // endPos is the char AFTER the last digit, so no +1 here
-		startPos = endPos - len(fmt.Sprintf("%d", number)) + 1 // +1 because we want a 1-based index
+		endPos = int(reader.Size()) - int(reader.Len()) - 1 // reader is at the char AFTER the last digit
+		startPos = endPos - len(fmt.Sprintf("%d", number)) + 1
 		positions = append(positions, NumberPosition{StartXPosition: startPos, EndXPosition: endPos, StartYPosition: y, Number: number})
 	}
 	return positions
 }
 
-func findSymbolsInLine(s string, arr []bool) {
-	for i, ch := range s {
-		if ch != '.' && (ch < '0' || ch > '9') {
-			arr[i+1] = true
-		}
-	}
+func isSymbol(_ grid.Point, ch rune) bool {
+	return ch != '.' && (ch < '0' || ch > '9')
 }
 
-func findDigitsInLine(s string, arr []bool) {
-	for i, ch := range s {
-		if ch >= '0' && ch <= '9' {
-			arr[i+1] = true
-		}
-	}
+func isDigit(_ grid.Point, ch rune) bool {
+	return ch >= '0' && ch <= '9'
 }
 
-func findGearsInLine(s string, arr []bool) {
-	for i, ch := range s {
-		if ch == '*' {
-			arr[i+1] = true
-		}
-	}
+func isGear(_ grid.Point, ch rune) bool {
+	return ch == '*'
 }
 
-func CellHasNeighborSymbols(matrix [][]bool, x int, y int) bool {
-	for b := -1; b <= 1; b++ {
-		for a := -1; a <= 1; a++ {
-			if matrix[y+b][x+a] {
-				return true
-			}
+func CellHasNeighborSymbols(matrix *grid.Grid[bool], x int, y int) bool {
+	for _, symbol := range matrix.Neighbors8(grid.Point{X: x, Y: y}) {
+		if symbol {
+			return true
 		}
 	}
 	return false
 }
 
-func NumberHasNeighborSymbols(matrix [][]bool, n NumberPosition) int {
+func NumberHasNeighborSymbols(matrix *grid.Grid[bool], n NumberPosition) int {
 	for i := n.StartXPosition; i <= n.EndXPosition; i++ {
 		if CellHasNeighborSymbols(matrix, i, n.StartYPosition) {
 			return n.Number
@@ -84,13 +76,15 @@ func NumberHasNeighborSymbols(matrix [][]bool, n NumberPosition) int {
 	return 0
 }
 
-func addAllCellsWithSymbolNeighbors(matrix [][]bool, positions []NumberPosition) int {
+func addAllCellsWithSymbolNeighbors(matrix *grid.Grid[bool], positions []NumberPosition) int {
 	sum := 0
 
 	for _, pos := range positions {
 		value := NumberHasNeighborSymbols(matrix, pos)
 		if value > 0 {
-			// fmt.Printf("%4d at Ln %3d, Col %3d is a part number\n", pos.Number, pos.StartYPosition, pos.StartXPosition)
+			if aoc.Tracing() {
+				aoc.Trace(searchLog, "part number", "number", pos.Number, "line", pos.StartYPosition+1, "column", pos.StartXPosition+1)
+			}
 			sum += value
 		}
 	}
@@ -103,7 +97,7 @@ func removeSigns(s string) string {
 	return s
 }
 
-func getPartsSurroundingGear(positions []NumberPosition, digits [][]bool, x int, y int) (int, int) {
+func getPartsSurroundingGear(positions []NumberPosition, digits *grid.Grid[bool], x int, y int) (int, int) {
 	factor1, factor2 := 0, 0
 
 	for b := y - 1; b <= y+1; b++ {
@@ -114,16 +108,13 @@ func getPartsSurroundingGear(positions []NumberPosition, digits [][]bool, x int,
 			for _, pos := range positions {
 				if pos.StartXPosition <= a && pos.EndXPosition >= a && pos.StartYPosition == b {
 					if factor1 == 0 {
-						// fmt.Println("Found factor 1 at Ln ", b, " Col ", a)
 						factor1 = pos.Number
 					} else if factor2 == 0 {
-						// fmt.Println("Found factor 2 at Ln ", b, " Col ", a)
 						factor2 = pos.Number
 					}
-					for digits[b][a] {
+					for digits.At(grid.Point{X: a, Y: b}) {
 						a++ // skip the digit
 					}
-					// fmt.Println("Skipping to Ln ", b, " Col ", a)
 				}
 			}
 		}
@@ -131,96 +122,67 @@ func getPartsSurroundingGear(positions []NumberPosition, digits [][]bool, x int,
 	return factor1, factor2
 }
 
-func scanGearsForParts(gears, digits [][]bool, maxx int, maxy int) int {
+func scanGearsForParts(positions []NumberPosition, gears, digits *grid.Grid[bool]) int {
 	sum := 0
-	y := 0
-	for _, row := range gears {
-		x := 0
-		for _, gear := range row {
-			if gear {
-				factor1, factor2 := getPartsSurroundingGear(positions, digits, x, y)
-				// fmt.Print("Gear at ", x, ",", y, " has parts ", factor1, " and ", factor2, "\n")
-				sum += factor1 * factor2
-			}
-			x++
-			if x > maxx {
-				break
+	for p, gear := range gears.All() {
+		if gear {
+			factor1, factor2 := getPartsSurroundingGear(positions, digits, p.X, p.Y)
+			if aoc.Tracing() {
+				aoc.Trace(searchLog, "gear", "x", p.X, "y", p.Y, "parts", []int{factor1, factor2})
 			}
-		}
-		y++
-		if y > maxy {
-			break
+			sum += factor1 * factor2
 		}
 	}
 	return sum
 }
 
-func debugNumbersAndPositions(positions []NumberPosition) {
+func logNumbersAndPositions(positions []NumberPosition) {
 	for _, pos := range positions {
-		fmt.Printf("Start: %d, End: %d, Y: %d, Number: %d\n", pos.StartXPosition, pos.EndXPosition, pos.StartYPosition, pos.Number)
+		aoc.Trace(parseLog, "number", "start", pos.StartXPosition, "end", pos.EndXPosition, "y", pos.StartYPosition, "number", pos.Number)
 	}
 }
 
-func debugMatrix(matrix [][]bool, maxx int, maxy int) {
-	y := 0
-	for _, row := range matrix {
-		fmt.Printf("%03d: ", y)
-		x := 0
-		for _, symbol := range row {
-			if symbol {
-				fmt.Printf("#")
-			} else {
-				fmt.Printf(".")
-			}
-			x++
-			if x > maxx {
-				break
-			}
+func logMatrix(name string, matrix *grid.Grid[bool]) {
+	parseLog.Debug(name + "\n" + grid.Map(matrix, func(_ grid.Point, symbol bool) rune {
+		if symbol {
+			return '#'
 		}
-		fmt.Println()
-		y++
-		if y > maxy {
-			break
-		}
-	}
+		return '.'
+	}).String())
 }
 
-func main() {
-	var symbols, gears, digits [][]bool
-
-	y := 0
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	var positions []NumberPosition
 
-	// Create three 2D arrays of booleans
-	symbols = make([][]bool, maxYPosition)
-	for i := range symbols {
-		symbols[i] = make([]bool, maxXPosition)
+	schematic, err := grid.Read(r, grid.Expect(schematicRunes, grid.Runes))
+	if err != nil {
+		return nil, nil, err
 	}
-	gears = make([][]bool, maxYPosition)
-	for i := range gears {
-		gears[i] = make([]bool, maxXPosition)
+	for y := 0; y < schematic.Height(); y++ {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		line := removeSigns(string(schematic.Row(y)))
+		positions = append(positions, findIntegers(line, y)...)
 	}
-	digits = make([][]bool, maxYPosition)
-	for i := range digits {
-		digits[i] = make([]bool, maxXPosition)
+	symbols := grid.Map(schematic, isSymbol)
+	gears := grid.Map(schematic, isGear)
+	digits := grid.Map(schematic, isDigit)
+	aoc.Parsed(ctx)
+	if aoc.Tracing() {
+		logNumbersAndPositions(positions)
 	}
-
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		line := removeSigns(scanner.Text())
-		y++ // y is 1-based
-		positions = append(positions, findIntegers(line, y)...)
-		findSymbolsInLine(line, symbols[y])
-		findGearsInLine(line, gears[y])
-		findDigitsInLine(line, digits[y])
+	if aoc.Debugging() {
+		logMatrix("symbols", symbols)
+		logMatrix("gears", gears)
+		logMatrix("digits", digits)
 	}
-	// debugNumbersAndPositions(positions)
-	// debugMatrix(symbols, 140, 140)
-	// debugMatrix(gears, 10, 10)
-	// debugMatrix(digits, 10, 10)
-
-	total := addAllCellsWithSymbolNeighbors(symbols, positions)
-	fmt.Println(total)
 
-	total = scanGearsForParts(gears, digits, maxXPosition, maxYPosition)
-	fmt.Println(total)
+	part1 := addAllCellsWithSymbolNeighbors(symbols, positions)
+	aoc.Part1Done(ctx)
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	part2 := scanGearsForParts(positions, gears, digits)
+	return part1, part2, nil
 }
@@ -0,0 +1,31 @@
+package day03
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"strconv"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes a size by size engine schematic: numbers of up to three
+// digits, kept apart by dots, with symbols and gears scattered among them.
+func Generate(rng *rand.Rand, size int) []byte {
+	width := gen.Clamp(size, 3, 140)
+	var b bytes.Buffer
+	for range size {
+		row := bytes.Repeat([]byte{'.'}, width)
+		for x := 0; x < width; x++ {
+			switch n := rng.IntN(10); {
+			case n < 2:
+				number := strconv.Itoa(gen.Between(rng, 1, 999))
+				x += copy(row[x:], number)
+			case n < 3:
+				row[x] = gen.Pick(rng, "****#$%&+-/=@")
+			}
+		}
+		b.Write(row)
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,54 @@
+package day04
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// most is as many scratchcards as the generated pile may end up as once
+// every copy has been won, since part 2 deals each copy out one at a time.
+const most = 20000
+
+// Generate makes a pile of size scratchcards, of five winning numbers and
+// eight numbers each, where no card wins copies past the end of the pile.
+func Generate(rng *rand.Rand, size int) []byte {
+	copies := make([]int, size)
+	for i := range copies {
+		copies[i] = 1
+	}
+	total := size
+
+	var b bytes.Buffer
+	for i := range size {
+		matches := 0
+		if rng.IntN(2) == 0 {
+			matches = min(gen.Between(rng, 1, 4), size-1-i)
+		}
+		for matches > 0 && total+copies[i]*matches > most {
+			matches--
+		}
+		for j := 1; j <= matches; j++ {
+			copies[i+j] += copies[i]
+		}
+		total += copies[i] * matches
+
+		numbers := rng.Perm(99)[:13]
+		winning, possessed := numbers[:5], append([]int{}, numbers[5:]...)
+		copy(possessed, winning[:matches])
+		rng.Shuffle(len(possessed), func(i, j int) { possessed[i], possessed[j] = possessed[j], possessed[i] })
+
+		fmt.Fprintf(&b, "Card %3d:", i+1)
+		for _, n := range winning {
+			fmt.Fprintf(&b, " %2d", n+1)
+		}
+		b.WriteString(" |")
+		for _, n := range possessed {
+			fmt.Fprintf(&b, " %2d", n+1)
+		}
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
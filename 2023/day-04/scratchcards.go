@@ -1,15 +1,15 @@
-package main
+package day04
 
 import (
-	"bufio"
-	"errors"
-	"fmt"
-	"os"
-	"regexp"
-	"strconv"
-	"strings"
+	"context"
+	"io"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/parse"
 )
 
+var log = aoc.Logger("simulation")
+
 type Card struct {
 	Number    int
 	Winning   []int
@@ -54,31 +54,6 @@ func TotalCardPointsAndMatches(c *Card) (int, int) {
 	return total, matches
 }
 
-func splitByColon(s string) (string, string, error) {
-	parts := strings.SplitN(s, ":", 2)
-	if len(parts) != 2 {
-		return "", "", errors.New("input string does not contain a colon")
-	}
-	return parts[0], parts[1], nil
-}
-
-func splitByVerticalBar(s string) (string, string, error) {
-	parts := strings.SplitN(s, "|", 2)
-	if len(parts) != 2 {
-		return "", "", errors.New("input string does not contain a vertical bar")
-	}
-	return parts[0], parts[1], nil
-}
-
-func extractInt(s string) (int, error) {
-	re := regexp.MustCompile(`\d+`)
-	match := re.FindStringSubmatch(s)
-	if match == nil {
-		return 0, errors.New("no integer found in string")
-	}
-	return strconv.Atoi(match[0])
-}
-
 func (d *Deck) AddCard(c Card) {
 	d.Cards = append(d.Cards, c)
 }
@@ -94,55 +69,74 @@ func (d *Deck) CopyCardsToEndofDeck(Number int, copies int) {
 	for i := start + 1; copies > 0; i++ {
 		d.Cards = append(d.Cards, d.Cards[i])
 		copies--
-		// fmt.Println("Copying card ", d.Cards[i].Number, " to end of deck")
-	}
-}
-
-func ExtractIntsFromString(s string) []int {
-	re := regexp.MustCompile(`\d+`)
-	match := re.FindAllString(s, -1)
-	if match == nil {
-		return nil
 	}
-	ints := make([]int, len(match))
-	for i, num := range match {
-		ints[i], _ = strconv.Atoi(num)
-	}
-	return ints
 }
 
-func main() {
+func parseCards(r io.Reader) (Deck, error) {
 	var d Deck
 	var c Card
-	total := 0
 
 	d.Cards = make([]Card, 0)
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		line := scanner.Text()
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return d, err
+	}
+	for _, line := range lines {
+		if line.Blank() {
+			continue
+		}
+		left, right, err := line.Cut(":")
+		if err != nil {
+			return d, err
+		}
+		winning, possessed, err := right.Pair("|")
+		if err != nil {
+			return d, err
+		}
+		label := left.Fields()
+		if len(label) != 2 {
+			return d, left.Errorf("expected \"Card <number>\"")
+		}
+		if c.Number, err = label[1].Int(); err != nil {
+			return d, err
+		}
+		if c.Winning, err = winning.Ints(); err != nil {
+			return d, err
+		}
+		if c.Possessed, err = possessed.Ints(); err != nil {
+			return d, err
+		}
+		d.AddCard(c)
+	}
+	return d, nil
+}
 
-		left, right, _ := splitByColon(line)
-		winning, possessed, _ := splitByVerticalBar(right)
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	total := 0
 
-		c.Number, _ = extractInt(left)
-		c.Winning = ExtractIntsFromString(winning)
-		c.Possessed = ExtractIntsFromString(possessed)
-		d.AddCard(c)
+	d, err := parseCards(r)
+	if err != nil {
+		return nil, nil, err
 	}
+	aoc.Parsed(ctx)
 	for _, card := range d.Cards {
 		points, _ := TotalCardPointsAndMatches(&card)
 		total += points
 
 	}
-	fmt.Println(total)
+	aoc.Part1Done(ctx)
 	i := 0
 	for i < len(d.Cards) {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
 		card := d.Cards[i]
 		_, matches := TotalCardPointsAndMatches(&card)
-		// fmt.Println("Card ", card.Number, " has ", matches, " matches")
 		d.CopyCardsToEndofDeck(card.Number, matches)
 		i++
-		// fmt.Println("we now have ", len(d.Cards), " cards")
+		if aoc.Tracing() {
+			aoc.Trace(log, "copied", "card", card.Number, "matches", matches, "deck", len(d.Cards))
+		}
 	}
-	fmt.Println(len(d.Cards))
+	return total, len(d.Cards), nil
 }
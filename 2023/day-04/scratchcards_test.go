@@ -0,0 +1,31 @@
+package day04
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+func writeCards(d Deck) string {
+	var b strings.Builder
+	for _, c := range d.Cards {
+		fmt.Fprintf(&b, "Card %d: %s | %s\n", c.Number, numbers(c.Winning), numbers(c.Possessed))
+	}
+	return b.String()
+}
+
+func numbers(list []int) string {
+	return strings.Trim(fmt.Sprint(list), "[]")
+}
+
+func FuzzParseCards(f *testing.F) {
+	parsetest.Seed(f,
+		"Card 1: 41 48 83 86 17 | 83 86  6 31 17  9 48 53\n",
+		"Card   2: 13 32 20 16 61 | 61 30 68 82 17 32 24 19\n\nCard 3: |\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, parseCards, writeCards)
+	})
+}
@@ -0,0 +1,43 @@
+package day05
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes an almanac with about size lines of ranges across its
+// maps. Each map moves blocks of numbers around without overlapping them.
+// The seed ranges are kept short, so that trying every seed in them can
+// check part 2.
+func Generate(rng *rand.Rand, size int) []byte {
+	blocks := max(size/len(mapNames), 1)
+	span := 1000 * blocks
+
+	var b bytes.Buffer
+	b.WriteString("seeds:")
+	for range gen.Between(rng, 1, 4) {
+		fmt.Fprintf(&b, " %d %d", rng.IntN(span), gen.Between(rng, 1, 1000))
+	}
+	b.WriteString("\n")
+
+	for _, name := range mapNames {
+		fmt.Fprintf(&b, "\n%s map:\n", name)
+		type block struct{ source, length int }
+		var list []block
+		for at := 0; len(list) < blocks; {
+			at += rng.IntN(span / blocks / 4)
+			length := gen.Between(rng, 1, span/blocks/2)
+			list = append(list, block{at, length})
+			at += length
+		}
+		dest := rng.IntN(span)
+		for _, i := range rng.Perm(len(list)) {
+			fmt.Fprintf(&b, "%d %d %d\n", dest, list[i].source, list[i].length)
+			dest += list[i].length
+		}
+	}
+	return b.Bytes()
+}
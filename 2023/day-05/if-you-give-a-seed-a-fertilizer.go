@@ -1,13 +1,19 @@
-package main
+package day05
 
 import (
-	"bufio"
-	"errors"
-	"fmt"
+	"context"
+	"io"
 	"math"
-	"os"
-	"strconv"
+	"slices"
 	"strings"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/parse"
+)
+
+var (
+	parseLog  = aoc.Logger("parser")
+	searchLog = aoc.Logger("search")
 )
 
 type State int
@@ -32,31 +38,16 @@ const (
 	Location
 )
 
-func splitByColon(s string) (string, string, error) {
-	parts := strings.SplitN(s, ":", 2)
-	if len(parts) != 2 {
-		return "", "", errors.New("input string does not contain a colon")
-	}
-	return parts[0], parts[1], nil
-}
-
 func mapSrcToDest(src int, ranges MappingList) int {
 	dest := src
-
-	//fmt.Println("src = ", src)
-	//fmt.Println("ranges = ", ranges)
-
 	for _, i := range ranges {
 		lo := i.Source
 		hi := i.Source + i.Length
-		// fmt.Println("lo = ", lo, ", hi = ", hi)
 		if src >= lo && src < hi {
-			// fmt.Println("src ", src, " is in between ", lo, " and ", hi-1)
 			dest = i.Destination + src - lo
 			break
 		}
 	}
-	//fmt.Fprintln(os.Stderr, "src = ", src, ", dest = ", dest)
 	return dest
 }
 
@@ -71,132 +62,214 @@ func mapSeedToLocation(a int, s, f, w, l, t, h, z MappingList) int {
 	return location
 }
 
-func main() {
+// mapNames are the maps of the almanac, in the order a seed goes through
+// them.
+var mapNames = [...]string{
+	"seed-to-soil", "soil-to-fertilizer", "fertilizer-to-water", "water-to-light",
+	"light-to-temperature", "temperature-to-humidity", "humidity-to-location",
+}
+
+// Almanac is the seeds to be planted and the maps from each category to the
+// next, indexed by the State they lead to less one.
+type Almanac struct {
+	Seeds SeedList
+	Maps  [len(mapNames)]MappingList
+}
+
+func parseAlmanac(r io.Reader) (Almanac, error) {
+	var a Almanac
 	var parserState State = Seeds
+	var seedsLine parse.Line // the last line of seeds, if there is one
 
-	var toBePlanted SeedList
-	var soilMaps []Category
-	var fertilizerMaps []Category
-	var waterMaps []Category
-	var lightMaps []Category
-	var temperatureMaps []Category
-	var humidityMaps []Category
-	var locationMaps []Category
-
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		line := scanner.Text()
-		line = strings.TrimSpace(line)
-		if len(line) == 0 {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return a, err
+	}
+	for _, line := range lines {
+		line = line.Trim()
+		if line.Blank() {
 			continue // skip blank lines
 		}
-		// fmt.Fprintf(os.Stderr, "line: %s\n", line)
-		left, right, error := splitByColon(line)
-		// fmt.Fprintln(os.Stderr, "left:", left)
-		// fmt.Fprintln(os.Stderr, "right:", right)
-		// fmt.Fprintln(os.Stderr, "error:", error)
-		if error == nil {
+		if strings.Contains(line.Text, ":") {
+			label, right, _ := line.Cut(":")
+			left := strings.TrimSpace(label.Text)
 			if strings.EqualFold(left, "seeds") {
 				parserState = Seeds
-				str := right
-				fields := strings.Fields(str)
-
-				for _, field := range fields {
-					num, err := strconv.Atoi(field)
-					if err != nil {
-						fmt.Fprintln(os.Stderr, "Error converting string to integer: ", err)
-						os.Exit(1)
-					}
-					toBePlanted = append(toBePlanted, num)
-					//fmt.Fprintln(os.Stderr, "added seed: ", num)
+				seeds, err := right.Ints()
+				if err != nil {
+					return a, err
 				}
-			} else if strings.EqualFold(left, "seed-to-soil map") {
-				parserState = Soil
-			} else if strings.EqualFold(left, "soil-to-fertilizer map") {
-				parserState = Fertilizer
-			} else if strings.EqualFold(left, "fertilizer-to-water map") {
-				parserState = Water
-			} else if strings.EqualFold(left, "water-to-light map") {
-				parserState = Light
-			} else if strings.EqualFold(left, "light-to-temperature map") {
-				parserState = Temperature
-			} else if strings.EqualFold(left, "temperature-to-humidity map") {
-				parserState = Humidity
-			} else if strings.EqualFold(left, "humidity-to-location map") {
-				parserState = Location
+				a.Seeds = append(a.Seeds, seeds...)
+				seedsLine = line
+			} else if i := slices.IndexFunc(mapNames[:], func(name string) bool {
+				return strings.EqualFold(left, name+" map")
+			}); i >= 0 {
+				parserState = State(i) + Soil
 			} else {
-				fmt.Fprintln(os.Stderr, "Unknown map")
-				os.Exit(1)
+				return a, label.Errorf("unknown map: %s", left)
 			}
-			// fmt.Fprintln(os.Stderr, "parserState = ", parserState)
+			parseLog.Debug("map", "name", left, "line", line.Number)
 			continue
 		}
-		var categoryMap Category
+		if parserState == Seeds {
+			return a, line.Errorf("expected a map before the ranges")
+		}
+		nums, err := line.Ints()
+		if err != nil {
+			return a, err
+		}
+		if len(nums) != 3 {
+			return a, line.Errorf("expected destination, source and length, found %d numbers", len(nums))
+		}
+		categoryMap := Category{Destination: nums[0], Source: nums[1], Length: nums[2]}
+		if aoc.Tracing() {
+			aoc.Trace(parseLog, "range", "state", parserState, "category", categoryMap)
+		}
+		a.Maps[parserState-Soil] = append(a.Maps[parserState-Soil], categoryMap)
+	}
 
-		if parserState != Seeds {
-			_, err := fmt.Sscanf(line, "%d %d %d", &categoryMap.Destination, &categoryMap.Source, &categoryMap.Length)
-			if err != nil {
-				fmt.Fprintln(os.Stderr, "Error scanning integers: ", err)
-				os.Exit(1)
+	if len(a.Seeds)%2 != 0 {
+		return a, seedsLine.ErrorAt(len(seedsLine.Text), "%d seed numbers cannot all be paired into ranges", len(a.Seeds))
+	}
+	if parse.Strict {
+		if len(a.Seeds) == 0 {
+			if seedsLine.Number == 0 {
+				return a, parse.End(lines).Errorf("no seeds")
 			}
-			switch parserState {
-			case Soil:
-				soilMaps = append(soilMaps, categoryMap)
-				//fmt.Fprintln(os.Stderr, "added Soil map: ", categoryMap)
-			case Fertilizer:
-				fertilizerMaps = append(fertilizerMaps, categoryMap)
-				//fmt.Fprintln(os.Stderr, "added Fertilizer map: ", categoryMap)
-			case Water:
-				waterMaps = append(waterMaps, categoryMap)
-				//fmt.Fprintln(os.Stderr, "added Water map: ", categoryMap)
-			case Light:
-				lightMaps = append(lightMaps, categoryMap)
-				//fmt.Fprintln(os.Stderr, "added Light map: ", categoryMap)
-			case Temperature:
-				temperatureMaps = append(temperatureMaps, categoryMap)
-				//fmt.Fprintln(os.Stderr, "added Temperature map: ", categoryMap)
-			case Humidity:
-				humidityMaps = append(humidityMaps, categoryMap)
-				//fmt.Fprintln(os.Stderr, "added Humidity map: ", categoryMap)
-			case Location:
-				locationMaps = append(locationMaps, categoryMap)
-				//fmt.Fprintln(os.Stderr, "added Location map: ", categoryMap)
-			default:
-				fmt.Fprintln(os.Stderr, "Unknown State")
-				os.Exit(1)
+			return a, seedsLine.ErrorAt(len(seedsLine.Text), "no seeds")
+		}
+		for i, m := range a.Maps {
+			if len(m) == 0 {
+				return a, parse.End(lines).Errorf("no %s map", mapNames[i])
 			}
 		}
 	}
+	return a, nil
+}
 
-	//fmt.Println(mapSeedToLocation(79, soilMaps, fertilizerMaps, waterMaps, lightMaps, temperatureMaps, humidityMaps, locationMaps))
-	//fmt.Println(mapSeedToLocation(14, soilMaps, fertilizerMaps, waterMaps, lightMaps, temperatureMaps, humidityMaps, locationMaps))
-	//fmt.Println(mapSeedToLocation(55, soilMaps, fertilizerMaps, waterMaps, lightMaps, temperatureMaps, humidityMaps, locationMaps))
-	//fmt.Println(mapSeedToLocation(13, soilMaps, fertilizerMaps, waterMaps, lightMaps, temperatureMaps, humidityMaps, locationMaps))
-
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	almanac, err := parseAlmanac(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	toBePlanted := almanac.Seeds
+	soilMaps, fertilizerMaps, waterMaps, lightMaps := almanac.Maps[0], almanac.Maps[1], almanac.Maps[2], almanac.Maps[3]
+	temperatureMaps, humidityMaps, locationMaps := almanac.Maps[4], almanac.Maps[5], almanac.Maps[6]
+	aoc.Parsed(ctx)
 	var lowest int = math.MaxInt
 
 	for _, num := range toBePlanted {
-
-		//fmt.Println("Index:", i, "Number:", num)
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
 		x := mapSeedToLocation(num, soilMaps, fertilizerMaps, waterMaps, lightMaps, temperatureMaps, humidityMaps, locationMaps)
+		if aoc.Tracing() {
+			aoc.Trace(searchLog, "seed", "seed", num, "location", x)
+		}
 		if x < lowest {
 			lowest = x
 		}
 	}
-	fmt.Println(lowest)
-
-	lowest = math.MaxInt
-	for i := 0; i < len(toBePlanted); i += 2 {
-		lo := toBePlanted[i]
-		hi := toBePlanted[i] + toBePlanted[i+1]
-		// fmt.Println("lo = ", lo, ", hi = ", hi)
-		for j := lo; j < hi; j++ {
-			x := mapSeedToLocation(j, soilMaps, fertilizerMaps, waterMaps, lightMaps, temperatureMaps, humidityMaps, locationMaps)
-			if x < lowest {
-				lowest = x
+	part1 := lowest
+	aoc.Part1Done(ctx)
+	return part1, lowestInRanges(almanac), nil
+}
+
+// Reference answers part 2 by trying every seed. Part 1 tries every seed
+// already, so there is nothing slower to check it against.
+func Reference(ctx context.Context, r io.Reader) (any, any, error) {
+	almanac, err := parseAlmanac(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	seeds := 0
+	for i := 1; i < len(almanac.Seeds); i += 2 {
+		seeds += almanac.Seeds[i]
+	}
+	lowest, err := lowestOneByOne(ctx, almanac, aoc.NewProgress(ctx, "seeds", seeds))
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, lowest, nil
+}
+
+// lowestOneByOne is the lowest location of any seed in the ranges, found by
+// trying every seed. It is too slow for a real almanac, but it is the
+// simplest way of getting the answer right, so it checks lowestInRanges.
+func lowestOneByOne(ctx context.Context, almanac Almanac, progress *aoc.Progress) (int, error) {
+	const batch = 1 << 16 // seeds between looking at ctx
+	lowest := math.MaxInt
+	m := almanac.Maps
+	for i := 0; i < len(almanac.Seeds); i += 2 {
+		lo := almanac.Seeds[i]
+		hi := almanac.Seeds[i] + almanac.Seeds[i+1]
+		searchLog.Debug("seed range", "lo", lo, "hi", hi, "lowest", lowest)
+		for from := lo; from < hi; from += batch {
+			if err := ctx.Err(); err != nil {
+				return 0, err
 			}
+			to := min(from+batch, hi)
+			for j := from; j < to; j++ {
+				x := mapSeedToLocation(j, m[0], m[1], m[2], m[3], m[4], m[5], m[6])
+				if x < lowest {
+					lowest = x
+				}
+			}
+			progress.Add(to - from)
 		}
 	}
-	fmt.Println(lowest)
+	progress.Done()
+	return lowest, nil
+}
 
+// span is the numbers from lo up to but not including hi.
+type span struct {
+	lo, hi int
+}
+
+// mapSpans maps every number in spans through ranges at once, cutting a
+// span wherever it crosses the edge of a range. As in mapSrcToDest, a
+// number is mapped by the first range it is in.
+func mapSpans(spans []span, ranges MappingList) []span {
+	var mapped []span
+	for _, c := range ranges {
+		if c.Length <= 0 {
+			continue
+		}
+		lo, hi := c.Source, c.Source+c.Length
+		var left []span
+		for _, s := range spans {
+			if before := (span{s.lo, min(s.hi, lo)}); before.lo < before.hi {
+				left = append(left, before)
+			}
+			if in := (span{max(s.lo, lo), min(s.hi, hi)}); in.lo < in.hi {
+				mapped = append(mapped, span{in.lo + c.Destination - lo, in.hi + c.Destination - lo})
+			}
+			if after := (span{max(s.lo, hi), s.hi}); after.lo < after.hi {
+				left = append(left, after)
+			}
+		}
+		spans = left
+	}
+	return append(mapped, spans...)
+}
+
+// lowestInRanges is the lowest location of any seed in the ranges, found by
+// mapping the ranges whole.
+func lowestInRanges(almanac Almanac) int {
+	var spans []span
+	for i := 0; i < len(almanac.Seeds); i += 2 {
+		if s := (span{almanac.Seeds[i], almanac.Seeds[i] + almanac.Seeds[i+1]}); s.lo < s.hi {
+			spans = append(spans, s)
+		}
+	}
+	for _, m := range almanac.Maps {
+		spans = mapSpans(spans, m)
+	}
+	lowest := math.MaxInt
+	for _, s := range spans {
+		lowest = min(lowest, s.lo)
+	}
+	searchLog.Debug("seed ranges", "spans", len(spans), "lowest", lowest)
+	return lowest
 }
@@ -0,0 +1,56 @@
+package day05
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/gen/gentest"
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+func writeAlmanac(a Almanac) string {
+	var b strings.Builder
+	b.WriteString("seeds:")
+	for _, seed := range a.Seeds {
+		fmt.Fprintf(&b, " %d", seed)
+	}
+	b.WriteString("\n")
+	for i, m := range a.Maps {
+		fmt.Fprintf(&b, "\n%s map:\n", mapNames[i])
+		for _, c := range m {
+			fmt.Fprintf(&b, "%d %d %d\n", c.Destination, c.Source, c.Length)
+		}
+	}
+	return b.String()
+}
+
+func FuzzParseAlmanac(f *testing.F) {
+	parsetest.Seed(f,
+		"seeds: 79 14 55 13\n\nseed-to-soil map:\n50 98 2\n52 50 48\n\nsoil-to-fertilizer map:\n0 15 37\n",
+		"seeds: 1 2\n\nseed-to-soil map:\n1 1 1\n\nsoil-to-fertilizer map:\n1 1 1\n\nfertilizer-to-water map:\n1 1 1\n\n"+
+			"water-to-light map:\n1 1 1\n\nlight-to-temperature map:\n1 1 1\n\ntemperature-to-humidity map:\n1 1 1\n\n"+
+			"humidity-to-location map:\n1 1 1\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, parseAlmanac, writeAlmanac)
+	})
+}
+
+func TestLowestInRanges(t *testing.T) {
+	inRanges := func(r io.Reader) (int, error) {
+		almanac, err := parseAlmanac(r)
+		return lowestInRanges(almanac), err
+	}
+	oneByOne := func(r io.Reader) (int, error) {
+		almanac, err := parseAlmanac(r)
+		if err != nil {
+			return 0, err
+		}
+		return lowestOneByOne(context.Background(), almanac, aoc.NewProgress(context.Background(), "seeds", 0))
+	}
+	gentest.Compare(t, Generate, 40, 200, inRanges, oneByOne)
+}
@@ -0,0 +1,22 @@
+package day06
+
+import (
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes a sheet of up to three races, each of which can be won.
+// Size only matters below three, as the times run together into one race
+// in part 2, and that race is tried a millisecond at a time.
+func Generate(rng *rand.Rand, size int) []byte {
+	var times, distances string
+	for range gen.Clamp(size, 1, 3) {
+		time := gen.Between(rng, 10, 99)
+		best := time / 2 * (time - time/2)
+		times += fmt.Sprintf(" %4d", time)
+		distances += fmt.Sprintf(" %4d", rng.IntN(best))
+	}
+	return []byte("Time:    " + times + "\nDistance:" + distances + "\n")
+}
@@ -1,12 +1,18 @@
-package main
+package day06
 
 import (
-	"bufio"
-	"errors"
-	"fmt"
-	"os"
+	"context"
+	"io"
 	"strconv"
 	"strings"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/parse"
+)
+
+var (
+	log      = aoc.Logger("simulation")
+	parseLog = aoc.Logger("parser")
 )
 
 type Races struct {
@@ -14,92 +20,110 @@ type Races struct {
 	DistanceMM []int
 }
 
-func splitByColon(s string) (string, string, error) {
-	parts := strings.SplitN(s, ":", 2)
-	if len(parts) != 2 {
-		return "", "", errors.New("input string does not contain a colon")
-	}
-	return parts[0], parts[1], nil
-}
-
-func solve(r Races) int {
+func solve(ctx context.Context, r Races) (int, error) {
+	const batch = 1 << 16 // button times between looking at ctx
 	answer := 1
 	for i := 0; i < len(r.TimeMS); i++ {
 		winners := 0
 		for buttonMS := 1; buttonMS < r.TimeMS[i]-1; buttonMS++ {
+			if buttonMS%batch == 0 {
+				if err := ctx.Err(); err != nil {
+					return 0, err
+				}
+			}
 			if buttonMS*(r.TimeMS[i]-buttonMS) > r.DistanceMM[i] {
 				winners++
-				//fmt.Fprintln(os.Stderr, "winner = ", buttonMS, "ms")
 			}
 		}
+		log.Debug("race", "time", r.TimeMS[i], "distance", r.DistanceMM[i], "winners", winners)
 		answer *= winners
 	}
-	return answer
+	return answer, nil
 }
 
-func main() {
+// parseRaces reads the sheet of paper twice: as the races it lists, and as
+// the one race it is in part two, with the spaces between the numbers
+// taken out.
+func parseRaces(ctx context.Context, input io.Reader) ([2]Races, error) {
 	var r [2]Races
+	seen := make(map[string]bool)
 
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		line := scanner.Text()
-		line = strings.TrimSpace(line)
-
-		if len(line) == 0 {
+	lines, err := parse.Lines(input)
+	if err != nil {
+		return r, err
+	}
+	for _, line := range lines {
+		if line.Blank() {
 			continue // skip blank lines
 		}
-		left, right, error := splitByColon(line)
-
-		if error == nil {
-			if strings.EqualFold(left, "time") {
-				str := right
-				fields := strings.Fields(str)
-				nospace := strings.Join(strings.Fields(str), "")
-				fmt.Fprintln(os.Stderr, "nospace = ", nospace)
-				for _, field := range fields {
-					num, err := strconv.Atoi(field)
-					if err != nil {
-						fmt.Fprintln(os.Stderr, "Error converting string to integer: ", err)
-						os.Exit(1)
-					}
-					r[0].TimeMS = append(r[0].TimeMS, num)
-					//fmt.Fprintln(os.Stderr, "added time: ", num)
-				}
-				num, _ := strconv.Atoi(nospace)
-				r[1].TimeMS = append(r[1].TimeMS, num)
-			} else if strings.EqualFold(left, "distance") {
-				str := right
-				fields := strings.Fields(str)
-				nospace := strings.Join(strings.Fields(str), "")
-				fmt.Fprintln(os.Stderr, "nospace = ", nospace)
-				for _, field := range fields {
-					num, err := strconv.Atoi(field)
-					if err != nil {
-						fmt.Fprintln(os.Stderr, "Error converting string to integer: ", err)
-						os.Exit(1)
-					}
-					r[0].DistanceMM = append(r[0].DistanceMM, num)
-					//fmt.Fprintln(os.Stderr, "added distance: ", num)
-				}
-				num, _ := strconv.Atoi(nospace)
-				r[1].DistanceMM = append(r[1].DistanceMM, num)
-			} else {
-				fmt.Fprintln(os.Stderr, "Unknown map")
-				os.Exit(1)
+		label, right, err := line.Cut(":")
+		if err != nil {
+			return r, err
+		}
+		// times and distances are never negative, and a sign would change
+		// what the numbers join into
+		if err := right.Only("0123456789 \t"); err != nil {
+			return r, err
+		}
+		fields, err := right.Ints()
+		if err != nil {
+			return r, err
+		}
+		// part two reads the same line as one number with the spaces
+		// removed, and a line without any numbers as no race at all
+		var nospace []int
+		if len(fields) > 0 {
+			n, err := strconv.Atoi(strings.Join(strings.Fields(right.Text), ""))
+			if err != nil {
+				return r, right.Errorf("numbers too long to join: %v", err)
 			}
+			nospace = append(nospace, n)
+		}
 
+		left := strings.TrimSpace(label.Text)
+		if seen[strings.ToLower(left)] {
+			if err := parse.Tolerate(ctx, parseLog, label.Errorf("a second %s line", left)); err != nil {
+				return r, err
+			}
+			continue
+		}
+		seen[strings.ToLower(left)] = true
+		if strings.EqualFold(left, "time") {
+			r[0].TimeMS = append(r[0].TimeMS, fields...)
+			r[1].TimeMS = append(r[1].TimeMS, nospace...)
+		} else if strings.EqualFold(left, "distance") {
+			r[0].DistanceMM = append(r[0].DistanceMM, fields...)
+			r[1].DistanceMM = append(r[1].DistanceMM, nospace...)
+		} else {
+			return r, label.Errorf("unknown map: %s", left)
 		}
 	}
 	if len(r[0].TimeMS) != len(r[0].DistanceMM) {
-		fmt.Fprintln(os.Stderr, "Error: time and distance lists are not the same length")
-		os.Exit(1)
+		return r, parse.End(lines).Errorf("%d times but %d distances", len(r[0].TimeMS), len(r[0].DistanceMM))
+	}
+	if parse.Strict && len(r[0].TimeMS) == 0 {
+		return r, parse.End(lines).Errorf("no races")
 	}
-	fmt.Fprintln(os.Stderr, "time list: ", r[0].TimeMS)
-	fmt.Fprintln(os.Stderr, "distance list: ", r[0].DistanceMM)
-	fmt.Fprintln(os.Stderr, "time list: ", r[1].TimeMS)
-	fmt.Fprintln(os.Stderr, "distance list: ", r[1].DistanceMM)
+	return r, nil
+}
 
-	fmt.Println(solve(r[0]))
-	fmt.Println(solve(r[1]))
+func Solve(ctx context.Context, input io.Reader) (any, any, error) {
+	r, err := parseRaces(ctx, input)
+	if err != nil {
+		return nil, nil, err
+	}
+	parseLog.Debug("races", "times", r[0].TimeMS, "distances", r[0].DistanceMM)
+	parseLog.Debug("one race", "time", r[1].TimeMS, "distance", r[1].DistanceMM)
 
+	aoc.Parsed(ctx)
+	part1, err := solve(ctx, r[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	aoc.Part1Done(ctx)
+	part2, err := solve(ctx, r[1])
+	if err != nil {
+		return part1, nil, err
+	}
+	return part1, part2, nil
 }
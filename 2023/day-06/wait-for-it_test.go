@@ -0,0 +1,36 @@
+package day06
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+// writeRaces writes the races of part one, which part two is read from.
+func writeRaces(r [2]Races) string {
+	var b strings.Builder
+	b.WriteString("Time:")
+	for _, t := range r[0].TimeMS {
+		fmt.Fprintf(&b, " %d", t)
+	}
+	b.WriteString("\nDistance:")
+	for _, d := range r[0].DistanceMM {
+		fmt.Fprintf(&b, " %d", d)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func FuzzParseRaces(f *testing.F) {
+	parsetest.Seed(f,
+		"Time:      7  15   30\nDistance:  9  40  200\n",
+		"Time: 71530\nDistance: 940200\n",
+		"Time: 1\nTime: 2\nDistance: 3 4\n",
+		"Time: -0 5\nDistance: 1 2\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, parsetest.Background(parseRaces), writeRaces)
+	})
+}
@@ -1,13 +1,15 @@
-package main
+package day07
 
 import (
-	"bufio"
+	"context"
 	"fmt"
-	"os"
+	"io"
 	"sort"
-	"strconv"
 	"strings"
 	"unicode"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/parse"
 )
 
 type HandType int
@@ -190,7 +192,7 @@ func totalWinnings(hands []Parsed) int {
 func appendCardToHand(c rune, x *Parsed, jokersWild bool) error {
 	card, err := charToCard(c, jokersWild)
 	if err != nil {
-		return fmt.Errorf("Error converting char to a card: %v", c)
+		return fmt.Errorf("not a card: %q", c)
 	}
 	x.Hand = append(x.Hand, card)
 	return nil
@@ -215,32 +217,44 @@ func determineHandTypeAndAppend(x *Parsed, hands []Parsed) []Parsed {
 	return append(hands, *x)
 }
 
-func main() {
-	var hands []Parsed = make([]Parsed, 1)
-	// prefix with sentinel value so first real hand is rank 1
-	hands[0] = Parsed{Hand: []Card{Zero, Zero, Zero, Zero, Zero}, Bid: 0, Type: -1}
-
-	var jokerHands []Parsed = make([]Parsed, 1)
-	// prefix with sentinel value so first real hand is rank 1
-	jokerHands[0] = Parsed{Hand: []Card{Zero, Zero, Zero, Zero, Zero}, Bid: 0, Type: -1}
-
-	scanner := bufio.NewScanner(os.Stdin)
-
-	for scanner.Scan() {
+// parseHands reads each hand twice, with jacks as they are for part one and
+// as jokers for part two.
+func parseHands(r io.Reader) (hands, jokerHands []Parsed, err error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, line := range lines {
 		var x, y Parsed
 
-		line := scanner.Text()
-
-		line = strings.TrimSpace(line)
-		lastSpace := strings.LastIndex(line, " ")
-		left := line[:lastSpace]
-		right := line[lastSpace+1:]
-		left = removePunctuationAndWhitespace(left)
-		hand := strings.ToUpper(left)
+		if line.Blank() && !parse.Strict {
+			continue
+		}
+		fields := line.Fields()
+		if len(fields) < 2 {
+			return nil, nil, line.ErrorAt(len(line.Text), "expected a hand and a bid")
+		}
+		bid := fields[len(fields)-1]
+		if parse.Strict {
+			if len(fields) > 2 {
+				return nil, nil, fields[2].Errorf("unexpected %q after the bid", fields[2].Text)
+			}
+			if err := fields[0].Only("23456789TJQKA"); err != nil {
+				return nil, nil, err
+			}
+			if n := len(fields[0].Text); n != 5 {
+				return nil, nil, fields[0].Errorf("a hand has 5 cards, not %d", n)
+			}
+		}
+		var left strings.Builder
+		for _, f := range fields[:len(fields)-1] {
+			left.WriteString(f.Text)
+		}
+		hand := strings.ToUpper(removePunctuationAndWhitespace(left.String()))
 
-		i, err := strconv.Atoi(right)
+		i, err := bid.Int()
 		if err != nil {
-			fmt.Println("Error converting string to int:", err)
+			return nil, nil, err
 		}
 		x.Bid = i
 		y.Bid = i
@@ -248,12 +262,26 @@ func main() {
 			appendCardToHand(c, &x, false)
 			appendCardToHand(c, &y, true)
 		}
+		if len(x.Hand) == 0 {
+			return nil, nil, fields[0].Errorf("no cards in the hand")
+		}
 		hands = determineHandTypeAndAppend(&x, hands)
 		jokerHands = determineHandTypeAndAppend(&y, jokerHands)
 	}
-	if err := scanner.Err(); err != nil {
-		fmt.Fprintln(os.Stderr, "reading standard input:", err)
+	return hands, jokerHands, nil
+}
+
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	// prefix with sentinel value so first real hand is rank 1
+	sentinel := Parsed{Hand: []Card{Zero, Zero, Zero, Zero, Zero}, Bid: 0, Type: -1}
+
+	parsed, jokerParsed, err := parseHands(r)
+	if err != nil {
+		return nil, nil, err
 	}
+	hands := append([]Parsed{sentinel}, parsed...)
+	jokerHands := append([]Parsed{sentinel}, jokerParsed...)
+	aoc.Parsed(ctx)
 
 	sort.Slice(hands, func(i, j int) bool {
 		if hands[i].Type != hands[j].Type {
@@ -261,6 +289,12 @@ func main() {
 		}
 		return less(hands[i].Hand, hands[j].Hand)
 	})
+	part1 := totalWinnings(hands)
+	aoc.Part1Done(ctx)
+	if err := ctx.Err(); err != nil {
+		return part1, nil, err
+	}
+
 	sort.Slice(jokerHands, func(i, j int) bool {
 		if jokerHands[i].Type != jokerHands[j].Type {
 			return jokerHands[i].Type < jokerHands[j].Type
@@ -268,6 +302,5 @@ func main() {
 		return less(jokerHands[i].Hand, jokerHands[j].Hand)
 	})
 
-	fmt.Println(totalWinnings(hands))
-	fmt.Println(totalWinnings(jokerHands))
+	return part1, totalWinnings(jokerHands), nil
 }
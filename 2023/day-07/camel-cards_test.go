@@ -0,0 +1,46 @@
+package day07
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+// cardChars are the cards in order of their value.
+const cardChars = "0123456789TJQKA"
+
+type table struct {
+	hands, jokerHands []Parsed
+}
+
+func readTable(r io.Reader) (table, error) {
+	hands, jokerHands, err := parseHands(r)
+	return table{hands, jokerHands}, err
+}
+
+// writeTable writes the hands of part one, which those of part two are read
+// from.
+func writeTable(t table) string {
+	var b strings.Builder
+	for _, h := range t.hands {
+		for _, c := range h.Hand {
+			b.WriteByte(cardChars[c])
+		}
+		fmt.Fprintf(&b, " %d\n", h.Bid)
+	}
+	return b.String()
+}
+
+func FuzzParseHands(f *testing.F) {
+	parsetest.Seed(f,
+		"32T3K 765\nT55J5 684\nKK677 28\nKTJJT 220\nQQQJA 483\n",
+		"JJJJJ 1\n\n2345J 2\n",
+		"qq-qj a 483\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, readTable, writeTable)
+	})
+}
@@ -0,0 +1,20 @@
+package day07
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes size hands of camel cards with their bids. Each hand is
+// dealt from a few of the cards, so that pairs and better turn up.
+func Generate(rng *rand.Rand, size int) []byte {
+	var b bytes.Buffer
+	for range size {
+		cards := gen.Word(rng, gen.Between(rng, 1, 5), "23456789TJQKA")
+		fmt.Fprintf(&b, "%s %d\n", gen.Word(rng, 5, cards), gen.Between(rng, 1, 1000))
+	}
+	return b.Bytes()
+}
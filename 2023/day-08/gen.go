@@ -0,0 +1,71 @@
+package day08
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+const (
+	letters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	between = "BCDEFGHIJKLMNOPQRSTUVWXY" // the last letter of a node not at either end
+)
+
+// Generate makes a map of about size nodes. From AAA and each other node
+// ending in A a path leads to a node ending in Z and then around a short
+// loop back through it, and the rest of the nodes only lead among
+// themselves. The loops are a few steps long, since part 2 walks the ghosts
+// until they all happen to be home at once.
+func Generate(rng *rand.Rand, size int) []byte {
+	type node struct{ label, left, right string }
+	var nodes []node
+	taken := map[string]bool{}
+	fresh := func(last string) string {
+		for {
+			label := gen.Word(rng, 2, letters) + gen.Word(rng, 1, last)
+			if !taken[label] {
+				taken[label] = true
+				return label
+			}
+		}
+	}
+
+	ghosts := gen.Clamp(size/10, 1, 3)
+	for i, loop := range rng.Perm(6)[:ghosts] {
+		start, end := "AAA", "ZZZ"
+		for i > 0 && (taken[start] || taken[end]) {
+			name := gen.Word(rng, 2, letters)
+			start, end = name+"A", name+"Z"
+		}
+		taken[start], taken[end] = true, true
+
+		path := []string{start}
+		for range loop + 1 {
+			path = append(path, fresh(between))
+		}
+		path = append(path, end)
+		for j, label := range path {
+			next := path[max((j+1)%len(path), 1)]
+			nodes = append(nodes, node{label, next, next})
+		}
+	}
+
+	var others []string
+	for range max(size-len(nodes), 1) {
+		others = append(others, fresh(between))
+	}
+	for _, label := range others {
+		nodes = append(nodes, node{label, others[rng.IntN(len(others))], others[rng.IntN(len(others))]})
+	}
+	rng.Shuffle(len(nodes), func(i, j int) { nodes[i], nodes[j] = nodes[j], nodes[i] })
+
+	var b bytes.Buffer
+	b.WriteString(gen.Word(rng, gen.Clamp(size, 1, 300), "LR"))
+	b.WriteString("\n\n")
+	for _, n := range nodes {
+		fmt.Fprintf(&b, "%s = (%s, %s)\n", n.label, n.left, n.right)
+	}
+	return b.Bytes()
+}
@@ -1,13 +1,20 @@
-package main
+package day08
 
 import (
-	"bufio"
+	"context"
+	"errors"
 	"fmt"
-	"os"
+	"io"
 	"regexp"
 	"strings"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/graph"
+	"github.com/havill/AdventOfCode/parse"
 )
 
+var log = aoc.Logger("simulation")
+
 type node struct {
 	label string
 	left  string
@@ -20,13 +27,22 @@ func addNodeToNetwork(network Network, n *node) {
 	network[n.label] = n
 }
 
-func parseInput(line string) *node {
+// nodeLine is how every node is written, checked only in strict mode.
+var nodeLine = regexp.MustCompile(`^(\w{3}) = \((\w{3}), (\w{3})\)$`)
+
+func parseInput(l parse.Line) (*node, error) {
+	if parse.Strict && !nodeLine.MatchString(l.Text) {
+		return nil, l.Errorf("expected a node such as \"AAA = (BBB, CCC)\"")
+	}
 	// Remove all punctuation
 	re := regexp.MustCompile(`[^\w\s]`)
-	line = re.ReplaceAllString(line, "")
+	line := re.ReplaceAllString(l.Text, "")
 
 	// Split the line into words
 	words := strings.Fields(line)
+	if len(words) < 3 {
+		return nil, l.Errorf("expected a node and the nodes to its left and right")
+	}
 
 	// Convert words to upper case
 	for i, word := range words {
@@ -40,7 +56,7 @@ func parseInput(line string) *node {
 		right: words[2],
 	}
 
-	return n
+	return n, nil
 }
 
 func isStartingNode(label string) bool {
@@ -60,50 +76,103 @@ func ghostsAreHome(ghostIsHere []*node) bool {
 	return true
 }
 
-func debugGhosts(ghostIsHere []*node, count int) bool {
-	arrived := 0
+func canGetHome(paths *graph.Graph[string], label string) bool {
+	for n := range paths.DFS(label) {
+		if isEndingNode(n) {
+			return true
+		}
+	}
+	return false
+}
 
+func logGhosts(steps int, ghostIsHere []*node) {
+	labels := make([]string, len(ghostIsHere))
+	home := 0
 	for i, ghost := range ghostIsHere {
-		fmt.Fprint(os.Stdout, "Ghost #", i, ":", ghost.label)
+		labels[i] = ghost.label
 		if isEndingNode(ghost.label) {
-			fmt.Fprint(os.Stdout, " (home)")
-			arrived += 1
+			home++
 		}
-		fmt.Fprintln(os.Stdout, "")
 	}
-	fmt.Fprintln(os.Stdout, "---------------------")
-	return arrived > count
+	aoc.Trace(log, "ghosts", "step", steps, "at", labels, "home", home)
 }
 
-func main() {
-	var instructions string
-	var instructionsI int = 0
-	var steps int = 0
-	var network Network = make(Network)
+// parseNetwork reads the left and right instructions and the nodes they
+// lead through.
+func parseNetwork(r io.Reader) (instructions string, network Network, err error) {
+	network = make(Network)
 
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		line := scanner.Text()
-		trimmed := strings.TrimSpace(line)
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return "", nil, err
+	}
+	var nodeLines []parse.Line
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line.Text)
 		caps := strings.ToUpper(trimmed)
 		if len(caps) == 0 {
 			continue
 		}
 		if len(instructions) == 0 {
+			if parse.Strict {
+				if err := line.Only("LR"); err != nil {
+					return "", nil, err
+				}
+			}
 			instructions = caps
 		} else {
-			n := parseInput(line)
+			n, err := parseInput(line)
+			if err != nil {
+				return "", nil, err
+			}
 			addNodeToNetwork(network, n)
+			nodeLines = append(nodeLines, line)
 		}
 	}
-	if err := scanner.Err(); err != nil {
-		fmt.Fprintln(os.Stderr, "reading standard input:", err)
-		os.Exit(1)
+	if parse.Strict {
+		if len(network) == 0 {
+			return "", nil, parse.End(lines).Errorf("no nodes")
+		}
+		// every node line matched nodeLine, so it can say where the names
+		// to the left and right are
+		for _, line := range nodeLines {
+			m := nodeLine.FindStringSubmatchIndex(line.Text)
+			for _, i := range []int{4, 6} {
+				if next := line.Text[m[i]:m[i+1]]; network[strings.ToUpper(next)] == nil {
+					return "", nil, line.ErrorAt(m[i], "%s is not in the network", next)
+				}
+			}
+		}
 	}
+	return instructions, network, nil
+}
+
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	var instructionsI int = 0
+	var steps int = 0
+
+	instructions, network, err := parseNetwork(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	// walking a network that never arrives would go on forever, so check
+	// there is a way there at all before setting out
+	paths := graph.New[string](true)
+	for _, n := range network {
+		paths.AddEdge(n.label, n.left)
+		paths.AddEdge(n.label, n.right)
+	}
+	if network["AAA"] != nil && !paths.Reachable("AAA", "ZZZ") {
+		return nil, nil, errors.New("ZZZ cannot be reached from AAA")
+	}
+
 	var youAreHere *node
 
 	youAreHere = network["AAA"]
 	for youAreHere != nil && youAreHere.label != "ZZZ" {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
 		if instructionsI >= len(instructions) {
 			instructionsI = 0
 		}
@@ -114,13 +183,13 @@ func main() {
 			right := network[youAreHere.right].label
 			youAreHere = network[right]
 		} else {
-			fmt.Fprintln(os.Stderr, "Invalid instruction: ", instructions[instructionsI])
-			os.Exit(1)
+			return nil, nil, fmt.Errorf("invalid instruction: %c", instructions[instructionsI])
 		}
 		steps += 1
 		instructionsI += 1
 	}
-	fmt.Println(steps)
+	part1 := steps
+	aoc.Part1Done(ctx)
 	steps = 0
 
 	var ghostIsHere []*node
@@ -130,40 +199,40 @@ func main() {
 			ghostIsHere = append(ghostIsHere, value)
 		}
 	}
+	for _, ghost := range ghostIsHere {
+		if !canGetHome(paths, ghost.label) {
+			return part1, nil, fmt.Errorf("the ghost at %s can never get home", ghost.label)
+		}
+	}
 	instructionsI = 0
 	for ghostIsHere != nil && !ghostsAreHome(ghostIsHere) {
-		/*
-			if debugGhosts(ghostIsHere, math.MaxInt32) {
-				os.Exit(1)
-			}
-		*/
+		if err := ctx.Err(); err != nil {
+			return part1, nil, err
+		}
+		if aoc.Tracing() {
+			logGhosts(steps, ghostIsHere)
+		}
 		if instructions[instructionsI] == 'L' {
-			//fmt.Fprint(os.Stdout, "GO LEFT (#", instructionsI, ") ")
 			for i, ghost := range ghostIsHere {
 				left := network[ghost.left].label
 				ghostIsHere[i] = network[left]
 			}
 		} else if instructions[instructionsI] == 'R' {
-			//fmt.Fprint(os.Stdout, "GO RIGHT (#", instructionsI, ") ")
 			for i, ghost := range ghostIsHere {
 				right := network[ghost.right].label
 				ghostIsHere[i] = network[right]
 			}
 		} else {
-			fmt.Fprintln(os.Stderr, "Invalid instruction: ", instructions[instructionsI])
-			os.Exit(1)
+			return nil, nil, fmt.Errorf("invalid instruction: %c", instructions[instructionsI])
 		}
 		steps += 1
-		//fmt.Fprint(os.Stdout, "[STEP ", steps, "]\n\n")
 		instructionsI += 1
 		if instructionsI >= len(instructions) {
 			instructionsI = 0
 		}
 	}
 	if ghostIsHere == nil {
-		fmt.Fprintln(os.Stderr, "Ghosts are lost!")
-		os.Exit(1)
+		return part1, nil, errors.New("ghosts are lost")
 	}
-	fmt.Println(steps)
-
+	return part1, steps, nil
 }
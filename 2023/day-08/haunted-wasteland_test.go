@@ -0,0 +1,57 @@
+package day08
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/parse"
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+type documents struct {
+	instructions string
+	network      Network
+}
+
+func readDocuments(r io.Reader) (documents, error) {
+	instructions, network, err := parseNetwork(r)
+	return documents{instructions, network}, err
+}
+
+func writeDocuments(d documents) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", d.instructions)
+	for _, label := range slices.Sorted(maps.Keys(d.network)) {
+		n := d.network[label]
+		fmt.Fprintf(&b, "%s = (%s, %s)\n", n.label, n.left, n.right)
+	}
+	return b.String()
+}
+
+func FuzzParseNetwork(f *testing.F) {
+	parsetest.Seed(f,
+		"RL\n\nAAA = (BBB, CCC)\nBBB = (DDD, EEE)\nCCC = (ZZZ, GGG)\nDDD = (DDD, DDD)\nEEE = (EEE, EEE)\nGGG = (GGG, GGG)\nZZZ = (ZZZ, ZZZ)\n",
+		"LLR\n\nAAA = (BBB, BBB)\nBBB = (AAA, ZZZ)\nZZZ = (ZZZ, ZZZ)\n",
+		"lr\naaa = (bbb, ccc) ddd\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, readDocuments, writeDocuments)
+	})
+}
+
+func TestStrictNetwork(t *testing.T) {
+	defer func(was bool) { parse.Strict = was }(parse.Strict)
+	parse.Strict = true
+	for input, want := range map[string]string{
+		"LR\n\nAAA = (BBB, ZZZ)\nBBB = (AAA, CCC)\nZZZ = (ZZZ, ZZZ)\n": "line 4, column 13: CCC is not in the network",
+		"LR\n": "line 1, column 3: no nodes",
+	} {
+		if _, _, err := parseNetwork(strings.NewReader(input)); err == nil || err.Error() != want {
+			t.Errorf("%q: got %v, want %s", input, err, want)
+		}
+	}
+}
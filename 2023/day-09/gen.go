@@ -0,0 +1,34 @@
+package day09
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes size histories of 21 values, each from a polynomial of at
+// most the fourth degree, so that the differences always come down to zero.
+func Generate(rng *rand.Rand, size int) []byte {
+	var b bytes.Buffer
+	for range size {
+		coefficients := make([]int, gen.Between(rng, 1, 5))
+		for i := range coefficients {
+			coefficients[i] = gen.Between(rng, -9, 9)
+		}
+		offset := gen.Between(rng, -5, 5)
+		for x := range 21 {
+			value := 0
+			for i := len(coefficients) - 1; i >= 0; i-- {
+				value = value*(x+offset) + coefficients[i]
+			}
+			if x > 0 {
+				b.WriteByte(' ')
+			}
+			fmt.Fprint(&b, value)
+		}
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
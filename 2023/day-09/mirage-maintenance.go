@@ -1,13 +1,15 @@
-package main
+package day09
 
 import (
-	"bufio"
-	"fmt"
-	"os"
-	"strconv"
-	"strings"
+	"context"
+	"io"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/parse"
 )
 
+var parseLog = aoc.Logger("parser")
+
 func diffSlice(slice []int) []int {
 	var diff []int
 	for i := 0; i < len(slice)-1; i++ {
@@ -57,39 +59,58 @@ func extrapolateBackwards(history [][]int) int {
 	return difference
 }
 
-func main() {
-	scanner := bufio.NewScanner(os.Stdin)
-	var histories [][][]int
-	var total int
+func parseHistories(ctx context.Context, r io.Reader) ([][]int, error) {
+	var histories [][]int
 
-	for scanner.Scan() {
-		dataset := scanner.Text()
-		numbers := strings.Fields(dataset)
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range lines {
+		numbers := line.Fields()
 		if len(numbers) < 1 {
-			fmt.Println("Invalid input. Please enter at least one integer.")
+			if err := parse.Tolerate(ctx, parseLog, line.Errorf("expected at least one number")); err != nil {
+				return nil, err
+			}
 			continue
 		}
 
 		var ints []int
-		var extrapolation [][]int
 		for _, number := range numbers {
-			n, err := strconv.Atoi(number)
+			n, err := number.Int()
 			if err != nil {
-				fmt.Printf("Invalid number: %s\n", number)
+				if err := parse.Tolerate(ctx, parseLog, err); err != nil {
+					return nil, err
+				}
 				continue
 			}
 			ints = append(ints, n)
 		}
-
-		extrapolation = append(extrapolation, ints)
-		histories = append(histories, extrapolation)
+		if len(ints) == 0 {
+			continue // every number was skipped, and there is nothing to extrapolate
+		}
+		histories = append(histories, ints)
 	}
+	return histories, nil
+}
 
-	if err := scanner.Err(); err != nil {
-		fmt.Fprintln(os.Stderr, "reading standard input:", err)
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	var histories [][][]int
+	var total int
+
+	values, err := parseHistories(ctx, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, ints := range values {
+		histories = append(histories, [][]int{ints})
 	}
+	aoc.Parsed(ctx)
 
 	for i := 0; i < len(histories); i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
 		for j := 0; j < len(histories[i]); j++ {
 			if allZeros(histories[i][j]) {
 				break
@@ -102,11 +123,12 @@ func main() {
 	for _, i := range histories {
 		total += extrapolateForward(i)
 	}
-	fmt.Println(total)
+	part1 := total
+	aoc.Part1Done(ctx)
 
 	total = 0
 	for _, i := range histories {
 		total += extrapolateBackwards(i)
 	}
-	fmt.Println(total)
+	return part1, total, nil
 }
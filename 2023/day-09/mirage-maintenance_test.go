@@ -0,0 +1,28 @@
+package day09
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+func writeHistories(histories [][]int) string {
+	var b strings.Builder
+	for _, history := range histories {
+		b.WriteString(strings.Trim(fmt.Sprint(history), "[]"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func FuzzParseHistories(f *testing.F) {
+	parsetest.Seed(f,
+		"0 3 6 9 12 15\n1 3 6 10 15 21\n10 13 16 21 30 45\n",
+		"-4 -1\n\n7 x 8\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, parsetest.Background(parseHistories), writeHistories)
+	})
+}
@@ -0,0 +1,89 @@
+package day10
+
+import (
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes a size by size field with one loop of pipe through S, and
+// scraps of pipe going nowhere around it. The loop is the outline of a
+// shape made of rows of cells, each row overlapping the one above it, so it
+// never touches itself.
+func Generate(rng *rand.Rand, size int) []byte {
+	size = gen.Clamp(size, 5, 140)
+	cells := size - 3 // the loop runs around the cells' corners, inside a border
+
+	// the shape takes up cells from[y] up to to[y] of each row y
+	from, to := make([]int, cells), make([]int, cells)
+	for y := range cells {
+		for {
+			from[y] = rng.IntN(cells)
+			to[y] = gen.Between(rng, from[y]+1, cells)
+			if y == 0 || max(from[y], from[y-1]) < min(to[y], to[y-1]) {
+				break
+			}
+		}
+	}
+	in := func(x, y int) bool {
+		return y >= 0 && y < cells && x >= from[y] && x < to[y]
+	}
+	// pipe is the tile at a corner of the cells, '.' if the outline
+	// doesn't pass through it
+	pipe := func(x, y int) byte {
+		var t Tile
+		if in(x-1, y-1) != in(x, y-1) {
+			t |= north
+		}
+		if in(x-1, y) != in(x, y) {
+			t |= south
+		}
+		if in(x, y-1) != in(x, y) {
+			t |= east
+		}
+		if in(x-1, y-1) != in(x-1, y) {
+			t |= west
+		}
+		switch t {
+		case north_south:
+			return '|'
+		case east_west:
+			return '-'
+		case north_east:
+			return 'L'
+		case north_west:
+			return 'J'
+		case south_west:
+			return '7'
+		case south_east:
+			return 'F'
+		}
+		return '.'
+	}
+
+	field := make([][]byte, size)
+	var loop [][2]int
+	for y := range field {
+		field[y] = make([]byte, size)
+		for x := range field[y] {
+			field[y][x] = gen.Pick(rng, "..........|-LJ7F")
+			if x > 0 && y > 0 && x <= cells+1 && y <= cells+1 {
+				if c := pipe(x-1, y-1); c != '.' {
+					field[y][x] = c
+					loop = append(loop, [2]int{x, y})
+				}
+			}
+		}
+	}
+
+	// the scraps next to S mustn't look like more of the loop
+	start := loop[rng.IntN(len(loop))]
+	x, y := start[0], start[1]
+	for _, p := range pipes {
+		if pipe(x-1+p.step.X, y-1+p.step.Y) == '.' {
+			field[y+p.step.Y][x+p.step.X] = '.'
+		}
+	}
+	field[y][x] = 'S'
+	return gen.Grid(size, size, func(x, y int) byte { return field[y][x] })
+}
@@ -1,9 +1,21 @@
-package main
+package day10
 
 import (
-	"bufio"
+	"context"
 	"fmt"
-	"os"
+	"io"
+	"math/bits"
+	"strings"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/grid"
+	"github.com/havill/AdventOfCode/parse"
+	"github.com/havill/AdventOfCode/render"
+)
+
+var (
+	log      = aoc.Logger("simulation")
+	parseLog = aoc.Logger("parser")
 )
 
 type Tile int
@@ -24,10 +36,7 @@ const (
 	starting    Tile = north | south | east | west | footprint
 )
 
-type Point struct {
-	x int
-	y int
-}
+type Point = grid.Point
 
 type Animal struct {
 	previous Point
@@ -35,19 +44,26 @@ type Animal struct {
 	next     Point
 }
 
-func availableDirections(field [][]Tile, p Point) []Point {
+// pipes lists which way each connection leads and the connection that the
+// neighbouring tile needs to have for the two to join up
+var pipes = []struct {
+	from, to Tile
+	step     Point
+}{
+	{north, south, grid.North},
+	{south, north, grid.South},
+	{east, west, grid.East},
+	{west, east, grid.West},
+}
+
+func availableDirections(field *grid.Grid[Tile], p Point) []Point {
 	directions := make([]Point, 0, 4)
-	if field[p.y][p.x]&north == north && p.y > 0 && field[p.y-1][p.x]&south == south {
-		directions = append(directions, Point{p.x, p.y - 1})
-	}
-	if field[p.y][p.x]&south == south && p.y < len(field)-1 && field[p.y+1][p.x]&north == north {
-		directions = append(directions, Point{p.x, p.y + 1})
-	}
-	if field[p.y][p.x]&east == east && p.x < len(field[p.y])-1 && field[p.y][p.x+1]&west == west {
-		directions = append(directions, Point{p.x + 1, p.y})
-	}
-	if field[p.y][p.x]&west == west && p.x > 0 && field[p.y][p.x-1]&east == east {
-		directions = append(directions, Point{p.x - 1, p.y})
+	for _, pipe := range pipes {
+		next := p.Add(pipe.step)
+		// outside the field At returns ground, which joins nothing
+		if field.At(p)&pipe.from == pipe.from && field.At(next)&pipe.to == pipe.to {
+			directions = append(directions, next)
+		}
 	}
 	return directions
 }
@@ -64,7 +80,7 @@ func removePreviousDirection(directions []Point, p Animal) []Point {
 }
 
 func pointsEqual(p1, p2 Point) bool {
-	return p1.x == p2.x && p1.y == p2.y
+	return p1 == p2
 }
 
 func allTogether(animals []Animal) bool {
@@ -79,156 +95,317 @@ func allTogether(animals []Animal) bool {
 func moveAnimal(a Animal) Animal {
 	a.previous = a.current
 	a.current = a.next
-	a.next = Point{-1, -1}
+	a.next = Point{X: -1, Y: -1}
 	return a
 }
 
-func charToTile(c rune) Tile {
+func charToTile(ctx context.Context, c rune) (Tile, error) {
 	switch c {
 	case '.':
-		return ground
+		return ground, nil
 	case 'S':
-		return starting
+		return starting, nil
 	case '|':
-		return north_south
+		return north_south, nil
 	case '-':
-		return east_west
+		return east_west, nil
 	case 'L':
-		return north_east
+		return north_east, nil
 	case 'J':
-		return north_west
+		return north_west, nil
 	case '7':
-		return south_west
+		return south_west, nil
 	case 'F':
-		return south_east
+		return south_east, nil
 	}
-	fmt.Fprintf(os.Stderr, "charToTile: unknown character %c\n", c)
-	return 0
+	// taken as ground unless strict
+	return ground, parse.Tolerate(ctx, parseLog, fmt.Errorf("unexpected %q", c))
 }
 
-func stringToTileRow(s string) []Tile {
-	tiles := make([]Tile, 0, len(s))
-	for _, c := range s {
-		tile := charToTile(c)
-		tiles = append(tiles, tile)
-	}
-	return tiles
+// readField reads the field of pipes, warning through ctx about any tile it
+// doesn't know.
+func readField(ctx context.Context, r io.Reader) (*grid.Grid[Tile], error) {
+	return grid.Read(r, func(c rune) (Tile, error) { return charToTile(ctx, c) })
 }
 
-func findStartingTile(field [][]Tile) Point {
-	for y, row := range field {
-		for x, tile := range row {
-			if tile == starting {
-				return Point{x, y}
-			}
-		}
-	}
-	return Point{-1, -1} // return Point{-1, -1} if "starting" is not found
+func findStartingTile(field *grid.Grid[Tile]) Point {
+	// returns Point{-1, -1} if "starting" is not found
+	start, _ := field.Find(func(tile Tile) bool { return tile == starting })
+	return start
 }
 
-func printField(field [][]Tile) {
-	for _, row := range field {
-		for _, tile := range row {
+func fieldString(field *grid.Grid[Tile]) string {
+	var b strings.Builder
+	for y := 0; y < field.Height(); y++ {
+		for _, tile := range field.Row(y) {
 			if tile == ground {
-				fmt.Fprint(os.Stdout, ".")
+				b.WriteByte('.')
 			} else if tile|footprint != 0 {
-				fmt.Fprint(os.Stdout, "*")
+				b.WriteByte('*')
 			} else if tile == starting {
-				fmt.Fprint(os.Stdout, "S")
+				b.WriteByte('S')
 			} else if tile|north_south != 0 {
-				fmt.Fprint(os.Stdout, "|")
+				b.WriteByte('|')
 			} else if tile|east_west != 0 {
-				fmt.Fprint(os.Stdout, "-")
+				b.WriteByte('-')
 			} else if tile|north_east != 0 {
-				fmt.Fprint(os.Stdout, "L")
+				b.WriteByte('L')
 			} else if tile|north_west != 0 {
-				fmt.Fprint(os.Stdout, "J")
+				b.WriteByte('J')
 			} else if tile|south_west != 0 {
-				fmt.Fprint(os.Stdout, "7")
+				b.WriteByte('7')
 			} else if tile|south_east != 0 {
-				fmt.Fprint(os.Stdout, "F")
+				b.WriteByte('F')
 			} else {
-				fmt.Fprint(os.Stdout, ".")
+				b.WriteByte('.')
 			}
 		}
-		fmt.Fprintln(os.Stdout, "")
+		b.WriteByte('\n')
 	}
+	return b.String()
 }
 
-func calculateLoopArea(field [][]Tile) int {
-	area := 0
+// insideLoop finds the tiles inside the loop, which walkLoop has to have
+// marked, by going along each row and counting the times it crosses it.
+func insideLoop(field *grid.Grid[Tile]) *grid.Grid[bool] {
+	inside := grid.New[bool](field.Width(), field.Height())
+	for y := 0; y < field.Height(); y++ {
+		in := false
+		for x, tile := range field.Row(y) {
+			switch {
+			case tile&footprint != 0 && tile&north != 0:
+				// the row crosses the loop here: a bend that goes north
+				// and the one that comes back south again cancel out,
+				// where a bend that goes on south does not
+				in = !in
+			case tile&footprint == 0:
+				inside.Set(Point{X: x, Y: y}, in)
+			}
+		}
+	}
+	return inside
+}
 
-	for _, row := range field {
-		inside := false
+func calculateLoopArea(field *grid.Grid[Tile]) int {
+	inside := insideLoop(field)
+	if aoc.Debugging() {
+		log.Debug("inside the loop\n" + grid.Map(field, func(p Point, tile Tile) rune {
+			switch {
+			case tile&footprint != 0:
+				return '*'
+			case inside.At(p):
+				return 'I'
+			}
+			return '.'
+		}).String())
+	}
+	return inside.Count(func(in bool) bool { return in })
+}
 
-		for x := 0; x < len(row); x++ {
-			if row[x]&footprint != 0 && row[x]&east_west != 0 && row[x]&north_south == 0 {
-				if row[x]&north_south != 0 {
-					fmt.Fprint(os.Stdout, "o")
-				} else {
-					fmt.Fprint(os.Stdout, "*")
+// Reference solves the puzzle by following the loop from S a tile at a time
+// and, for part 2, drawing it three times the size and filling in from the
+// edge, so that the gaps between pipes that touch are wide enough to get
+// through. It is slower than Solve but has nothing clever to get wrong, so
+// it checks it.
+func Reference(ctx context.Context, r io.Reader) (any, any, error) {
+	field, err := readField(ctx, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	start, ok := field.Find(func(tile Tile) bool { return tile == starting })
+	if !ok {
+		return nil, nil, fmt.Errorf("no starting tile")
+	}
+	shape := startShape(field, start)
+	if n := bits.OnesCount(uint(shape)); n != 2 {
+		return nil, nil, fmt.Errorf("S joins %d pipes, expected 2", n)
+	}
+	loop := map[Point]Tile{start: shape}
+	for previous, current := start, start; ; {
+		var next Point
+		for _, pipe := range pipes {
+			if loop[current]&pipe.from != 0 && current.Add(pipe.step) != previous {
+				next = current.Add(pipe.step)
+				if field.At(next)&pipe.to == 0 {
+					return nil, nil, fmt.Errorf("the loop is broken at %v", current)
 				}
-				continue
-			} else if row[x]&footprint != 0 && !inside {
-				inside = true
-				fmt.Fprint(os.Stdout, "*")
-			} else if row[x]&footprint != 0 && inside {
-				inside = false
-				fmt.Fprint(os.Stdout, "*")
-			} else if inside {
-				area++
-				fmt.Fprint(os.Stdout, "I")
-			} else {
-				fmt.Fprint(os.Stdout, ".")
+				break
+			}
+		}
+		if next == start {
+			break
+		}
+		loop[next] = field.At(next)
+		previous, current = current, next
+	}
+
+	// the big field has a border all round, so that the outside is one piece
+	big := grid.New[bool](3*field.Width()+2, 3*field.Height()+2)
+	for p, tile := range loop {
+		middle := Point{X: 3*p.X + 2, Y: 3*p.Y + 2}
+		big.Set(middle, true)
+		for _, pipe := range pipes {
+			if tile&pipe.from != 0 {
+				big.Set(middle.Add(pipe.step), true)
 			}
 		}
-		fmt.Fprintln(os.Stdout, "")
 	}
-	return area
+	outside := map[Point]bool{{X: 0, Y: 0}: true}
+	queue := []Point{{X: 0, Y: 0}}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for q, wall := range big.Neighbors4(p) {
+			if !wall && !outside[q] {
+				outside[q] = true
+				queue = append(queue, q)
+			}
+		}
+	}
+	area := 0
+	for p := range field.All() {
+		if _, on := loop[p]; !on && !outside[Point{X: 3*p.X + 2, Y: 3*p.Y + 2}] {
+			area++
+		}
+	}
+	return len(loop) / 2, area, nil
 }
 
-func main() {
-	var field [][]Tile
-	var animals []Animal
-	var start Point
-	var distance int
+// startShape works out which pipe S stands for from the pipes next to it
+// that join on to it.
+func startShape(field *grid.Grid[Tile], start Point) Tile {
+	var shape Tile
+	for _, pipe := range pipes {
+		if field.At(start.Add(pipe.step))&pipe.to != 0 {
+			shape |= pipe.from
+		}
+	}
+	return shape
+}
+
+// Pictures are the ways a field can be drawn once its loop has been found.
+var Pictures = []render.Picture{{Name: "loop", Draw: drawLoop}}
 
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		line := scanner.Text()
-		row := stringToTileRow(line)
-		field = append(field, row)
+// loopRunes draws each pipe as a line.
+var loopRunes = map[Tile]rune{
+	north_south: '│', east_west: '─', north_east: '└', north_west: '┘', south_west: '┐', south_east: '┌',
+}
+
+// drawLoop draws the loop with the tiles inside it filled in, the tiles
+// outside it shaded, and any pipe that isn't part of it faded.
+func drawLoop(ctx context.Context, r io.Reader) (*render.Frame, error) {
+	field, err := readField(ctx, r)
+	if err != nil {
+		return nil, err
 	}
-	if err := scanner.Err(); err != nil {
-		fmt.Fprintln(os.Stderr, "reading standard input:", err)
+	if _, err := walkLoop(ctx, field); err != nil {
+		return nil, err
 	}
-	start = findStartingTile(field)
-	distance = 0
+	inside := insideLoop(field)
+	return render.FromGrid(field, func(p Point, tile Tile) render.Cell {
+		switch {
+		case tile&footprint != 0:
+			return render.Cell{Rune: loopRunes[tile&^footprint], Fg: render.Yellow, Bg: render.Black}
+		case inside.At(p):
+			return render.Cell{Rune: 'I', Fg: render.Black, Bg: render.Green}
+		case tile == ground:
+			return render.Cell{Rune: '.', Fg: render.Grey, Bg: render.Navy}
+		}
+		return render.Cell{Rune: loopRunes[tile], Fg: render.Grey, Bg: render.Navy}
+	}), nil
+}
 
-	if start.x != -1 && start.y != -1 {
+// walkLoop sends an animal each way round the loop from S, marking every
+// tile they step on with footprint, and returns how far they went before
+// they met.
+func walkLoop(ctx context.Context, field *grid.Grid[Tile]) (int, error) {
+	var animals []Animal
+
+	start := findStartingTile(field)
+	if parse.Strict {
+		if start.X == -1 {
+			return 0, grid.ErrorAt(Point{X: field.Width(), Y: field.Height() - 1}, "no starting tile")
+		}
+		for p, tile := range field.All() {
+			if tile == starting && p != start {
+				return 0, grid.ErrorAt(p, "a second starting tile, after the one at line %d, column %d", start.Y+1, start.X+1)
+			}
+		}
+	}
+	distance := 0
+
+	if start.X != -1 && start.Y != -1 {
+		// S is drawn as whichever pipe it stands for, so that it is
+		// crossed like one when working out the area
+		shape := startShape(field, start)
+		if n := bits.OnesCount(uint(shape)); n != 2 {
+			return 0, fmt.Errorf("S joins %d pipes, expected 2", n)
+		}
+		field.Set(start, shape|footprint)
 		choices := availableDirections(field, start)
 		for i := 0; i < len(choices); i++ {
-			animals = append(animals, Animal{start, choices[i], Point{-1, -1}})
-			field[animals[i].current.y][animals[i].current.x] |= footprint
+			animals = append(animals, Animal{start, choices[i], Point{X: -1, Y: -1}})
+			field.Set(animals[i].current, field.At(animals[i].current)|footprint)
 		}
 		distance++
 	}
 
 	for !allTogether(animals) {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		for i := 0; i < len(animals); i++ {
 			animals[i].next = animals[i].current
 			choices := availableDirections(field, animals[i].current)
 			choices = removePreviousDirection(choices, animals[i])
-			if len(choices) > 0 {
-				animals[i].next = choices[0]
+			if len(choices) == 0 {
+				return 0, fmt.Errorf("the loop is broken at %v", animals[i].current)
 			}
+			animals[i].next = choices[0]
 			animals[i] = moveAnimal(animals[i])
-			field[animals[i].current.y][animals[i].current.x] |= footprint
+			field.Set(animals[i].current, field.At(animals[i].current)|footprint)
 		}
 		distance++
+		if render.Screen != nil {
+			drawChase(field, animals)
+		}
+	}
+	return distance, nil
+}
+
+// drawChase shows the animals on their way round the loop, with the pipe
+// they have been along so far lit up.
+func drawChase(field *grid.Grid[Tile], animals []Animal) {
+	render.Screen.Draw(render.FromGrid(field, func(p Point, tile Tile) render.Cell {
+		for _, animal := range animals {
+			if animal.current == p {
+				return render.Cell{Rune: loopRunes[tile&^footprint], Fg: render.White, Bg: render.Red}
+			}
+		}
+		switch {
+		case tile&footprint != 0:
+			return render.Cell{Rune: loopRunes[tile&^footprint], Fg: render.Yellow, Bg: render.Black}
+		case tile == ground:
+			return render.Cell{Rune: '.', Fg: render.Grey, Bg: render.Black}
+		}
+		return render.Cell{Rune: loopRunes[tile], Fg: render.Grey, Bg: render.Black}
+	}))
+}
+
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	field, err := readField(ctx, r)
+	if err != nil {
+		return nil, nil, err
 	}
-	fmt.Println(distance)
-	fmt.Println(calculateLoopArea(field))
+	distance, err := walkLoop(ctx, field)
+	if err != nil {
+		return nil, nil, err
+	}
+	aoc.Part1Done(ctx)
+	area := calculateLoopArea(field)
 
-	printField(field)
+	if aoc.Debugging() {
+		log.Debug("field\n" + fieldString(field))
+	}
+	return distance, area, nil
 }
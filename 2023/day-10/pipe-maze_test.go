@@ -0,0 +1,83 @@
+package day10
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/grid"
+	"github.com/havill/AdventOfCode/parse"
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+// tileChars draws each tile as it is in the input.
+var tileChars = map[Tile]rune{
+	ground: '.', starting: 'S', north_south: '|', east_west: '-',
+	north_east: 'L', north_west: 'J', south_west: '7', south_east: 'F',
+}
+
+func writeField(field *grid.Grid[Tile]) string {
+	return grid.Map(field, func(_ grid.Point, tile Tile) rune { return tileChars[tile] }).String()
+}
+
+func FuzzReadField(f *testing.F) {
+	parsetest.Seed(f,
+		"..F7.\n.FJ|.\nSJ.L7\n|F--J\nLJ...\n",
+		"-L|F7\n7S-7|\nL|7||\n-L-J|\nL|-JF\n",
+		"S?\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, parsetest.Background(readField), writeField)
+	})
+}
+
+func TestStrictStart(t *testing.T) {
+	defer func(was bool) { parse.Strict = was }(parse.Strict)
+	parse.Strict = true
+	for input, want := range map[string]string{
+		".S-7.\n.|.|.\n.L-JS\n": "line 3, column 5: a second starting tile, after the one at line 1, column 2",
+		"F-7\nL-J\n":            "line 2, column 4: no starting tile",
+	} {
+		if _, _, err := Solve(context.Background(), strings.NewReader(input)); err == nil || err.Error() != want {
+			t.Errorf("%q: got %v, want %s", input, err, want)
+		}
+	}
+}
+
+// TestStartAndBends covers the part 2 fix: S counts as the pipe it stands
+// for, only bends that go north flip inside and outside, and a loop that
+// doesn't close is an error rather than a walk that never ends.
+func TestStartAndBends(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		distance int
+		area     int
+		err      string
+	}{
+		{"S as a south-east bend", ".....\n.S-7.\n.|.|.\n.L-J.\n.....\n", 4, 1, ""},
+		{"S as an upright pipe", "F-7..\n|.L-7\nS...|\nL---J\n", 7, 4, ""},
+		{"a bend that turns back", ".F-7F7.\n.|.LJ|.\n.S---J.\n", 7, 1, ""},
+		{"a loop that doesn't close", "S-7\n|.|\nL-.\n", 0, 0, "the loop is broken"},
+		{"S on one pipe", "S-7\n..|\n", 0, 0, "S joins 1 pipes, expected 2"},
+	}
+	for _, tt := range tests {
+		for _, solver := range []struct {
+			name  string
+			solve func(context.Context, io.Reader) (any, any, error)
+		}{{"Solve", Solve}, {"Reference", Reference}} {
+			distance, area, err := solver.solve(context.Background(), strings.NewReader(tt.input))
+			switch {
+			case tt.err != "":
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("%s, %s: got %v, want %q", tt.name, solver.name, err, tt.err)
+				}
+			case err != nil:
+				t.Errorf("%s, %s: %v", tt.name, solver.name, err)
+			case distance != tt.distance || area != tt.area:
+				t.Errorf("%s, %s: got %v and %v, want %d and %d", tt.name, solver.name, distance, area, tt.distance, tt.area)
+			}
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package day11
+
+import (
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes a size by size image of galaxies, with about one row and
+// column in eight left empty to be expanded.
+func Generate(rng *rand.Rand, size int) []byte {
+	size = gen.Clamp(size, 2, 140)
+	emptyRow, emptyCol := make([]bool, size), make([]bool, size)
+	for i := range size {
+		emptyRow[i] = rng.IntN(8) == 0
+		emptyCol[i] = rng.IntN(8) == 0
+	}
+	return gen.Grid(size, size, func(x, y int) byte {
+		if emptyRow[y] || emptyCol[x] || rng.IntN(20) != 0 {
+			return '.'
+		}
+		return '#'
+	})
+}
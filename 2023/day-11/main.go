@@ -1,32 +1,50 @@
-package main
+package day11
 
 import (
-	_ "embed"
-	"flag"
-	"fmt"
+	"bytes"
+	"context"
+	"errors"
+	"io"
 	"strings"
-)
 
-var input string
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/grid"
+	"github.com/havill/AdventOfCode/parse"
+)
 
-func init() {
-	input = strings.TrimRight(input, "\n")
+// readImage reads the image as it is, less the newlines at the end. In
+// strict mode it has to be a grid of '.' and '#'.
+func readImage(r io.Reader) (string, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	if parse.Strict {
+		if _, err := grid.Read(bytes.NewReader(raw), grid.Expect(".#", grid.Runes)); err != nil {
+			return "", err
+		}
+	}
+	input := strings.TrimRight(string(raw), "\n")
 	if len(input) == 0 {
-		panic("empty input.txt file")
+		return "", errors.New("empty input")
 	}
+	return input, nil
 }
 
-func main() {
-	var part int
-	flag.IntVar(&part, "part", 1, "part 1 or 2")
-	flag.Parse()
-	fmt.Printf("Running part %d...\n", part)
-
-	if part == 1 {
-		ans := part1(input)
-		fmt.Println(ans)
-	} else {
-		ans := part2(input)
-		fmt.Println(ans)
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	input, err := readImage(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	aoc.Parsed(ctx)
+	answer1, err := part1(ctx, input)
+	if err != nil {
+		return nil, nil, err
+	}
+	aoc.Part1Done(ctx)
+	answer2, err := part2(ctx, input)
+	if err != nil {
+		return answer1, nil, err
 	}
+	return answer1, answer2, nil
 }
@@ -0,0 +1,21 @@
+package day11
+
+import (
+	"testing"
+
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+func writeImage(image string) string {
+	return image + "\n"
+}
+
+func FuzzReadImage(f *testing.F) {
+	parsetest.Seed(f,
+		"...#......\n.......#..\n#.........\n..........\n",
+		"#.\n.\n\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, readImage, writeImage)
+	})
+}
@@ -1,11 +1,12 @@
-package main
+package day11
 
 import (
+	"context"
 	"math"
 	"strings"
 )
 
-func part1(input string) any {
+func part1(ctx context.Context, input string) (any, error) {
 	grid := strings.Split(input, "\n")
 	expandedRows := make([]bool, len(grid))
 	expandedCols := make([]bool, len(grid[0]))
@@ -47,6 +48,9 @@ func part1(input string) any {
 	res := 0
 
 	for i := range galaxies {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		for j := range galaxies[i] {
 			if galaxies[i][j] == -1 {
 				continue
@@ -57,10 +61,10 @@ func part1(input string) any {
 		}
 	}
 
-	return res
+	return res, nil
 }
 
-func part2(input string) any {
+func part2(ctx context.Context, input string) (any, error) {
 	grid := strings.Split(input, "\n")
 	expandedRows := make([]bool, len(grid))
 	expandedCols := make([]bool, len(grid[0]))
@@ -102,6 +106,9 @@ func part2(input string) any {
 	res := 0
 
 	for i := range galaxies {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		for j := range galaxies[i] {
 			if galaxies[i][j] == -1 {
 				continue
@@ -112,7 +119,7 @@ func part2(input string) any {
 		}
 	}
 
-	return res
+	return res, nil
 }
 
 type coord struct {
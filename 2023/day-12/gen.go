@@ -0,0 +1,38 @@
+package day12
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes size condition records. Each is drawn from a row of
+// springs that fits its groups, before some of the springs are made
+// unknown; no more than ten, so that trying every way of filling them in
+// can check the count.
+func Generate(rng *rand.Rand, size int) []byte {
+	var b bytes.Buffer
+	for range size {
+		var springs []byte
+		var groups []string
+		springs = append(springs, bytes.Repeat([]byte{'.'}, rng.IntN(3))...)
+		for i := range gen.Between(rng, 1, 5) {
+			if i > 0 {
+				springs = append(springs, bytes.Repeat([]byte{'.'}, gen.Between(rng, 1, 3))...)
+			}
+			n := gen.Between(rng, 1, 5)
+			springs = append(springs, bytes.Repeat([]byte{'#'}, n)...)
+			groups = append(groups, fmt.Sprint(n))
+		}
+		springs = append(springs, bytes.Repeat([]byte{'.'}, rng.IntN(3))...)
+
+		for range rng.IntN(min(len(springs), 10) + 1) {
+			springs[rng.IntN(len(springs))] = '?'
+		}
+		fmt.Fprintf(&b, "%s %s\n", springs, strings.Join(groups, ","))
+	}
+	return b.Bytes()
+}
@@ -1,19 +1,24 @@
-package main
+package day12
 
-import "C"
 import (
-	"bufio"
+	"context"
 	"fmt"
-	"os"
+	"io"
 	"regexp"
 	"strconv"
 	"strings"
-	"time"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/parse"
 )
 
+var searchLog = aoc.Logger("search")
+
 func groupMatchesRecord(re *regexp.Regexp, conditionRecord string) bool {
 	match := re.MatchString(conditionRecord)
-	fmt.Println("groupMatchesRecord: ", conditionRecord, match)
+	if aoc.Tracing() {
+		aoc.Trace(searchLog, "candidate", "record", conditionRecord, "match", match)
+	}
 	return match
 }
 
@@ -25,7 +30,6 @@ func testCombosHelper(re *regexp.Regexp, brokenGroupsSum int, conditionRecord st
 	}
 	if len(conditionRecord) == 0 {
 		if groupMatchesRecord(re, current) {
-			//fmt.Println(current)
 			total++
 		}
 		return total
@@ -63,6 +67,20 @@ func splitString(input string) (string, string) {
 	return left, right
 }
 
+// checkRecord is the strict reading of a line: the springs, one space, and
+// the sizes of the groups of damaged ones.
+func checkRecord(l parse.Line) error {
+	springs, groups, err := l.Cut(" ")
+	if err != nil {
+		return err
+	}
+	if err := springs.Only(".#?"); err != nil {
+		return err
+	}
+	_, err = groups.IntList(",")
+	return err
+}
+
 func stringToIntSlice(input string) ([]int, error) {
 	parts := strings.Split(input, ",")
 	result := make([]int, len(parts))
@@ -114,60 +132,130 @@ func repeatIntSlice(contiguousGroups []int, folds int) []int {
 	return result
 }
 
-func main() {
-	total := 0
-	unfoldedTotal := 0
-	scanner := bufio.NewScanner(os.Stdin)
-	progress := 0
-
-	//fmt.Println("Please enter some lines of text. Press CTRL+D to end input.")
-	for scanner.Scan() {
-		var sum int
-		var pattern string
-		var re *regexp.Regexp
-
-		line := scanner.Text()
-		progress++
-		fmt.Println("processing line #", progress, "at", time.Now())
-		left, right := splitString(line)
-		//fmt.Println("Left: ", left)
-		//fmt.Println("Right: ", right)
+// record is a row of springs, some of whose conditions are unknown, and the
+// sizes of the groups of damaged springs in it.
+type record struct {
+	springs string
+	groups  []int
+}
+
+func parseRecords(r io.Reader) ([]record, error) {
+	var records []record
+
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range lines {
+		if parse.Strict {
+			if err := checkRecord(l); err != nil {
+				return nil, err
+			}
+		}
+		left, right := splitString(l.Text)
 		brokenGroups, err := stringToIntSlice(right)
 		if err != nil {
-			fmt.Println("Error: ", err)
-			return
+			return nil, l.Errorf("%v", err)
 		}
-		//fmt.Println("Broken groups: ", brokenGroups)
-		sum = sumArray(brokenGroups)
-		//fmt.Println("Sum: ", sum)
-		pattern = convertSliceToString(brokenGroups)
-		//fmt.Println("Uncompiled: ", pattern)
-		re, err = regexp.Compile(pattern)
+		records = append(records, record{springs: left, groups: brokenGroups})
+	}
+	return records, nil
+}
+
+// enumerate counts the arrangements of a record by filling in the unknown
+// springs every way they could be and matching each against a regular
+// expression of the groups. Part 2 would take forever like this, but it is
+// the plainest reading of the puzzle, so it checks arrangements.
+func enumerate(rec record) (int, error) {
+	re, err := regexp.Compile(convertSliceToString(rec.groups))
+	if err != nil {
+		return 0, fmt.Errorf("error compiling regex: %v", err)
+	}
+	return testAllCombos(re, sumArray(rec.groups), rec.springs), nil
+}
+
+// Reference answers part 1 by listing every arrangement. Part 2 is out of
+// its reach.
+func Reference(ctx context.Context, r io.Reader) (any, any, error) {
+	records, err := parseRecords(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	progress := aoc.NewProgress(ctx, "records", len(records))
+	total := 0
+	for _, rec := range records {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		n, err := enumerate(rec)
 		if err != nil {
-			fmt.Println("Error compiling regex:", err)
-			return
+			return nil, nil, err
+		}
+		total += n
+		progress.Add(1)
+	}
+	progress.Done()
+	return total, nil, nil
+}
+
+// arrangements counts the arrangements of a record by working back from
+// the end of the row: ways[i][j] is how many there are of the last groups
+// from j in the springs from i.
+func arrangements(rec record) int {
+	springs, groups := rec.springs, rec.groups
+	ways := make([][]int, len(springs)+2)
+	for i := range ways {
+		ways[i] = make([]int, len(groups)+1)
+	}
+	ways[len(springs)][len(groups)] = 1
+	ways[len(springs)+1][len(groups)] = 1 // past the end, after a group that ran to it
+
+	for i := len(springs) - 1; i >= 0; i-- {
+		for j := len(groups); j >= 0; j-- {
+			if springs[i] != '#' {
+				ways[i][j] += ways[i+1][j]
+			}
+			if j == len(groups) {
+				continue
+			}
+			end := i + groups[j]
+			if groups[j] > 0 && end <= len(springs) && !strings.Contains(springs[i:end], ".") &&
+				(end == len(springs) || springs[end] != '#') {
+				ways[i][j] += ways[end+1][j+1]
+			}
+		}
+	}
+	return ways[0][0]
+}
+
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	records, err := parseRecords(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	aoc.Parsed(ctx)
+	progress := aoc.NewProgress(ctx, "records", 2*len(records))
+
+	total := 0
+	for i, rec := range records {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
 		}
-		total += testAllCombos(re, sum, left)
-
-		/*
-			// Part 2
-			unfoldedConditions := repeatString(left, 5)
-			unfoldedBrokenGroups := repeatIntSlice(brokenGroups, 5)
-			//fmt.Println("Unfolded conditions: ", unfoldedConditions)
-			//fmt.Println("Unfolded broken groups: ", unfoldedBrokenGroups)
-			sum = sumArray(unfoldedBrokenGroups)
-			//fmt.Println("Sum: ", sum)
-			pattern = convertSliceToString(unfoldedBrokenGroups)
-			//fmt.Println("Uncompiled: ", pattern)
-			re, _ = regexp.Compile(pattern)
-			matches := testAllCombos(re, sum, unfoldedConditions)
-			//fmt.Println("Matches: ", matches)
-			unfoldedTotal += matches
-		*/
-	}
-	if err := scanner.Err(); err != nil {
-		fmt.Fprintln(os.Stderr, "reading standard input:", err)
-	}
-	fmt.Println("Total matches (part 1): ", total)
-	fmt.Println("Total matches (part 2): ", unfoldedTotal)
+		n := arrangements(rec)
+		searchLog.Debug("line", "number", i+1, "record", rec.springs, "groups", rec.groups, "arrangements", n)
+		total += n
+		progress.Add(1)
+	}
+	aoc.Part1Done(ctx)
+
+	unfoldedTotal := 0
+	for _, rec := range records {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		unfoldedTotal += arrangements(record{repeatString(rec.springs, 5), repeatIntSlice(rec.groups, 5)})
+		progress.Add(1)
+	}
+	progress.Done()
+	return total, unfoldedTotal, nil
 }
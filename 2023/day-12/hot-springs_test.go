@@ -0,0 +1,53 @@
+package day12
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/gen/gentest"
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+func writeRecords(records []record) string {
+	var b strings.Builder
+	for _, r := range records {
+		groups := make([]string, len(r.groups))
+		for i, g := range r.groups {
+			groups[i] = strconv.Itoa(g)
+		}
+		fmt.Fprintf(&b, "%s %s\n", r.springs, strings.Join(groups, ","))
+	}
+	return b.String()
+}
+
+func FuzzParseRecords(f *testing.F) {
+	parsetest.Seed(f,
+		"???.### 1,1,3\n.??..??...?##. 1,1,3\n?#?#?#?#?#?#?#? 1,3,1,6\n",
+		"????.#...#... 4, 1,1\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, parseRecords, writeRecords)
+	})
+}
+
+func TestArrangements(t *testing.T) {
+	total := func(count func(record) (int, error)) func(io.Reader) (int, error) {
+		return func(r io.Reader) (int, error) {
+			records, err := parseRecords(r)
+			sum := 0
+			for _, rec := range records {
+				n, err := count(rec)
+				if err != nil {
+					return 0, err
+				}
+				sum += n
+			}
+			return sum, err
+		}
+	}
+	fast := func(rec record) (int, error) { return arrangements(rec), nil }
+	gentest.Compare(t, Generate, 40, 200, total(fast), total(enumerate))
+}
@@ -0,0 +1,34 @@
+package day13
+
+import (
+	"bytes"
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes notes of about size lines, in patterns of ash and rocks
+// that each reflect either across a row or down a column.
+func Generate(rng *rand.Rand, size int) []byte {
+	var b bytes.Buffer
+	for i := range max(size/10, 1) {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		height, width := gen.Between(rng, 2, 15), gen.Between(rng, 1, 15)
+		rows := make([][]byte, height)
+		for y := range rows {
+			rows[y] = []byte(gen.Word(rng, width, "#."))
+		}
+		mirror := gen.Between(rng, 1, height-1)
+		for y := mirror; y < height && 2*mirror-1-y >= 0; y++ {
+			copy(rows[y], rows[2*mirror-1-y])
+		}
+		if rng.IntN(2) == 0 {
+			b.Write(gen.Grid(width, height, func(x, y int) byte { return rows[y][x] }))
+		} else {
+			b.Write(gen.Grid(height, width, func(x, y int) byte { return rows[x][y] }))
+		}
+	}
+	return b.Bytes()
+}
@@ -1,57 +1,35 @@
-package main
+package day13
 
 import (
-	"bufio"
-	"fmt"
-	"os"
-	"strings"
-)
-
-type imgs struct {
-	img []img
-}
-
-func newImages() imgs {
-	return imgs{img: make([]img, 0)}
-}
-
-type img struct {
-	raw []string
-}
-
-func newImg() img {
-	return img{raw: make([]string, 0)}
-}
-
-func (i img) transposed() []string {
-	transposed := []string{}
+	"context"
+	"io"
+	"slices"
 
-	for x := 0; x < len(i.raw[0]); x++ {
-		buf := ""
-		for j := len(i.raw) - 1; j >= 0; j-- {
-			buf += string(i.raw[j][x])
-		}
-		transposed = append(transposed, buf)
-	}
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/grid"
+	"github.com/havill/AdventOfCode/parse"
+)
 
-	return transposed
-}
+var log = aoc.Logger("search")
 
-var images imgs
+// pattern is one of the notes' pictures of ash and rocks.
+type pattern = *grid.Grid[rune]
 
-func findReflection(image []string) (int, bool) {
+func findReflection(image pattern) (int, bool) {
 	walkToEdge := func(lower, upper int) bool {
-		for lower >= 0 && upper < len(image) && strings.Compare(image[lower], image[upper]) == 0 {
+		for lower >= 0 && upper < image.Height() && slices.Equal(image.Row(lower), image.Row(upper)) {
 			lower--
 			upper++
 		}
 		lower++
-		return (lower == 0 || upper == len(image))
+		return (lower == 0 || upper == image.Height())
 	}
 
-	for i := 0; i < len(image)-1; i++ {
-		if strings.Compare(image[i], image[i+1]) == 0 {
-			fmt.Fprintln(os.Stderr, "start point: ", image[i], " vs ", image[i+1])
+	for i := 0; i < image.Height()-1; i++ {
+		if slices.Equal(image.Row(i), image.Row(i+1)) {
+			if aoc.Tracing() {
+				aoc.Trace(log, "start point", "line", i, "row", string(image.Row(i)))
+			}
 			if walkToEdge(i, i+1) {
 				return i, true
 			}
@@ -60,20 +38,22 @@ func findReflection(image []string) (int, bool) {
 	return 0, false
 }
 
-func horizontalReflection(image img, fn func(image []string) (int, bool)) (int, bool) {
-	return fn(image.raw)
+func horizontalReflection(image pattern, fn func(image pattern) (int, bool)) (int, bool) {
+	return fn(image)
 }
 
-func verticalReflection(image img, fn func(image []string) (int, bool)) (int, bool) {
-	return fn(image.transposed())
+func verticalReflection(image pattern, fn func(image pattern) (int, bool)) (int, bool) {
+	return fn(image.Transpose())
 }
 
-func findReflectionWithDifference(image []string) (int, bool) {
+func findReflectionWithDifference(image pattern) (int, bool) {
 	walkToEdge := func(lower, upper int) bool {
 		diffs := 0
-		for lower >= 0 && upper < len(image) {
-			n := numDiffs(image[lower], image[upper])
-			fmt.Fprintln(os.Stderr, n, " difference(s) found at line ", lower)
+		for lower >= 0 && upper < image.Height() {
+			n := numDiffs(image.Row(lower), image.Row(upper))
+			if aoc.Tracing() {
+				aoc.Trace(log, "differences", "line", lower, "count", n)
+			}
 			diffs += n
 			lower--
 			upper++
@@ -82,8 +62,10 @@ func findReflectionWithDifference(image []string) (int, bool) {
 		return diffs == 1
 	}
 
-	for i := 0; i < len(image)-1; i++ {
-		fmt.Fprintln(os.Stderr, "start point: ", image[i], " vs ", image[i+1])
+	for i := 0; i < image.Height()-1; i++ {
+		if aoc.Tracing() {
+			aoc.Trace(log, "start point", "line", i, "rows", []string{string(image.Row(i)), string(image.Row(i + 1))})
+		}
 		if walkToEdge(i, i+1) {
 			return i, true
 		}
@@ -91,7 +73,7 @@ func findReflectionWithDifference(image []string) (int, bool) {
 	return 0, false
 }
 
-func numDiffs(a, b string) int {
+func numDiffs(a, b []rune) int {
 	diff := 0
 	for i := range a {
 		if a[i] != b[i] {
@@ -101,86 +83,118 @@ func numDiffs(a, b string) int {
 	return diff
 }
 
-func findSmudge(lines []string) int {
+func findSmudge(images []pattern) int {
 	sum := 0
-	for _, image := range images.img {
-		fmt.Fprintln(os.Stderr, "checking for horizontal reflection")
+	for _, image := range images {
 		n, ok := horizontalReflection(image, findReflectionWithDifference)
 		if ok {
 			sum += 100 * (n + 1)
 		}
-		fmt.Fprintln(os.Stderr, "checking for vertical reflection")
+		log.Debug("smudged horizontal reflection", "found", ok, "after", n+1)
 		n, ok = verticalReflection(image, findReflectionWithDifference)
 		if ok {
 			sum += (n + 1)
 		}
+		log.Debug("smudged vertical reflection", "found", ok, "after", n+1)
 	}
 
 	return sum
 }
 
-func reflectionLines(lines []string) int {
-	parselines(lines)
-
+func reflectionLines(images []pattern) int {
 	sum := 0
-	for _, image := range images.img {
-		fmt.Fprintln(os.Stderr, "checking for horizontal reflection")
+	for _, image := range images {
 		n, ok := horizontalReflection(image, findReflection)
 		if ok {
 			sum += 100 * (n + 1)
 		}
-		fmt.Fprintln(os.Stderr, "checking for vertical reflection")
+		log.Debug("horizontal reflection", "found", ok, "after", n+1)
 		n, ok = verticalReflection(image, findReflection)
 		if ok {
 			sum += (n + 1)
 		}
+		log.Debug("vertical reflection", "found", ok, "after", n+1)
 	}
 	return sum
 }
 
-func parselines(lines []string) {
-	addImage := func(i int) int {
-		image := newImg()
-		for ; i < len(lines); i++ {
-			if len(lines[i]) == 0 {
-				break
-			}
-			image.raw = append(image.raw, lines[i])
+// parselines splits the notes into their patterns at the blank lines.
+func parselines(lines []string) ([]pattern, error) {
+	var images []pattern
+	var rows []parse.Line
+	for i := 0; i <= len(lines); i++ {
+		if i < len(lines) && len(lines[i]) != 0 {
+			rows = append(rows, parse.NewLine(i+1, lines[i]))
+			continue
 		}
-		images.img = append(images.img, image)
-		return i
-	}
-
-	images = newImages()
-
-	for i := 0; i < len(lines); i++ {
-		next := addImage(i)
-		i = next
+		if len(rows) == 0 {
+			continue
+		}
+		image, err := grid.FromLines(rows, grid.Runes)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, image)
+		rows = nil
 	}
+	return images, nil
 }
 
-func readlines() []string {
-	lines := []string{}
-
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
+func readlines(r io.Reader) ([]string, error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
 	}
-
-	if err := scanner.Err(); err != nil {
-		fmt.Fprintf(os.Stderr, "%v", err)
-		os.Exit(1)
+	if parse.Strict {
+		if err := checkPatterns(lines); err != nil {
+			return nil, err
+		}
 	}
-
-	return lines
+	text := make([]string, len(lines))
+	for i, line := range lines {
+		text[i] = line.Text
+	}
+	return text, nil
 }
 
-func main() {
-	lines := readlines()
+// checkPatterns is the strict reading of the notes: rectangles of ash and
+// rocks, separated by blank lines.
+func checkPatterns(lines []parse.Line) error {
+	start := 0
+	for i := 0; i <= len(lines); i++ {
+		if i < len(lines) && !lines[i].Blank() {
+			continue
+		}
+		if i == start {
+			if i < len(lines) {
+				return lines[i].Errorf("expected a pattern")
+			}
+			break
+		}
+		if _, err := grid.FromLines(lines[start:i], grid.Expect(".#", grid.Runes)); err != nil {
+			return err
+		}
+		start = i + 1
+	}
+	return nil
+}
 
-	noteSummary := reflectionLines(lines)
-	fmt.Printf("%d\n", noteSummary)
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	lines, err := readlines(r)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	noteSummary = findSmudge(lines)
-	fmt.Printf("%d\n", noteSummary)
+	images, err := parselines(lines)
+	if err != nil {
+		return nil, nil, err
+	}
+	aoc.Parsed(ctx)
+	noteSummary := reflectionLines(images)
+	aoc.Part1Done(ctx)
+	if err := ctx.Err(); err != nil {
+		return noteSummary, nil, err
+	}
+	smudgedSummary := findSmudge(images)
+	return noteSummary, smudgedSummary, nil
 }
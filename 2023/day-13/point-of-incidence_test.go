@@ -0,0 +1,27 @@
+package day13
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+func writeNotes(lines []string) string {
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func FuzzReadlines(f *testing.F) {
+	parsetest.Seed(f,
+		"#.##..##.\n..#.##.#.\n##......#\n\n#...##..#\n#....#..#\n..##..###\n",
+		"#.\n\n\n.#x\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, readlines, writeNotes)
+	})
+}
@@ -0,0 +1,13 @@
+package day14
+
+import (
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes a size by size platform of rounded and cube-shaped rocks.
+func Generate(rng *rand.Rand, size int) []byte {
+	size = gen.Clamp(size, 1, 100)
+	return gen.Grid(size, size, func(x, y int) byte { return gen.Pick(rng, "OO#.......") })
+}
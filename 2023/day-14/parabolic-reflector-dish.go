@@ -1,131 +1,129 @@
-package main
+package day14
 
 import (
-	"bufio"
-	"fmt"
-	"os"
-)
-
-func rotate(lines [][]byte) [][]byte {
-	rows := len(lines)
-	cols := len(lines[0])
+	"context"
+	"io"
 
-	rotated := make([][]byte, cols)
-	for i := 0; i < cols; i++ {
-		rotated[i] = make([]byte, rows)
-		for j := 0; j < rows; j++ {
-			rotated[i][j] = lines[rows-1-j][i]
-		}
-	}
-	return rotated
-}
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/grid"
+	"github.com/havill/AdventOfCode/render"
+)
 
-func moveNorth(lines [][]byte) {
-	for i := 1; i < len(lines); i++ {
-		for j := 0; j < len(lines[i]); j++ {
+func moveNorth(lines *grid.Grid[byte]) {
+	for i := 1; i < lines.Height(); i++ {
+		for j := 0; j < lines.Width(); j++ {
 			for k := i - 1; k >= 0; k-- {
-				if lines[k][j] == '.' && lines[k+1][j] == 'O' {
-					lines[k][j] = 'O'
-					lines[k+1][j] = '.'
+				above, below := grid.Point{X: j, Y: k}, grid.Point{X: j, Y: k + 1}
+				if lines.At(above) == '.' && lines.At(below) == 'O' {
+					lines.Set(above, 'O')
+					lines.Set(below, '.')
 				}
 			}
 		}
 	}
 }
 
-func move(dir byte, lines [][]byte) {
+func move(dir byte, lines *grid.Grid[byte]) *grid.Grid[byte] {
 	switch dir {
 	case 'N':
 		moveNorth(lines)
 	case 'S':
-		temp := rotate(rotate(lines))
-		moveNorth(temp)
-		temp = rotate(rotate(temp))
-		copy(lines, temp)
+		lines = lines.Rotate().Rotate()
+		moveNorth(lines)
+		lines = lines.Rotate().Rotate()
 	case 'W':
-		temp := rotate(lines)
-		moveNorth(temp)
-		temp = rotate(rotate(rotate(temp)))
-		copy(lines, temp)
+		lines = lines.Rotate()
+		moveNorth(lines)
+		lines = lines.Rotate().Rotate().Rotate()
 	case 'E':
-		temp := rotate(rotate(rotate(lines)))
-		moveNorth(temp)
-		temp = rotate(temp)
-		copy(lines, temp)
+		lines = lines.Rotate().Rotate().Rotate()
+		moveNorth(lines)
+		lines = lines.Rotate()
 	}
+	return lines
 }
 
-func rockTotal(lines [][]byte) int {
+func rockTotal(lines *grid.Grid[byte]) int {
 	n := 0
-	for i := 0; i < len(lines); i++ {
-		for j := 0; j < len(lines[i]); j++ {
-			if lines[i][j] == 'O' {
-				n += len(lines) - i
-			}
+	for p, rock := range lines.All() {
+		if rock == 'O' {
+			n += lines.Height() - p.Y
 		}
 	}
 	return n
 }
 
-func cp(lines [][]byte) [][]byte {
-	l1 := make([][]byte, len(lines))
-	for i, row := range lines {
-		l1[i] = append([]byte(nil), row...)
-	}
-	return l1
-}
-
-func goNorth(lines [][]byte) {
-	l1 := cp(lines)
-	move('N', l1)
-	fmt.Println(rockTotal(l1))
+func goNorth(lines *grid.Grid[byte]) int {
+	return rockTotal(move('N', lines.Clone()))
 }
 
-func key(lines [][]byte) string {
+func key(lines *grid.Grid[byte]) string {
 	key := ""
-	for i := 0; i < len(lines); i++ {
-		key += string(lines[i])
+	for i := 0; i < lines.Height(); i++ {
+		key += string(lines.Row(i))
 	}
 	return key
 }
 
-func spinCycle(lines [][]byte) {
+// drawPlatform shows where the rocks are after a tilt.
+func drawPlatform(lines *grid.Grid[byte]) {
+	render.Screen.Draw(render.FromGrid(lines, func(_ grid.Point, c byte) render.Cell {
+		switch c {
+		case 'O':
+			return render.Cell{Rune: 'O', Fg: render.Black, Bg: render.White}
+		case '#':
+			return render.Cell{Rune: '#', Fg: render.Grey, Bg: render.Black}
+		}
+		return render.Cell{Rune: '.', Fg: render.Grey, Bg: render.Black}
+	}))
+}
+
+func spinCycle(ctx context.Context, lines *grid.Grid[byte]) (int, error) {
 	cache := map[string]int{}
-	revCache := map[int][][]byte{}
+	revCache := map[int]*grid.Grid[byte]{}
 	n := 0
 	start := 0
 	period := 0
 	for ; ; n++ {
-		if cache[key(lines)] > 0 {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		if _, seen := cache[key(lines)]; seen {
 			if start == 0 {
 				start = n
 				cache = map[string]int{}
 			} else {
 				// second time
-				period = n - start - 1
+				period = n - start
 				break
 			}
-		} else {
-			cache[key(lines)] = n
-			revCache[n] = cp(lines)
 		}
+		cache[key(lines)] = n
+		revCache[n] = lines.Clone()
 
-		move('N', lines)
-		move('W', lines)
-		move('S', lines)
-		move('E', lines)
+		for _, dir := range []byte("NWSE") {
+			lines = move(dir, lines)
+			if render.Screen != nil {
+				drawPlatform(lines)
+			}
+		}
 	}
 
 	temp := revCache[start+(1000000000-start)%period] // fuck slices
-	fmt.Println(rockTotal(temp))
+	return rockTotal(temp), nil
 }
 
-func main() {
-	scanner := bufio.NewScanner(os.Stdin)
-	lines := [][]byte{} // hope we don't run out of memory
-	for scanner.Scan() {
-		lines = append(lines, []byte(scanner.Text()))
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	lines, err := grid.Read(r, grid.Expect("O#.", grid.Bytes))
+	if err != nil {
+		return nil, nil, err
+	}
+	aoc.Parsed(ctx)
+	part1 := goNorth(lines)
+	aoc.Part1Done(ctx)
+	part2, err := spinCycle(ctx, lines)
+	if err != nil {
+		return part1, nil, err
 	}
-	goNorth(lines)
-	spinCycle(lines)
+	return part1, part2, nil
 }
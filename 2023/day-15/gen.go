@@ -0,0 +1,29 @@
+package day15
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes an initialization sequence of ten times size steps, all on
+// the one line, that put lenses in and take them out again under size
+// labels.
+func Generate(rng *rand.Rand, size int) []byte {
+	labels := make([]string, max(size, 1))
+	for i := range labels {
+		labels[i] = gen.Word(rng, gen.Between(rng, 1, 6), "abcdefghijklmnopqrstuvwxyz")
+	}
+	steps := make([]string, 10*size)
+	for i := range steps {
+		steps[i] = labels[rng.IntN(len(labels))]
+		if rng.IntN(3) == 0 {
+			steps[i] += "-"
+		} else {
+			steps[i] += "=" + strconv.Itoa(gen.Between(rng, 1, 9))
+		}
+	}
+	return []byte(strings.Join(steps, ",") + "\n")
+}
@@ -1,14 +1,21 @@
-package main
+package day15
 
 import (
-	"bufio"
+	"context"
+	"errors"
 	"fmt"
-	"os"
+	"io"
+	"regexp"
 	"strconv"
 	"strings"
 	"unicode"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/parse"
 )
 
+var log = aoc.Logger("simulation")
+
 type lens struct {
 	label       string
 	focalLength int
@@ -74,6 +81,24 @@ func calculateHash(s string) int {
 	return hash
 }
 
+// step is how every step of the initialization sequence is written, checked
+// only in strict mode.
+var step = regexp.MustCompile(`^[a-z]+(-|=[1-9])$`)
+
+// checkSequence is the strict reading of the input: one line of steps
+// separated by commas.
+func checkSequence(lines []parse.Line) error {
+	if len(lines) > 1 {
+		return lines[1].Errorf("expected the sequence to be on one line")
+	}
+	for _, s := range lines[0].Split(",") {
+		if !step.MatchString(s.Text) {
+			return s.Errorf("expected a step such as \"rn=1\" or \"cm-\", found %q", s.Text)
+		}
+	}
+	return nil
+}
+
 func focusingPower(boxes [256][]lens) int {
 	total := 0
 	for j, box := range boxes {
@@ -84,30 +109,65 @@ func focusingPower(boxes [256][]lens) int {
 	return total
 }
 
-func main() {
-	var boxes [256][]lens
+// instruction is a step of the initialization sequence, which is also kept
+// as it was written since that is what part one hashes.
+type instruction struct {
+	text        string
+	label       string
+	operation   rune
+	focalLength int
+}
 
-	scanner := bufio.NewScanner(os.Stdin)
-	scanner.Scan()
-	input := scanner.Text()
+func parseSequence(r io.Reader) ([]instruction, error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("empty input")
+	}
+	if parse.Strict {
+		if err := checkSequence(lines); err != nil {
+			return nil, err
+		}
+	}
+	input := lines[0].Text
 
 	// Remove all whitespace
 	input = strings.ReplaceAll(input, " ", "")
 	input = strings.ReplaceAll(input, "\n", "")
 
 	// Split the input into steps
-	steps := strings.Split(input, ",")
+	var sequence []instruction
+	for _, step := range strings.Split(input, ",") {
+		label, op, focalLen, err := parseStep(step)
+		if err != nil {
+			return nil, fmt.Errorf("invalid step: %v", err)
+		}
+		sequence = append(sequence, instruction{step, label, op, focalLen})
+	}
+	return sequence, nil
+}
+
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	var boxes [256][]lens
+
+	sequence, err := parseSequence(r)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// Calculate the sum of the hash values
 	sum := 0
-	for _, step := range steps {
-		sum += calculateHash(step)
-		label, op, focalLen, err := parseStep(step)
+	for _, step := range sequence {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		sum += calculateHash(step.text)
+		label, op, focalLen := step.label, step.operation, step.focalLength
 		correctBox := calculateHash(label)
-		//fmt.Fprintf(os.Stderr, "step=%q label=%q op='%c' focal=%d box=%d\n", step, label, op, focalLen, correctBox)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "invalid step: %v\n", err)
-			return
+		if aoc.Tracing() {
+			aoc.Trace(log, "step", "step", step.text, "label", label, "op", string(op), "focal", focalLen, "box", correctBox)
 		}
 		switch op {
 		case '-':
@@ -115,20 +175,16 @@ func main() {
 		case '=':
 			addOrChangeLens(label, focalLen, &boxes[correctBox])
 		default:
-			fmt.Fprintf(os.Stderr, "invalid operation: %c\n", op)
-			return
+			return nil, nil, fmt.Errorf("invalid operation: %c", op)
 		}
-		/*
+		if aoc.Tracing() {
 			for i, box := range boxes {
-				if len(box) == 0 {
-					continue
+				if len(box) != 0 {
+					aoc.Trace(log, "box", "box", i, "lenses", box)
 				}
-				fmt.Printf("\tBox %d: %v\n", i, box)
 			}
-			fmt.Println()
-		*/
+		}
 	}
 
-	fmt.Println(sum)
-	fmt.Println(focusingPower(boxes))
+	return sum, focusingPower(boxes), nil
 }
@@ -0,0 +1,27 @@
+package day15
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+func writeSequence(sequence []instruction) string {
+	steps := make([]string, len(sequence))
+	for i, step := range sequence {
+		steps[i] = step.text
+	}
+	return strings.Join(steps, ",") + "\n"
+}
+
+func FuzzParseSequence(f *testing.F) {
+	parsetest.Seed(f,
+		"rn=1,cm-,qp=3,cm=2,qp-,pc=4,ot=9,ab=5,pc-,pc=6,ot=7\n",
+		"rn = 1, cm-\nignored\n",
+		"=",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, parseSequence, writeSequence)
+	})
+}
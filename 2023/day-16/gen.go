@@ -0,0 +1,14 @@
+package day16
+
+import (
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes a size by size contraption of mirrors and splitters in
+// mostly empty space.
+func Generate(rng *rand.Rand, size int) []byte {
+	size = gen.Clamp(size, 1, 110)
+	return gen.Grid(size, size, func(x, y int) byte { return gen.Pick(rng, `/\|-..........`) })
+}
@@ -1,10 +1,12 @@
-package main
+package day16
 
 import (
-	"bufio"
+	"context"
 	"fmt"
-	"log"
-	"os"
+	"io"
+
+	"github.com/havill/AdventOfCode/grid"
+	"github.com/havill/AdventOfCode/render"
 )
 
 type verticalDirection int
@@ -53,7 +55,7 @@ type tile struct {
 	energized  int
 }
 
-type gridMatrix [][]tile
+type gridMatrix = *grid.Grid[tile]
 
 func toArrow(x, y int) headingType {
 	if y > 0 && x == 0 {
@@ -85,41 +87,20 @@ func spawnBeam(beams beamMap, x int, y int, going headingType) {
 	beams[newBeam] = 1
 }
 
-func loadGridFromFile(file os.File) (gridMatrix, error) {
-	scanner := bufio.NewScanner(&file)
-	var grid gridMatrix
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		row := make([]tile, len(line))
-		for i, char := range line {
-			switch spaceType(char) {
-			case emptySpace, forwardMirror, backwardMirror, verticalSplitter, horizontalSplitter:
-				row[i] = tile{containing: spaceType(char)}
-			default:
-				return nil, fmt.Errorf("invalid character %c", char)
-			}
-		}
-		grid = append(grid, row)
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, err
+func charToTile(char rune) (tile, error) {
+	switch spaceType(char) {
+	case emptySpace, forwardMirror, backwardMirror, verticalSplitter, horizontalSplitter:
+		return tile{containing: spaceType(char)}, nil
 	}
+	return tile{}, fmt.Errorf("invalid character %c", char)
+}
 
-	return grid, nil
+func loadGridFromFile(file io.Reader) (gridMatrix, error) {
+	return grid.Read(file, charToTile)
 }
 
-func energizedTiles(grid gridMatrix) int {
-	count := 0
-	for _, row := range grid {
-		for _, space := range row {
-			if space.energized > 0 {
-				count++
-			}
-		}
-	}
-	return count
+func energizedTiles(contraption gridMatrix) int {
+	return contraption.Count(func(space tile) bool { return space.energized > 0 })
 }
 
 func findBeamsAtPosition(beams beamMap, x, y int) []beam {
@@ -132,77 +113,35 @@ func findBeamsAtPosition(beams beamMap, x, y int) []beam {
 	return matchingBeams
 }
 
-func setTerminalForegroundColor(r, g, b int) {
-	fmt.Printf("\033[38;2;%d;%d;%dm", r, g, b)
-}
-func setTerminalBackgroundColor(r, g, b int) {
-	fmt.Printf("\033[48;2;%d;%d;%dm", r, g, b)
-}
-func resetTerminalColors() {
-	fmt.Printf("\033[0m")
-}
-func resetCursorToTopLeft(clearScreen bool) {
-	fmt.Printf("%s", "\033[H") // move cursor to Ln 1, Col 1
-	if clearScreen {
-		fmt.Printf("%s", "\033[2J") // clear screen
-	}
-}
-
-func debugDiagram(grid gridMatrix, beams beamMap, interactive bool) {
-	if !interactive {
-		resetCursorToTopLeft(interactive)
-	}
-	for y, row := range grid {
-		for x, space := range row {
-			beamAtPosition := findBeamsAtPosition(beams, x, y)
-			if beamAtPosition != nil {
-				if len(beamAtPosition) > 1 {
-					setTerminalBackgroundColor(255, 0, 0)   // red
-					setTerminalForegroundColor(255, 255, 0) // yellow
-					fmt.Print("*")
-					resetTerminalColors()
-				} else {
-					c := rune(toArrow(int(beamAtPosition[0].xAdvance), int(beamAtPosition[0].yAdvance)))
-					setTerminalBackgroundColor(255, 255, 0) // yellow
-					setTerminalForegroundColor(0, 0, 255)   // blue
-					fmt.Printf("%c", c)
-					resetTerminalColors()
-				}
-			} else {
-				if space.energized > 0 {
-					setTerminalBackgroundColor(255, 255, 0) // yellow
-					setTerminalForegroundColor(0, 0, 0)     // black
-				}
-				fmt.Print(string(space.containing))
-				resetTerminalColors()
+func debugDiagram(contraption gridMatrix, beams beamMap) {
+	frame := render.FromGrid(contraption, func(p grid.Point, space tile) render.Cell {
+		beamAtPosition := findBeamsAtPosition(beams, p.X, p.Y)
+		if beamAtPosition != nil {
+			if len(beamAtPosition) > 1 {
+				return render.Cell{Rune: '*', Fg: render.Yellow, Bg: render.Red}
 			}
+			c := rune(toArrow(int(beamAtPosition[0].xAdvance), int(beamAtPosition[0].yAdvance)))
+			return render.Cell{Rune: c, Fg: render.Blue, Bg: render.Yellow}
 		}
-		fmt.Println() // end of row
-	}
-	if interactive {
-		fmt.Println("beams    =", beams)
-		fmt.Printf("energized= %d\n", energizedTiles(grid))
-		// pause until they press Enter
-		var reader *bufio.Reader = bufio.NewReader(os.Stdin)
-		reader.ReadString('\n')
-	} else {
-		fmt.Println()
-	}
+		if space.energized > 0 {
+			return render.Cell{Rune: rune(space.containing), Fg: render.Black, Bg: render.Yellow}
+		}
+		return render.Cell{Rune: rune(space.containing)}
+	})
+	render.Screen.Draw(frame)
 }
 
-func heatTiles(grid gridMatrix, beams beamMap) {
+func heatTiles(contraption gridMatrix, beams beamMap) {
 	for k, heat := range beams {
-		grid[k.y][k.x].energized += heat
+		where := grid.Point{X: k.x, Y: k.y}
+		space := contraption.At(where)
+		space.energized += heat
+		contraption.Set(where, space)
 	}
 }
 
-func gridDimensions(grid gridMatrix) (int, int) {
-	height := len(grid)
-	width := 0
-	if height > 0 {
-		width = len(grid[0])
-	}
-	return width, height
+func gridDimensions(contraption gridMatrix) (int, int) {
+	return contraption.Width(), contraption.Height()
 }
 
 func gcBeams(beams, history beamMap, width, height int) beamMap {
@@ -229,11 +168,11 @@ func advanceBeams(beams, history beamMap) beamMap {
 	return newBeams
 }
 
-func deflectOrSplitBeams(beams beamMap, grid gridMatrix) beamMap {
+func deflectOrSplitBeams(beams beamMap, contraption gridMatrix) beamMap {
 	newBeams := make(beamMap)
 
 	for k, v := range beams {
-		tile := grid[k.y][k.x]
+		tile := contraption.At(grid.Point{X: k.x, Y: k.y})
 		switch tile.containing {
 		case forwardMirror:
 			if k.xAdvance > 0 {
@@ -281,37 +220,69 @@ func deflectOrSplitBeams(beams beamMap, grid gridMatrix) beamMap {
 	return newBeams
 }
 
-func main() {
-	if len(os.Args) < 2 {
-		log.Fatalf("Please provide a file name")
-	}
-
-	resetCursorToTopLeft(true) // for interactive debug
-
-	file := os.Args[1]
-	f, err := os.Open(file)
-	if err != nil {
-		log.Fatalf("Failed to open file: %v", err)
-	}
-	defer f.Close()
-
-	grid, _ := loadGridFromFile(*f)
-	x, y := gridDimensions(grid)
+// energize sends the beam in from the top left and follows it until every
+// beam has gone off the edge or is going round where another has been,
+// heating each tile it passes through.
+func energize(ctx context.Context, contraption gridMatrix) error {
+	x, y := gridDimensions(contraption)
 
 	beams := make(beamMap)
 	history := make(beamMap)
 
 	spawnBeam(beams, 0, 0, east)
 	for len(beams) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		beams = gcBeams(beams, history, x, y)
 
-		debugDiagram(grid, beams, false) // debug
+		if render.Screen != nil {
+			debugDiagram(contraption, beams)
+		}
 
-		heatTiles(grid, beams)
-		beams = deflectOrSplitBeams(beams, grid)
+		heatTiles(contraption, beams)
+		beams = deflectOrSplitBeams(beams, contraption)
 		beams = advanceBeams(beams, history)
 
 	}
-	count := energizedTiles(grid)
-	fmt.Printf("Part 1: Number of energized tiles = %d\n", count)
+	return nil
+}
+
+// Pictures are the ways a contraption can be drawn once the beam has been
+// through it.
+var Pictures = []render.Picture{{Name: "energized", Draw: drawEnergized}}
+
+// drawEnergized draws the contraption as a heatmap of how many beams went
+// through each tile.
+func drawEnergized(ctx context.Context, r io.Reader) (*render.Frame, error) {
+	contraption, err := loadGridFromFile(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := energize(ctx, contraption); err != nil {
+		return nil, err
+	}
+	most := 0
+	for _, space := range contraption.All() {
+		most = max(most, space.energized)
+	}
+	return render.FromGrid(contraption, func(_ grid.Point, space tile) render.Cell {
+		cell := render.Cell{Rune: rune(space.containing), Fg: render.White, Bg: render.Heat.Shade(space.energized, most)}
+		if 2*space.energized > most {
+			cell.Fg = render.Black // on yellow or white
+		}
+		return cell
+	}), nil
+}
+
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	contraption, err := loadGridFromFile(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := energize(ctx, contraption); err != nil {
+		return nil, nil, err
+	}
+	count := energizedTiles(contraption)
+	return count, nil, nil
 }
@@ -0,0 +1,24 @@
+package day16
+
+import (
+	"testing"
+
+	"github.com/havill/AdventOfCode/grid"
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+func writeContraption(contraption gridMatrix) string {
+	return grid.Map(contraption, func(_ grid.Point, space tile) rune {
+		return rune(space.containing)
+	}).String()
+}
+
+func FuzzLoadGridFromFile(f *testing.F) {
+	parsetest.Seed(f,
+		".|...\\....\n|.-.\\.....\n.....|-...\n........|.\n",
+		"./\n\\x\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, loadGridFromFile, writeContraption)
+	})
+}
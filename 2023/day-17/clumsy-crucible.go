@@ -1,36 +1,60 @@
-package main
+package day17
 
 import (
-	"container/heap"
-	"fmt"
+	"bytes"
+	"context"
 	"image"
-	"os"
+	"io"
+	"iter"
 	"strings"
-)
-
-type hqi[T any] struct {
-	v T
-	p int
-}
-
-type HeapQ[T any] []hqi[T]
 
-func (q HeapQ[_]) Len() int           { return len(q) }
-func (q HeapQ[_]) Less(i, j int) bool { return q[i].p < q[j].p }
-func (q HeapQ[_]) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
-func (q *HeapQ[T]) Push(x any)        { *q = append(*q, x.(hqi[T])) }
-func (q *HeapQ[_]) Pop() (x any)      { x, *q = (*q)[len(*q)-1], (*q)[:len(*q)-1]; return x }
-func (q *HeapQ[T]) GPush(v T, p int)  { heap.Push(q, hqi[T]{v, p}) }
-func (q *HeapQ[T]) GPop() (T, int)    { x := heap.Pop(q).(hqi[T]); return x.v, x.p }
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/parse"
+	"github.com/havill/AdventOfCode/search"
+)
 
 type State struct {
 	Pos image.Point
 	Dir image.Point
 }
 
-func main() {
-	input, _ := os.ReadFile("input.txt")
-	split := strings.Fields(string(input))
+// checkMap is the strict reading of the map: a rectangle of the heat lost in
+// each block, from 1 to 9.
+func checkMap(input []byte) error {
+	lines, err := parse.Lines(bytes.NewReader(input))
+	if err != nil {
+		return err
+	}
+	for _, l := range lines {
+		if err := l.Only("123456789"); err != nil {
+			return err
+		}
+		if len(l.Text) != len(lines[0].Text) {
+			return l.ErrorAt(len(l.Text), "%d blocks wide, expected %d", len(l.Text), len(lines[0].Text))
+		}
+	}
+	return nil
+}
+
+// readMap reads the rows of the map, which are only checked in strict mode.
+func readMap(r io.Reader) ([]string, error) {
+	input, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if parse.Strict {
+		if err := checkMap(input); err != nil {
+			return nil, err
+		}
+	}
+	return strings.Fields(string(input)), nil
+}
+
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	split, err := readMap(r)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	grid, end := map[image.Point]int{}, image.Point{0, 0}
 	for y, s := range split {
@@ -40,40 +64,49 @@ func main() {
 		}
 	}
 
-	recurseMinimax := func(min, max int) int {
-		queue, visited := HeapQ[State]{}, map[State]struct{}{}
-		queue.GPush(State{image.Point{0, 0}, image.Point{1, 0}}, 0)
-		queue.GPush(State{image.Point{0, 0}, image.Point{0, 1}}, 0)
+	aoc.Parsed(ctx)
 
-		for len(queue) > 0 {
-			node, heat := queue.GPop()
-
-			if node.Pos == end {
-				return heat
-			}
-			if _, ok := visited[node]; ok {
-				continue
-			}
-			visited[node] = struct{}{}
-
-			for _, d := range []image.Point{
-				{node.Dir.Y, node.Dir.X}, {-node.Dir.Y, -node.Dir.X},
-			} {
-				for i := min; i <= max; i++ {
-					n := node.Pos.Add(d.Mul(i))
-					if _, ok := grid[n]; ok {
-						h := 0
-						for j := 1; j <= i; j++ {
-							h += grid[node.Pos.Add(d.Mul(j))]
+	// a cancelled search runs out of moves, and so ends
+	recurseMinimax := func(min, max int) int {
+		starts := []State{{image.Point{0, 0}, image.Point{1, 0}}, {image.Point{0, 0}, image.Point{0, 1}}}
+		turns := func(node State) iter.Seq2[State, int] {
+			return func(yield func(State, int) bool) {
+				if ctx.Err() != nil {
+					return
+				}
+				for _, d := range []image.Point{
+					{node.Dir.Y, node.Dir.X}, {-node.Dir.Y, -node.Dir.X},
+				} {
+					for i := min; i <= max; i++ {
+						n := node.Pos.Add(d.Mul(i))
+						if _, ok := grid[n]; ok {
+							h := 0
+							for j := 1; j <= i; j++ {
+								h += grid[node.Pos.Add(d.Mul(j))]
+							}
+							if !yield(State{n, d}, h) {
+								return
+							}
 						}
-						queue.GPush(State{n, d}, heat+h)
 					}
 				}
 			}
 		}
-		return -1
+		result := search.Dijkstra(starts, turns, func(node State) bool { return node.Pos == end })
+		if !result.Found {
+			return -1
+		}
+		return result.Cost
 	}
 
-	fmt.Println(recurseMinimax(1, 3))  // min of 1 block, max of 3 blocks forward
-	fmt.Println(recurseMinimax(4, 10)) // part 2: ultra crucibles: 4 min, 10 max
+	part1 := recurseMinimax(1, 3) // min of 1 block, max of 3 blocks forward
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	aoc.Part1Done(ctx)
+	part2 := recurseMinimax(4, 10) // part 2: ultra crucibles: 4 min, 10 max
+	if err := ctx.Err(); err != nil {
+		return part1, nil, err
+	}
+	return part1, part2, nil
 }
@@ -0,0 +1,22 @@
+package day17
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+func writeMap(rows []string) string {
+	return strings.Join(rows, "\n") + "\n"
+}
+
+func FuzzReadMap(f *testing.F) {
+	parsetest.Seed(f,
+		"2413432311323\n3215453535623\n3255245654254\n",
+		"12\n3 4\n\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, readMap, writeMap)
+	})
+}
@@ -0,0 +1,13 @@
+package day17
+
+import (
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes a size by size map of how much heat each city block loses.
+func Generate(rng *rand.Rand, size int) []byte {
+	size = gen.Clamp(size, 1, 141)
+	return gen.Grid(size, size, func(x, y int) byte { return gen.Pick(rng, "123456789") })
+}
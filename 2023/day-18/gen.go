@@ -0,0 +1,88 @@
+package day18
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes a dig plan of about size steps around a lagoon that never
+// crosses itself. The lagoon is made of rows, each overlapping the one
+// above it, on a grid of uneven spacing. It is dug clockwise from its top
+// left corner, so the inside starts one step down and right from there as
+// the solver expects. The colours, read as part 2 reads them, are the same
+// plan made bigger.
+func Generate(rng *rand.Rand, size int) []byte {
+	cells := gen.Clamp(size/4, 1, 50)
+	at := func() []int {
+		lines := make([]int, cells+1)
+		for i := 1; i < len(lines); i++ {
+			lines[i] = lines[i-1] + gen.Between(rng, 2, 10)
+		}
+		return lines
+	}
+	columns, rows := at(), at()
+
+	// row y runs from column from[y] to column to[y]
+	from, to := make([]int, cells), make([]int, cells)
+	for y := range cells {
+		for {
+			from[y] = rng.IntN(cells)
+			to[y] = gen.Between(rng, from[y]+1, cells)
+			if y == 0 || max(from[y], from[y-1]) < min(to[y], to[y-1]) {
+				break
+			}
+		}
+	}
+
+	type corner struct{ x, y int }
+	corners := []corner{{from[0], 0}}
+	for y := range cells {
+		corners = append(corners, corner{to[y], y}, corner{to[y], y + 1})
+	}
+	for y := cells - 1; y >= 0; y-- {
+		corners = append(corners, corner{from[y], y + 1}, corner{from[y], y})
+	}
+
+	type dig struct {
+		direction string
+		meters    int
+	}
+	var plan []dig
+	for i := 1; i < len(corners); i++ {
+		dx := columns[corners[i].x] - columns[corners[i-1].x]
+		dy := rows[corners[i].y] - rows[corners[i-1].y]
+		var next dig
+		switch {
+		case dx > 0:
+			next = dig{"R", dx}
+		case dx < 0:
+			next = dig{"L", -dx}
+		case dy > 0:
+			next = dig{"D", dy}
+		case dy < 0:
+			next = dig{"U", -dy}
+		default:
+			continue
+		}
+		if n := len(plan); n > 0 && plan[n-1].direction == next.direction {
+			plan[n-1].meters += next.meters
+		} else {
+			plan = append(plan, next)
+		}
+	}
+
+	longest := 0
+	for _, d := range plan {
+		longest = max(longest, d.meters)
+	}
+	scale := gen.Between(rng, 1, 0xfffff/longest)
+	var b bytes.Buffer
+	for _, d := range plan {
+		fmt.Fprintf(&b, "%s %d (#%05x%d)\n", d.direction, d.meters, d.meters*scale, strings.Index("RDLU", d.direction))
+	}
+	return b.Bytes()
+}
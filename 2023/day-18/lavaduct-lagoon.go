@@ -1,14 +1,24 @@
-package main
+package day18
 
 import (
-	"bufio"
-	"flag"
+	"context"
 	"fmt"
-	"os"
+	"io"
+	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/parse"
+	"github.com/havill/AdventOfCode/render"
 )
 
+var parseLog = aoc.Logger("parser")
+
+// --- Part Two --- is off by default because flood filling the decoded
+// trench needs far more memory than the first part's
+var part2 = false
+
 type rgba struct {
 	red, green, blue, alpha int64
 }
@@ -72,6 +82,10 @@ func parseDirection(s string) (horizontalChange, verticalChange int) {
 	return 0, 0
 }
 
+// planLine is how every step of the plan is written, checked only in strict
+// mode.
+var planLine = regexp.MustCompile(`^[UDLR] \d+ \(#[0-9a-f]{6}\)$`)
+
 func parseDigPlan(line string) (direction string, meters int, color string, err error) {
 	fields := strings.Fields(line)
 	if len(fields) != 3 {
@@ -112,44 +126,35 @@ func dig(where coordinate, color rgba, lagoon graph) graph {
 	return lagoon
 }
 
-func setTerminalForegroundColor(r, g, b int) {
-	fmt.Printf("\033[38;2;%d;%d;%dm", r, g, b)
-}
-func setTerminalBackgroundColor(r, g, b int) {
-	fmt.Printf("\033[48;2;%d;%d;%dm", r, g, b)
-}
-func resetTerminalColors() {
-	fmt.Printf("\033[0m")
-}
-func resetCursorToTopLeft(clearScreen bool) {
-	fmt.Printf("%s", "\033[H") // move cursor to Ln 1, Col 1
-	if clearScreen {
-		fmt.Printf("%s", "\033[2J") // clear screen
-	}
-}
-func debugPrintLagoon(lagoon graph) {
+// lagoonFrame draws the lagoon with each hole in the colour it was dug
+// with.
+func lagoonFrame(lagoon graph) *render.Frame {
+	frame := render.NewFrame(lagoon.maxX-lagoon.minX+1, lagoon.maxY-lagoon.minY+1)
 	for y := lagoon.minY; y <= lagoon.maxY; y++ {
 		for x := lagoon.minX; x <= lagoon.maxX; x++ {
 			ground := lagoon.cube[coordinate{x, y}]
-			setTerminalBackgroundColor(0, 0, 0)
+			cell := render.Cell{Rune: '.', Fg: render.White, Bg: render.Black}
 			if ground.Hole {
-				setTerminalForegroundColor(int(ground.color.red), int(ground.color.green), int(ground.color.blue))
-				fmt.Printf("#")
-			} else {
-				setTerminalForegroundColor(255, 255, 255)
-				fmt.Printf(".")
+				cell.Rune = '#'
+				cell.Fg = render.RGB(uint8(ground.color.red), uint8(ground.color.green), uint8(ground.color.blue))
 			}
-			resetTerminalColors()
+			frame.Set(x-lagoon.minX, y-lagoon.minY, cell)
 		}
-		fmt.Println()
 	}
-	fmt.Println()
+	return frame
+}
+
+func debugPrintLagoon(lagoon graph) {
+	render.Screen.Draw(lagoonFrame(lagoon))
 }
 
-func fillPolygonStack(area graph, color rgba, x int, y int) {
+func fillPolygonStack(ctx context.Context, area graph, color rgba, x int, y int) error {
 	stack := []coordinate{{x, y}}
 
 	for len(stack) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		// Pop a coordinate from the stack.
 		where := stack[len(stack)-1]
 		stack = stack[:len(stack)-1]
@@ -169,6 +174,7 @@ func fillPolygonStack(area graph, color rgba, x int, y int) {
 		stack = append(stack, coordinate{where.x, where.y - 1}) // Up
 		stack = append(stack, coordinate{where.x, where.y + 1}) // Down
 	}
+	return nil
 }
 
 func fillPolygon(area graph, color rgba, x int, y int) {
@@ -211,31 +217,59 @@ func decodeHexadecimal(hex string) (int, string, error) {
 	return int(result), right, nil
 }
 
-func main() {
-	var lagoon graph
-	x, y := 0, 0
-	lagoon.cube = make(map[coordinate]Ground)
+// step is one line of the dig plan.
+type step struct {
+	direction string
+	meters    int
+	color     string
+	rgb       rgba
+}
 
-	part2 := flag.Bool("part2", false, "--- Part Two ---")
-	debug := flag.Bool("debug", false, "show the before and after maps")
-	flag.Parse()
+func parsePlan(ctx context.Context, r io.Reader) ([]step, error) {
+	var plan []step
 
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		line := scanner.Text()
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range lines {
+		if parse.Strict && !planLine.MatchString(l.Text) {
+			return nil, l.Errorf("expected a step of the plan such as \"R 6 (#70c710)\"")
+		}
+		line := l.Text
 		direction, meters, color, err := parseDigPlan(line)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error parsing dig plan: %v\n", err)
+			if err := parse.Tolerate(ctx, parseLog, l.Errorf("%v", err)); err != nil {
+				return nil, err
+			}
 			continue
 		}
-		xDelta, yDelta := parseDirection(direction)
 		rgb, err := extractRGB(color)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error extracting RGB: %v\n", err)
+			if err := parse.Tolerate(ctx, parseLog, l.Errorf("%v", err)); err != nil {
+				return nil, err
+			}
 			continue
 		}
+		plan = append(plan, step{direction, meters, color, rgb})
+	}
+	return plan, nil
+}
+
+// digLagoon digs the trench of the plan and fills it in.
+func digLagoon(ctx context.Context, plan []step) (graph, error) {
+	var lagoon graph
+	x, y := 0, 0
+	lagoon.cube = make(map[coordinate]Ground)
+
+	for _, s := range plan {
+		if err := ctx.Err(); err != nil {
+			return lagoon, err
+		}
+		direction, meters, color, rgb := s.direction, s.meters, s.color, s.rgb
+		xDelta, yDelta := parseDirection(direction)
 
-		if *part2 {
+		if part2 {
 			meters, direction, _ = decodeHexadecimal(color)
 			rgb = rgba{255, 255, 255, 0}
 		}
@@ -248,19 +282,47 @@ func main() {
 			meters--
 		}
 	}
-	if err := scanner.Err(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error reading from stdin: %v\n", err)
-	}
 
-	if *debug {
-		resetCursorToTopLeft(true)
-		debugPrintLagoon(lagoon)
+	if render.Screen != nil {
+		debugPrintLagoon(lagoon) // before filling
 	}
 	//fillPolygon(lagoon, rgba{255, 0, 0, 0}, 1, 1)
-	fillPolygonStack(lagoon, rgba{255, 0, 0, 0}, 1, 1)
-	if *debug {
-		fmt.Println("After filling:")
-		debugPrintLagoon(lagoon)
+	if err := fillPolygonStack(ctx, lagoon, rgba{255, 0, 0, 0}, 1, 1); err != nil {
+		return lagoon, err
+	}
+	if render.Screen != nil {
+		debugPrintLagoon(lagoon) // after filling
+	}
+	return lagoon, nil
+}
+
+// Pictures are the ways a dig plan can be drawn once it has been dug.
+var Pictures = []render.Picture{{Name: "lagoon", Draw: drawLagoon}}
+
+// drawLagoon draws the lagoon once it is dug and filled in.
+func drawLagoon(ctx context.Context, r io.Reader) (*render.Frame, error) {
+	plan, err := parsePlan(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	lagoon, err := digLagoon(ctx, plan)
+	if err != nil {
+		return nil, err
+	}
+	return lagoonFrame(lagoon), nil
+}
+
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	plan, err := parsePlan(ctx, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	lagoon, err := digLagoon(ctx, plan)
+	if err != nil {
+		return nil, nil, err
+	}
+	if part2 {
+		return nil, countHoles(lagoon), nil
 	}
-	fmt.Printf("Lava Area: %dm\u00B3\n", countHoles(lagoon))
+	return countHoles(lagoon), nil, nil
 }
@@ -0,0 +1,27 @@
+package day18
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+func writePlan(plan []step) string {
+	var b strings.Builder
+	for _, s := range plan {
+		fmt.Fprintf(&b, "%s %d (%s)\n", s.direction, s.meters, s.color)
+	}
+	return b.String()
+}
+
+func FuzzParsePlan(f *testing.F) {
+	parsetest.Seed(f,
+		"R 6 (#70c710)\nD 5 (#0dc571)\nL 2 (#5713f0)\nD 2 (#d2c081)\n",
+		"U 2 (#caa173)\nX 2 (#d2c0)\nL 1 #1b58a2\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, parsetest.Background(parsePlan), writePlan)
+	})
+}
@@ -1,16 +1,10 @@
-package main
+package day19
 
 import (
-	"bufio"
-	"flag"
 	"fmt"
-	"os"
-	"strconv"
 	"strings"
 )
 
-var debug = flag.Bool("debug", false, "enable debug mode")
-
 type Categories rune
 
 const (
@@ -109,54 +103,3 @@ func parseTernaryOp(expr string) (left string, op Comparison, action string, err
 
 	return left, op, action, nil
 }
-
-func main() {
-	//var w Workflows = make(Workflows, 0)
-	var p Parts = make(Parts, 0)
-
-	flag.Parse()
-
-	if *debug {
-		fmt.Println("Debug mode enabled")
-	}
-
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		line := scanner.Text()
-		left, right := parseLine(line)
-		fmt.Println("Left:", left, "Right:", right)
-		if len(left) > 0 {
-		} else { // no identifier to left of brace; we have a part
-			list := parseList(right)
-			for _, item := range list {
-				fmt.Println("Item:", item)
-				id, _, val, err := parseTernaryOp(item)
-				if err != nil {
-					fmt.Println("Error parsing binary expression:", err)
-					continue
-				}
-				i, err := strconv.Atoi(val)
-				if err != nil {
-					fmt.Println("Error converting string to integer:", err)
-					continue
-				}
-				switch id {
-				case "x":
-					p = append(p, Ratings{Cool: i})
-				case "m":
-					p = append(p, Ratings{Musical: i})
-				case "a":
-					p = append(p, Ratings{Aerodynamic: i})
-				case "s":
-					p = append(p, Ratings{Shiny: i})
-				default:
-					fmt.Println("Unknown identifier:", id)
-				}
-			}
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		fmt.Fprintln(os.Stderr, "reading standard input:", err)
-	}
-}
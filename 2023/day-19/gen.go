@@ -0,0 +1,39 @@
+package day19
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes a system of about size workflows and parts. A workflow
+// only ever sends a part on to one listed after it, starting from in, so
+// every part is accepted or rejected in the end.
+func Generate(rng *rand.Rand, size int) []byte {
+	names := append([]string{"in"}, gen.Words(rng, max(size/2, 1)-1, 3, "abcdefghijklmnopqrstuvwxyz", "in")...)
+	target := func(after int) string {
+		if n := rng.IntN(len(names) - after + 1); n < len(names)-after-1 {
+			return names[after+1+n]
+		} else if n%2 == 0 {
+			return "A"
+		}
+		return "R"
+	}
+
+	var b bytes.Buffer
+	for i, name := range names {
+		fmt.Fprintf(&b, "%s{", name)
+		for range gen.Between(rng, 1, 3) {
+			fmt.Fprintf(&b, "%c%c%d:%s,", gen.Pick(rng, "xmas"), gen.Pick(rng, "<>"), gen.Between(rng, 1, 4000), target(i))
+		}
+		fmt.Fprintf(&b, "%s}\n", target(i))
+	}
+	b.WriteByte('\n')
+	for range max(size/2, 1) {
+		fmt.Fprintf(&b, "{x=%d,m=%d,a=%d,s=%d}\n",
+			gen.Between(rng, 1, 4000), gen.Between(rng, 1, 4000), gen.Between(rng, 1, 4000), gen.Between(rng, 1, 4000))
+	}
+	return b.Bytes()
+}
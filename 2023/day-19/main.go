@@ -1,28 +1,34 @@
-package main
+package day19
 
 import (
-	"bufio"
+	"context"
 	"errors"
 	"fmt"
-	"os"
+	"io"
+	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/graph"
+	"github.com/havill/AdventOfCode/parse"
 )
 
-func ReadInput(inputFile string) (content []string, err error) {
-	file, err := os.Open(inputFile)
+func ReadInput(r io.Reader) (content []string, err error) {
+	lines, err := parse.Lines(r)
 	if err != nil {
-		return content, err
+		return nil, err
 	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		content = append(content, scanner.Text())
+	if parse.Strict {
+		if err := checkSystem(lines); err != nil {
+			return nil, err
+		}
 	}
-	err = scanner.Err()
-
-	return content, err
+	content = make([]string, len(lines))
+	for i, line := range lines {
+		content[i] = line.Text
+	}
+	return content, nil
 }
 
 type part struct {
@@ -41,13 +47,16 @@ func parseInput(lines []string) (wf, []part, error) {
 		if len(line) > 2 {
 			if isWorkFlows {
 				ws := strings.Split(line[:len(line)-1], "{")
-				if len(ws) != 2 {
-					return wfs, parts, errors.New("invalid input")
+				if len(ws) != 2 || !strings.HasSuffix(line, "}") {
+					return wfs, parts, fmt.Errorf("invalid workflow %q", line)
 				}
 				key := ws[0]
 				ops := strings.Split(ws[1], ",")
 				wfs[key] = ops
 			} else {
+				if !strings.HasPrefix(line, "{") || !strings.HasSuffix(line, "}") {
+					return wfs, parts, fmt.Errorf("invalid part %q", line)
+				}
 				p := line[1 : len(line)-1]
 				ps := strings.Split(p, ",")
 				x := 0
@@ -84,12 +93,40 @@ func parseInput(lines []string) (wf, []part, error) {
 				})
 			}
 		} else {
-			isWorkFlows = !isWorkFlows
+			// the parts start after the first blank line, as
+			// parseWorkFlows has it
+			isWorkFlows = false
 		}
 	}
 	return wfs, parts, nil
 }
 
+// checkWorkflows makes sure every workflow sends parts somewhere that exists
+// and that none of them leads back to itself, which would send parts round
+// in circles forever.
+func checkWorkflows(wfs wf) error {
+	if _, ok := wfs["in"]; !ok {
+		return errors.New("no workflow named in")
+	}
+	flows := graph.New[string](true)
+	for name, ops := range wfs {
+		for _, op := range ops {
+			_, target, found := strings.Cut(op, ":")
+			if !found {
+				target = op
+			}
+			if _, ok := wfs[target]; !ok && target != "A" && target != "R" {
+				return fmt.Errorf("workflow %s sends parts to unknown workflow %s", name, target)
+			}
+			flows.AddEdge(name, target)
+		}
+	}
+	if flows.HasCycle() {
+		return errors.New("workflows loop back on themselves")
+	}
+	return nil
+}
+
 func evaluate(p part, ops []string) string {
 	var (
 		value     int
@@ -160,12 +197,17 @@ func evaluate(p part, ops []string) string {
 	panic("solution not found")
 }
 
-func getSumRatingNumbers(s []string) (int, error) {
+func getSumRatingNumbers(ctx context.Context, s []string) (int, error) {
 	var total int = 0
 	if wfs, parts, err := parseInput(s); err != nil {
 		return 0, err
+	} else if err := checkWorkflows(wfs); err != nil {
+		return 0, err
 	} else {
 		for _, p := range parts {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
 			wfKey := "in"
 			exit := false
 			for {
@@ -194,8 +236,8 @@ func parseWorkFlows(lines []string) (map[string]string, error) {
 	for _, line := range lines {
 		if len(line) > 2 {
 			ws := strings.Split(line[:len(line)-1], "{")
-			if len(ws) != 2 {
-				return wfs, errors.New("invalid input")
+			if len(ws) != 2 || !strings.HasSuffix(line, "}") {
+				return wfs, fmt.Errorf("invalid workflow %q", line)
 			}
 			ops := strings.Split(ws[1], ",")
 			if len(ops) == 2 {
@@ -223,6 +265,9 @@ func parseWorkFlows(lines []string) (map[string]string, error) {
 }
 
 func getAccepted(wfKey string, xMin, xMax, mMin, mMax, aMin, aMax, sMin, sMax int, wfs map[string]string) int {
+	if xMin > xMax || mMin > mMax || aMin > aMax || sMin > sMax {
+		return 0 // no part could get past the conditions so far
+	}
 	if wfKey == "A" {
 		return (xMax - xMin + 1) * (mMax - mMin + 1) * (aMax - aMin + 1) * (sMax - sMin + 1)
 	} else if wfKey == "R" {
@@ -240,35 +285,35 @@ func getAccepted(wfKey string, xMin, xMax, mMin, mMax, aMin, aMax, sMin, sMax in
 		switch param {
 		case 'x':
 			if symbol == '>' {
-				acc += getAccepted(trueOption, value+1, xMax, mMin, mMax, aMin, aMax, sMin, sMax, wfs)
-				acc += getAccepted(falseOption, xMin, value, mMin, mMax, aMin, aMax, sMin, sMax, wfs)
+				acc += getAccepted(trueOption, max(value+1, xMin), xMax, mMin, mMax, aMin, aMax, sMin, sMax, wfs)
+				acc += getAccepted(falseOption, xMin, min(value, xMax), mMin, mMax, aMin, aMax, sMin, sMax, wfs)
 			} else {
-				acc += getAccepted(trueOption, xMin, value-1, mMin, mMax, aMin, aMax, sMin, sMax, wfs)
-				acc += getAccepted(falseOption, value, xMax, mMin, mMax, aMin, aMax, sMin, sMax, wfs)
+				acc += getAccepted(trueOption, xMin, min(value-1, xMax), mMin, mMax, aMin, aMax, sMin, sMax, wfs)
+				acc += getAccepted(falseOption, max(value, xMin), xMax, mMin, mMax, aMin, aMax, sMin, sMax, wfs)
 			}
 		case 'm':
 			if symbol == '>' {
-				acc += getAccepted(trueOption, xMin, xMax, value+1, mMax, aMin, aMax, sMin, sMax, wfs)
-				acc += getAccepted(falseOption, xMin, xMax, mMin, value, aMin, aMax, sMin, sMax, wfs)
+				acc += getAccepted(trueOption, xMin, xMax, max(value+1, mMin), mMax, aMin, aMax, sMin, sMax, wfs)
+				acc += getAccepted(falseOption, xMin, xMax, mMin, min(value, mMax), aMin, aMax, sMin, sMax, wfs)
 			} else {
-				acc += getAccepted(trueOption, xMin, xMax, mMin, value-1, aMin, aMax, sMin, sMax, wfs)
-				acc += getAccepted(falseOption, xMin, xMax, value, mMax, aMin, aMax, sMin, sMax, wfs)
+				acc += getAccepted(trueOption, xMin, xMax, mMin, min(value-1, mMax), aMin, aMax, sMin, sMax, wfs)
+				acc += getAccepted(falseOption, xMin, xMax, max(value, mMin), mMax, aMin, aMax, sMin, sMax, wfs)
 			}
 		case 'a':
 			if symbol == '>' {
-				acc += getAccepted(trueOption, xMin, xMax, mMin, mMax, value+1, aMax, sMin, sMax, wfs)
-				acc += getAccepted(falseOption, xMin, xMax, mMin, mMax, aMin, value, sMin, sMax, wfs)
+				acc += getAccepted(trueOption, xMin, xMax, mMin, mMax, max(value+1, aMin), aMax, sMin, sMax, wfs)
+				acc += getAccepted(falseOption, xMin, xMax, mMin, mMax, aMin, min(value, aMax), sMin, sMax, wfs)
 			} else {
-				acc += getAccepted(trueOption, xMin, xMax, mMin, mMax, aMin, value-1, sMin, sMax, wfs)
-				acc += getAccepted(falseOption, xMin, xMax, mMin, mMax, value, aMax, sMin, sMax, wfs)
+				acc += getAccepted(trueOption, xMin, xMax, mMin, mMax, aMin, min(value-1, aMax), sMin, sMax, wfs)
+				acc += getAccepted(falseOption, xMin, xMax, mMin, mMax, max(value, aMin), aMax, sMin, sMax, wfs)
 			}
 		case 's':
 			if symbol == '>' {
-				acc += getAccepted(trueOption, xMin, xMax, mMin, mMax, aMin, aMax, value+1, sMax, wfs)
-				acc += getAccepted(falseOption, xMin, xMax, mMin, mMax, aMin, aMax, sMin, value, wfs)
+				acc += getAccepted(trueOption, xMin, xMax, mMin, mMax, aMin, aMax, max(value+1, sMin), sMax, wfs)
+				acc += getAccepted(falseOption, xMin, xMax, mMin, mMax, aMin, aMax, sMin, min(value, sMax), wfs)
 			} else {
-				acc += getAccepted(trueOption, xMin, xMax, mMin, mMax, aMin, aMax, sMin, value-1, wfs)
-				acc += getAccepted(falseOption, xMin, xMax, mMin, mMax, aMin, aMax, value, sMax, wfs)
+				acc += getAccepted(trueOption, xMin, xMax, mMin, mMax, aMin, aMax, sMin, min(value-1, sMax), wfs)
+				acc += getAccepted(falseOption, xMin, xMax, mMin, mMax, aMin, aMax, max(value, sMin), sMax, wfs)
 			}
 		}
 		return acc
@@ -283,11 +328,48 @@ func getAllCombinations(input []string) (int, error) {
 	}
 }
 
-/*
-func main() {
-	abs, _ := filepath.Abs("input.txt")
-	output, _ := ReadInput(abs)
-	fmt.Println(getSumRatingNumbers(output))
-	fmt.Println(getAllCombinations(output))
+var (
+	workflowLine = regexp.MustCompile(`^[a-z]+\{([xmas][<>]\d+:([a-z]+|A|R),)*([a-z]+|A|R)\}$`)
+	partLine     = regexp.MustCompile(`^\{x=\d+,m=\d+,a=\d+,s=\d+\}$`)
+)
+
+// checkSystem is the strict reading of the input: the workflows, a blank
+// line, and then the ratings of the parts.
+func checkSystem(lines []parse.Line) error {
+	parts := false
+	for _, line := range lines {
+		switch {
+		case line.Blank():
+			if parts {
+				return line.Errorf("expected a part, or the end of the input")
+			}
+			parts = true
+		case !parts && !workflowLine.MatchString(line.Text):
+			return line.Errorf("expected a workflow such as \"px{a<2006:qkq,m>2090:A,rfg}\"")
+		case parts && !partLine.MatchString(line.Text):
+			return line.Errorf("expected a part such as \"{x=787,m=2655,a=1222,s=2876}\"")
+		}
+	}
+	if !parts {
+		return errors.New("no blank line between the workflows and the parts")
+	}
+	return nil
+}
+
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	input, err := ReadInput(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	aoc.Parsed(ctx)
+	sum, err := getSumRatingNumbers(ctx, input)
+	if err != nil {
+		return nil, nil, err
+	}
+	aoc.Part1Done(ctx)
+	combinations, err := getAllCombinations(input)
+	if err != nil {
+		return sum, nil, err
+	}
+	return sum, combinations, nil
 }
-*/
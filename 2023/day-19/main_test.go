@@ -0,0 +1,55 @@
+package day19
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+type system struct {
+	workflows wf
+	parts     []part
+	flows     map[string]string // the workflows as part two reads them
+}
+
+func readSystem(r io.Reader) (system, error) {
+	var s system
+	input, err := ReadInput(r)
+	if err != nil {
+		return s, err
+	}
+	if s.workflows, s.parts, err = parseInput(input); err != nil {
+		return s, err
+	}
+	s.flows, err = parseWorkFlows(input)
+	return s, err
+}
+
+// writeSystem writes the workflows and parts, which part two reads the
+// workflows from too.
+func writeSystem(s system) string {
+	var b strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(s.workflows)) {
+		fmt.Fprintf(&b, "%s{%s}\n", name, strings.Join(s.workflows[name], ","))
+	}
+	b.WriteString("\n")
+	for _, p := range s.parts {
+		fmt.Fprintf(&b, "{x=%d,m=%d,a=%d,s=%d}\n", p.x, p.m, p.a, p.s)
+	}
+	return b.String()
+}
+
+func FuzzReadSystem(f *testing.F) {
+	parsetest.Seed(f,
+		"px{a<2006:qkq,m>2090:A,rfg}\npv{a>1716:R,A}\nin{s<1351:px,qqz}\n\n{x=787,m=2655,a=1222,s=2876}\n{x=1679,m=44,a=2067,s=496}\n",
+		"in{A}\n\n{x=1}\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, readSystem, writeSystem)
+	})
+}
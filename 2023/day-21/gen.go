@@ -0,0 +1,18 @@
+package day21
+
+import (
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes a size by size garden with the elf in the middle.
+func Generate(rng *rand.Rand, size int) []byte {
+	size = gen.Clamp(size, 1, 131)
+	return gen.Grid(size, size, func(x, y int) byte {
+		if x == size/2 && y == size/2 {
+			return 'S'
+		}
+		return gen.Pick(rng, "#.......")
+	})
+}
@@ -1,109 +1,97 @@
-package main
+package day21
 
 import (
-	"bufio"
+	"context"
 	"fmt"
-	"os"
+	"io"
+	"iter"
+	"strings"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/grid"
+	"github.com/havill/AdventOfCode/search"
 )
 
-func printMap(rocks, plots [][]bool, startX, startY int, occupied rune) {
-	for y, row := range rocks {
-		for x := range row {
-			if x == startX && y == startY {
-				fmt.Print("S")
-			} else if rocks[y][x] {
-				fmt.Print("#")
-			} else if plots[y][x] {
-				fmt.Print("O")
-			} else {
-				fmt.Print(".")
-			}
-		}
-		fmt.Println()
+var log = aoc.Logger("search")
+
+func logMap(msg string, rocks, plots *grid.Grid[bool], start grid.Point) {
+	if !aoc.Debugging() {
+		return
 	}
-	fmt.Println()
+	var b strings.Builder
+	rocks.Fprint(&b, func(p grid.Point, rock bool) string {
+		if p == start {
+			return "S"
+		} else if rock {
+			return "#"
+		} else if plots.At(p) {
+			return "O"
+		}
+		return "."
+	})
+	log.Debug(msg + "\n" + b.String())
 }
 
-func countTrue(grid [][]bool) int {
-	count := 0
-	for _, row := range grid {
-		for _, cell := range row {
-			if cell {
-				count++
+func countTrue(g *grid.Grid[bool]) int {
+	return g.Count(func(cell bool) bool { return cell })
+}
+
+// stepCounter marks the plots that can be reached in exactly steps steps.
+// Stepping back and forth wastes two steps, so those are the plots an even
+// number of steps short of it. If ctx is cancelled the walk stops where it
+// is and the error is returned.
+func stepCounter(ctx context.Context, rocks, reached *grid.Grid[bool], start grid.Point, steps int) error {
+	walk := func(p grid.Point) iter.Seq[grid.Point] {
+		return func(yield func(grid.Point) bool) {
+			if ctx.Err() != nil {
+				return
+			}
+			for next, rock := range rocks.Neighbors4(p) {
+				if !rock && !yield(next) {
+					return
+				}
 			}
 		}
 	}
-	return count
+	for p, d := range search.BFS([]grid.Point{start}, walk).Dist {
+		if d <= steps && d%2 == steps%2 {
+			reached.Set(p, true)
+		}
+	}
+	return ctx.Err()
 }
 
-func stepCounter(rocks, plots, reached [][]bool, x, y, remaining int) bool {
-	walked := false
-
-	if x < 0 || y < 0 || x >= len(rocks[0]) || y >= len(rocks) {
-		return false
+func charToRock(char rune) (bool, error) {
+	switch char {
+	case '#':
+		return true, nil
+	case '.', 'S':
+		return false, nil
 	}
-	if remaining > 0 {
-		plots[y][x] = true
-		remaining -= 1
-		if !rocks[y-1][x] && !plots[y-1][x] { // north
-			walked = stepCounter(rocks, plots, reached, x, y-1, remaining) || walked
-		}
-		if !rocks[y+1][x] && !plots[y+1][x] { // south
-			walked = stepCounter(rocks, plots, reached, x, y+1, remaining) || walked
-		}
-		if !rocks[y][x+1] && !plots[y][x+1] { // east
-			walked = stepCounter(rocks, plots, reached, x+1, y, remaining) || walked
-		}
-		if !rocks[y][x-1] && !plots[y][x-1] { // west
-			walked = stepCounter(rocks, plots, reached, x-1, y, remaining) || walked
-		}
-	} else {
-		reached[y][x] = true
-	}
-	return walked
+	return false, fmt.Errorf("invalid character: %c", char)
 }
 
-func main() {
-	scanner := bufio.NewScanner(os.Stdin)
-	var rocks, plots, reached [][]bool
-	var startX, startY int
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	garden, err := grid.Read(r, grid.Expect(".#S", grid.Runes))
+	if err != nil {
+		return nil, nil, err
+	}
+	start, _ := garden.Find(func(char rune) bool { return char == 'S' })
 
-	for j := 0; scanner.Scan(); {
-		line := scanner.Text()
-		row := make([]bool, len(line))
-		for i, char := range line {
-			if char == 'S' {
-				startX = i
-				startY = j
-			} else if char == '#' {
-				row[i] = true
-			} else if char == '.' {
-				row[i] = false
-			} else {
-				fmt.Fprintf(os.Stderr, "Invalid character: %c\n", char)
-				os.Exit(1)
-			}
+	rocks := grid.New[bool](garden.Width(), garden.Height())
+	for p, char := range garden.All() {
+		rock, err := charToRock(char)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%v at %v", err, p)
 		}
-		j += 1
-		rocks = append(rocks, row)
-	}
-	if err := scanner.Err(); err != nil {
-		fmt.Fprintln(os.Stderr, "reading standard input:", err)
-		os.Exit(1)
+		rocks.Set(p, rock)
 	}
+	reached := grid.New[bool](rocks.Width(), rocks.Height())
 
-	plots = make([][]bool, len(rocks))
-	for i := range rocks {
-		plots[i] = make([]bool, len(rocks[i]))
-	}
-	reached = make([][]bool, len(rocks))
-	for i := range rocks {
-		reached[i] = make([]bool, len(rocks[i]))
+	logMap("garden", rocks, reached, start)
+	if err := stepCounter(ctx, rocks, reached, start, 6); err != nil {
+		return nil, nil, err
 	}
-
-	printMap(rocks, plots, startX, startY, '#')
-	stepCounter(rocks, plots, reached, startX, startY, 6)
-	fmt.Println(countTrue(reached))
-	printMap(rocks, reached, -1, -1, 'O')
-
+	logMap("reached", rocks, reached, grid.Point{X: -1, Y: -1})
+	return countTrue(reached), nil, nil
 }
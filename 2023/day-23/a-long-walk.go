@@ -1,11 +1,17 @@
-package main
+package day23
 
 import (
-	"bufio"
+	"context"
 	"fmt"
-	"os"
+	"io"
+	"strings"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/grid"
 )
 
+var log = aoc.Logger("search")
+
 type Tile rune
 
 const (
@@ -17,129 +23,98 @@ const (
 	West   Tile = '<'
 )
 
-type Hiked [][]bool
+type Trails = *grid.Grid[Tile]
+type Hiked = *grid.Grid[bool]
 
-func PrintMap(hikingTrails [][]Tile, stepped Hiked) {
-	for y, row := range hikingTrails {
-		for x, tile := range row {
-			if stepped[y][x] {
-				fmt.Print("O")
-			} else {
-				fmt.Print(string(tile))
-			}
+// LogMap traces the trails with the tiles stepped on so far.
+func LogMap(hikingTrails Trails, stepped Hiked) {
+	var b strings.Builder
+	hikingTrails.Fprint(&b, func(p grid.Point, tile Tile) string {
+		if stepped.At(p) {
+			return "O"
 		}
-		fmt.Println()
-	}
-	fmt.Println()
+		return string(tile)
+	})
+	aoc.Trace(log, "trails\n"+b.String())
 }
 
-func ReadTileMatix() ([][]Tile, error) {
-	scanner := bufio.NewScanner(os.Stdin)
-	var matrix [][]Tile
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		row := make([]Tile, len(line))
-		for i, char := range line {
-			row[i] = Tile(char)
-		}
-		matrix = append(matrix, row)
-	}
-
-	if err := scanner.Err(); err != nil {
+func ReadTileMatix(r io.Reader) (Trails, error) {
+	matrix, err := grid.Read(r, grid.Expect(".#^>v<", func(char rune) (Tile, error) { return Tile(char), nil }))
+	if err != nil {
 		return nil, fmt.Errorf("reading standard input: %v", err)
 	}
-
 	return matrix, nil
 }
 
 func cloneHiked(original Hiked) Hiked {
-	clone := make(Hiked, len(original))
-	for i := range original {
-		clone[i] = make([]bool, len(original[i]))
-		copy(clone[i], original[i])
-	}
-	return clone
+	return original.Clone()
 }
 
-func CanGoUp(slippery bool, hikingTrails [][]Tile, stepped Hiked, x, y int) bool {
-	if y-1 < 0 {
+// canGo reports whether the hike can take a step from x, y in the direction
+// of heading, where slope is the slippery tile that allows that step.
+func canGo(slippery bool, hikingTrails Trails, stepped Hiked, x, y int, heading grid.Point, slope Tile) bool {
+	here := grid.Point{X: x, Y: y}
+	next := here.Add(heading)
+	if !hikingTrails.In(next) {
 		return false
 	}
-	if slippery && hikingTrails[y][x] != Path && hikingTrails[y][x] != North {
+	if slippery && hikingTrails.At(here) != Path && hikingTrails.At(here) != slope {
 		return false
 	}
-	return hikingTrails[y-1][x] != Forest && !stepped[y-1][x]
+	return hikingTrails.At(next) != Forest && !stepped.At(next)
 }
 
-func CanGoRight(slippery bool, hikingTrails [][]Tile, stepped Hiked, x, y int) bool {
-	width := len(hikingTrails[0])
-	if x+1 >= width {
-		return false
-	}
-	if slippery && hikingTrails[y][x] != Path && hikingTrails[y][x] != East {
-		return false
-	}
-	return hikingTrails[y][x+1] != Forest && !stepped[y][x+1]
+func CanGoUp(slippery bool, hikingTrails Trails, stepped Hiked, x, y int) bool {
+	return canGo(slippery, hikingTrails, stepped, x, y, grid.North, North)
 }
 
-func CanGoDown(slippery bool, hikingTrails [][]Tile, stepped Hiked, x, y int) bool {
-	height := len(hikingTrails)
-	if y+1 >= height {
-		return false
-	}
-	if slippery && hikingTrails[y][x] != Path && hikingTrails[y][x] != South {
-		return false
-	}
-	return hikingTrails[y+1][x] != Forest && !stepped[y+1][x]
+func CanGoRight(slippery bool, hikingTrails Trails, stepped Hiked, x, y int) bool {
+	return canGo(slippery, hikingTrails, stepped, x, y, grid.East, East)
 }
 
-func CanGoLeft(slippery bool, hikingTrails [][]Tile, stepped Hiked, x, y int) bool {
-	if x-1 < 0 {
-		return false
-	}
-	if slippery && hikingTrails[y][x] != Path && hikingTrails[y][x] != West {
-		return false
-	}
-	return hikingTrails[y][x-1] != Forest && !stepped[y][x-1]
+func CanGoDown(slippery bool, hikingTrails Trails, stepped Hiked, x, y int) bool {
+	return canGo(slippery, hikingTrails, stepped, x, y, grid.South, South)
 }
 
-func AtGoal(hikingTrails [][]Tile, x, y int) bool {
-	height := len(hikingTrails)
-	return y+1 >= height
+func CanGoLeft(slippery bool, hikingTrails Trails, stepped Hiked, x, y int) bool {
+	return canGo(slippery, hikingTrails, stepped, x, y, grid.West, West)
 }
 
-func WalkToBottom(solutions *[]int, slippery bool, hikingTrails [][]Tile, stepped Hiked, steps, x, y int) int {
-	width := len(hikingTrails[0])
-	height := len(hikingTrails)
+func AtGoal(hikingTrails Trails, x, y int) bool {
+	height := hikingTrails.Height()
+	return y+1 >= height
+}
 
+// WalkToBottom tries every hike from x, y, adding the length of each that
+// reaches the bottom to solutions and counting every step in progress. It gives up
+// once ctx is done, which the caller has to check.
+func WalkToBottom(ctx context.Context, progress *aoc.Progress, solutions *[]int, slippery bool, hikingTrails Trails, stepped Hiked, steps, x, y int) int {
 	north, east, south, west := 0, 0, 0, 0
 
-	if x < 0 || x >= width || y < 0 || y >= height {
+	if !hikingTrails.In(grid.Point{X: x, Y: y}) || ctx.Err() != nil {
 		return 0
 	}
-	stepped[y][x] = true
+	stepped.Set(grid.Point{X: x, Y: y}, true)
 	steps++
-	//PrintMap(hikingTrails, stepped)
+	progress.Add(1)
+	if aoc.Tracing() {
+		LogMap(hikingTrails, stepped)
+	}
 	if CanGoUp(slippery, hikingTrails, stepped, x, y) {
-		//fmt.Println("Going up from ", x, y)
 		newMap := cloneHiked(stepped)
-		north = WalkToBottom(solutions, slippery, hikingTrails, newMap, steps, x, y-1)
+		north = WalkToBottom(ctx, progress, solutions, slippery, hikingTrails, newMap, steps, x, y-1)
 	}
 	if CanGoRight(slippery, hikingTrails, stepped, x, y) {
-		//fmt.Println("Going right from ", x, y)
 		newMap := cloneHiked(stepped)
-		east = WalkToBottom(solutions, slippery, hikingTrails, newMap, steps, x+1, y)
+		east = WalkToBottom(ctx, progress, solutions, slippery, hikingTrails, newMap, steps, x+1, y)
 	}
 	if CanGoDown(slippery, hikingTrails, stepped, x, y) {
-		//fmt.Println("Going down from ", x, y)
 		newMap := cloneHiked(stepped)
-		south = WalkToBottom(solutions, slippery, hikingTrails, newMap, steps, x, y+1)
+		south = WalkToBottom(ctx, progress, solutions, slippery, hikingTrails, newMap, steps, x, y+1)
 	}
 	if CanGoLeft(slippery, hikingTrails, stepped, x, y) {
-		//fmt.Println("Going left from ", x, y)
 		newMap := cloneHiked(stepped)
-		west = WalkToBottom(solutions, slippery, hikingTrails, newMap, steps, x-1, y)
+		west = WalkToBottom(ctx, progress, solutions, slippery, hikingTrails, newMap, steps, x-1, y)
 	}
 	if north >= east && north >= south && north >= west {
 		steps += north
@@ -151,15 +126,18 @@ func WalkToBottom(solutions *[]int, slippery bool, hikingTrails [][]Tile, steppe
 		steps += west
 	}
 	if AtGoal(hikingTrails, x, y) {
-		fmt.Println(steps)
+		log.Debug("reached the goal", "steps", steps)
 		*solutions = append(*solutions, steps)
 		return steps
 	}
 	return 0
 }
 
-func FindStart(hikingTrails [][]Tile) (int, int) {
-	for x, tile := range hikingTrails[0] {
+func FindStart(hikingTrails Trails) (int, int) {
+	if hikingTrails.Height() == 0 {
+		return -1, -1
+	}
+	for x, tile := range hikingTrails.Row(0) {
 		if tile != Forest {
 			return x, 0
 		}
@@ -181,29 +159,39 @@ func maxInt(arr []int) (max int, err error) {
 	return max, nil
 }
 
-func main() {
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
 	solutions1, solutions2 := []int{}, []int{}
-	longestHike := 0
 
-	hikingTrails, err := ReadTileMatix()
+	hikingTrails, err := ReadTileMatix(r)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error reading tile matrix: %v\n", err)
-		os.Exit(1)
+		return nil, nil, fmt.Errorf("error reading tile matrix: %v", err)
 	}
 
-	stepped := make(Hiked, len(hikingTrails))
-	for i := range hikingTrails {
-		stepped[i] = make([]bool, len(hikingTrails[i]))
+	stepped := grid.New[bool](hikingTrails.Width(), hikingTrails.Height())
+	if aoc.Tracing() {
+		LogMap(hikingTrails, stepped)
 	}
-	//PrintMap(hikingTrails, stepped)
 
 	x, y := FindStart(hikingTrails)
 
-	WalkToBottom(&solutions1, true, hikingTrails, stepped, -1, x, y)
-	longestHike, _ = maxInt(solutions1)
-	fmt.Printf("Part 1: %d\n", longestHike)
+	progress := aoc.NewProgress(ctx, "steps", 0)
+	WalkToBottom(ctx, progress, &solutions1, true, hikingTrails, stepped, -1, x, y)
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	longestHike, err := maxInt(solutions1)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	WalkToBottom(&solutions2, false, hikingTrails, stepped, -1, x, y)
-	longestHike, _ = maxInt(solutions2)
-	fmt.Printf("Part 2: %d\n", longestHike)
+	WalkToBottom(ctx, progress, &solutions2, false, hikingTrails, stepped, -1, x, y)
+	progress.Done()
+	if err := ctx.Err(); err != nil {
+		return longestHike, nil, err
+	}
+	longestDryHike, err := maxInt(solutions2)
+	if err != nil {
+		return longestHike, nil, err
+	}
+	return longestHike, longestDryHike, nil
 }
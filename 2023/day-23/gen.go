@@ -0,0 +1,66 @@
+package day23
+
+import (
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+	"github.com/havill/AdventOfCode/grid"
+)
+
+// Generate makes a size by size maze of trails from the top left to the
+// bottom right, with a couple of walls knocked through so that there is
+// more than one way down, and slopes along the way that the maze was first
+// dug. The solver tries every hike, copying the map at each step, so the
+// maze is kept small.
+func Generate(rng *rand.Rand, size int) []byte {
+	size = gen.Clamp(size, 5, 41) | 1
+	trails := grid.New[Tile](size, size)
+	for p := range trails.All() {
+		trails.Set(p, Forest)
+	}
+
+	// dig out the maze from the start, remembering the way to each cell;
+	// the cells are every other tile, with the walls between them
+	from := map[grid.Point]grid.Point{}
+	start := grid.Point{X: 1, Y: 1}
+	trails.Set(start, Path)
+	stack := []grid.Point{start}
+	for len(stack) > 0 {
+		here := stack[len(stack)-1]
+		var next []grid.Point
+		for _, step := range []grid.Point{grid.North, grid.East, grid.South, grid.West} {
+			p := here.Add(step).Add(step)
+			if p.X > 0 && p.Y > 0 && p.X < size-1 && p.Y < size-1 && trails.At(p) == Forest {
+				next = append(next, p)
+			}
+		}
+		if len(next) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		p := next[rng.IntN(len(next))]
+		trails.Set(grid.Point{X: (here.X + p.X) / 2, Y: (here.Y + p.Y) / 2}, Path)
+		trails.Set(p, Path)
+		from[p] = here
+		stack = append(stack, p)
+	}
+
+	// the slopes lead down the way the maze was dug to the end
+	slopes := map[grid.Point]Tile{grid.North: North, grid.East: East, grid.South: South, grid.West: West}
+	for p := (grid.Point{X: size - 2, Y: size - 2}); p != start; p = from[p] {
+		if rng.IntN(3) == 0 {
+			q := from[p]
+			step := grid.Point{X: (p.X - q.X) / 2, Y: (p.Y - q.Y) / 2}
+			trails.Set(q.Add(step), slopes[step])
+		}
+	}
+	for range 2 {
+		p := grid.Point{X: gen.Between(rng, 1, size-2), Y: gen.Between(rng, 1, size-2)}
+		if p.X%2 != p.Y%2 {
+			trails.Set(p, Path)
+		}
+	}
+	trails.Set(grid.Point{X: 1, Y: 0}, Path)
+	trails.Set(grid.Point{X: size - 2, Y: size - 1}, Path)
+	return gen.Grid(size, size, func(x, y int) byte { return byte(trails.At(grid.Point{X: x, Y: y})) })
+}
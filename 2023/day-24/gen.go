@@ -0,0 +1,32 @@
+package day24
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes size hailstones, all of them hit at some time by one rock
+// thrown from around the middle of the test area, so that part 2 has an
+// answer.
+func Generate(rng *rand.Rand, size int) []byte {
+	var rock, throw [3]int
+	for i := range rock {
+		rock[i] = gen.Between(rng, 250_000_000_000_000, 350_000_000_000_000)
+		throw[i] = gen.Between(rng, -200, 200)
+	}
+
+	var b bytes.Buffer
+	for range gen.Clamp(size, 3, 300) {
+		var pos, vel [3]int
+		hit := gen.Between(rng, 1, 100_000_000_000)
+		for i := range pos {
+			vel[i] = gen.Between(rng, -300, 300)
+			pos[i] = rock[i] + (throw[i]-vel[i])*hit
+		}
+		fmt.Fprintf(&b, "%d, %d, %d @ %d, %d, %d\n", pos[0], pos[1], pos[2], vel[0], vel[1], vel[2])
+	}
+	return b.Bytes()
+}
@@ -1,27 +1,31 @@
-package main
+package day24
 
 import (
-	"fmt"
-	"os"
+	"context"
+	"io"
 	"slices"
-	"strconv"
-	"strings"
-)
-
-func getLines(filename string) []string {
-	contents, _ := os.ReadFile(filename)
-	lines := strings.Split(string(contents), "\n")
-	return lines
-}
 
-func day24() {
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/parse"
+)
 
-	lines := getLines("input.txt")
-	hailStones := parseHailstones(lines)
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	hailStones, err := parseHailstones(lines)
+	if err != nil {
+		return nil, nil, err
+	}
+	aoc.Parsed(ctx)
 
 	areaMin, areaMax := float64(200000000000000), float64(400000000000000)
 	intersectCount := 0
 	for i := 0; i < len(hailStones)-1; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
 		for j := i + 1; j < len(hailStones); j++ {
 			a, b := hailStones[i], hailStones[j]
 			if point, does := hailstonesIntersect(a, b); does {
@@ -42,10 +46,13 @@ func day24() {
 	}
 
 	var result = intersectCount
-	fmt.Println("Day 24 Part 1 Result: ", result)
+	aoc.Part1Done(ctx)
 
 	maybeX, maybeY, maybeZ := []int{}, []int{}, []int{}
 	for i := 0; i < len(hailStones)-1; i++ {
+		if err := ctx.Err(); err != nil {
+			return result, nil, err
+		}
 		for j := i + 1; j < len(hailStones); j++ {
 			a, b := hailStones[i], hailStones[j]
 			if a.vel.x == b.vel.x {
@@ -91,7 +98,7 @@ func day24() {
 		result2 = int(xPos + yPos + zPos)
 	}
 
-	fmt.Println("Day 24 Part 2 Result: ", result2)
+	return result, result2, nil
 }
 
 func findMatchingVel(dvel, pv int) []int {
@@ -145,33 +152,48 @@ func vectorCross(a, b Vector2) float64 {
 	return (a.x * b.y) - (a.y * b.x)
 }
 
-func parseHailstones(lines []string) []Hailstone {
+func parseHailstones(lines []parse.Line) ([]Hailstone, error) {
 	hailStones := make([]Hailstone, 0, len(lines))
 	for _, line := range lines {
-		split := strings.Split(line, " @ ")
-		coords := commaSepToIntArr(split[0])
-		vels := commaSepToIntArr(split[1])
-		hailStone := Hailstone{Vector3{float64(coords[0]), float64(coords[1]), float64(coords[2])}, Vector3{float64(vels[0]), float64(vels[1]), float64(vels[2])}}
-		hailStones = append(hailStones, hailStone)
-	}
-	return hailStones
-}
-
-func commaSepToIntArr(s string) []int {
-	strArr := strings.Split(s, ",")
-	intArr := make([]int, len(strArr))
-	for i, str := range strArr {
-		num, _ := strconv.Atoi(str)
-		intArr[i] = num
+		if line.Blank() {
+			continue
+		}
+		pos, vel, err := line.Pair("@")
+		if err != nil {
+			return nil, err
+		}
+		coords, err := vector(pos)
+		if err != nil {
+			return nil, err
+		}
+		vels, err := vector(vel)
+		if err != nil {
+			return nil, err
+		}
+		hailStones = append(hailStones, Hailstone{coords, vels})
 	}
-	return intArr
+	return hailStones, nil
 }
 
-func main() {
+// exact is as far as a float64 can count without skipping any integers.
+const exact = 1 << 53
 
-	funcs := []func(){day24}
-
-	for _, f := range funcs {
-		f()
+// vector reads three numbers separated by commas, as in "19, 13, 30".
+func vector(l parse.Line) (Vector3, error) {
+	fields := l.Split(",")
+	if len(fields) != 3 {
+		return Vector3{}, l.Errorf("expected 3 numbers, found %d", len(fields))
+	}
+	var n [3]float64
+	for i, f := range fields {
+		v, err := f.Int()
+		if err != nil {
+			return Vector3{}, err
+		}
+		if v > exact || v < -exact {
+			return Vector3{}, f.Trim().Errorf("%d is too large to work with exactly", v)
+		}
+		n[i] = float64(v)
 	}
+	return Vector3{n[0], n[1], n[2]}, nil
 }
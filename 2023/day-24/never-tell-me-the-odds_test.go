@@ -0,0 +1,39 @@
+package day24
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/parse"
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+func readHailstones(r io.Reader) ([]Hailstone, error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseHailstones(lines)
+}
+
+func writeHailstones(hailStones []Hailstone) string {
+	var b strings.Builder
+	for _, h := range hailStones {
+		fmt.Fprintf(&b, "%.0f, %.0f, %.0f @ %.0f, %.0f, %.0f\n", h.pos.x, h.pos.y, h.pos.z, h.vel.x, h.vel.y, h.vel.z)
+	}
+	return b.String()
+}
+
+func FuzzParseHailstones(f *testing.F) {
+	parsetest.Seed(f,
+		"19, 13, 30 @ -2,  1, -2\n18, 19, 22 @ -1, -1, -2\n20, 25, 34 @ -2, -2, -4\n",
+		"262130794315133, 305267994111063, 163273807102793 @ 57, -252, 150\n",
+		"19, 13 @ -2, 1, -2\n",
+		"9223372036854775807, 0, 0 @ 0, 0, 0\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, readHailstones, writeHailstones)
+	})
+}
@@ -0,0 +1,48 @@
+package day25
+
+import (
+	"bytes"
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes a wiring diagram of two groups of five to size/2
+// components joined by three wires. Within a group each component is wired
+// to the next two around a ring, so no other three wires split the diagram
+// in two.
+func Generate(rng *rand.Rand, size int) []byte {
+	most := gen.Clamp(size/2, 5, 750)
+	left, right := gen.Between(rng, 5, most), gen.Between(rng, 5, most)
+	names := gen.Words(rng, left+right, 3, "abcdefghijklmnopqrstuvwxyz")
+	wired := make(map[string][]string)
+	wire := func(a, b string) {
+		if rng.IntN(2) == 0 {
+			a, b = b, a
+		}
+		wired[a] = append(wired[a], b)
+	}
+	for _, group := range [][]string{names[:left], names[left:]} {
+		for i, name := range group {
+			wire(name, group[(i+1)%len(group)])
+			wire(name, group[(i+2)%len(group)])
+		}
+	}
+	from, to := rng.Perm(left)[:3], rng.Perm(right)[:3]
+	for i := range 3 {
+		wire(names[from[i]], names[left+to[i]])
+	}
+
+	var b bytes.Buffer
+	for _, i := range rng.Perm(len(names)) {
+		if len(wired[names[i]]) == 0 {
+			continue
+		}
+		b.WriteString(names[i] + ":")
+		for _, other := range wired[names[i]] {
+			b.WriteString(" " + other)
+		}
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
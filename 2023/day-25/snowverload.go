@@ -1,207 +1,39 @@
-package main
+package day25
 
 import (
-	"bufio"
-	"flag"
-	"fmt"
-	"os"
+	"context"
+	"errors"
+	"io"
 	"regexp"
 	"strings"
 
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/graph"
+	"github.com/havill/AdventOfCode/parse"
 	combinations "github.com/mxschmitt/golang-combinations"
 )
 
-var debug bool
-
-type Graph struct {
-	edge map[string][]string
-}
-
-type EdgePair struct {
-	a string
-	b string
-}
-
-func NewGraph() *Graph {
-	return &Graph{
-		edge: make(map[string][]string),
-	}
-}
-
-func CloneGraph(g *Graph) *Graph {
-	newGraph := NewGraph()
-	for node, edges := range g.edge {
-		newEdges := make([]string, len(edges))
-		copy(newEdges, edges)
-		newGraph.edge[node] = newEdges
-	}
-	return newGraph
-}
-
-func (g *Graph) AddEdge(node1, node2 string, directed bool) {
-	// Add edge from node1 to node2
-	g.edge[node1] = append(g.edge[node1], node2)
-
-	if !directed {
-		// Add edge from node2 to node1
-		g.edge[node2] = append(g.edge[node2], node1)
-	}
-}
-
-func (g *Graph) DeleteEdge(node1, node2 string, directed bool) {
-	g.edge[node1] = remove(g.edge[node1], node2)
-	if !directed {
-		g.edge[node2] = remove(g.edge[node2], node1)
-	}
-}
-
-func remove(slice []string, val string) []string {
-	for i, item := range slice {
-		if item == val {
-			return append(slice[:i], slice[i+1:]...)
-		}
-	}
-	return slice
-}
-
-func (g *Graph) SimpleDFS(node string, visited map[string]bool) {
-	if _, ok := visited[node]; ok {
-		return
-	}
-	visited[node] = true
-	for _, neighbor := range g.edge[node] {
-		g.SimpleDFS(neighbor, visited)
-	}
-}
-
-func (g *Graph) DFS(v string, visited map[string]bool, parent string) bool {
-	visited[v] = true
-
-	for _, i := range g.edge[v] {
-		if !visited[i] {
-			if g.DFS(i, visited, v) {
-				return true
-			}
-		} else if i != parent {
-			return true
-		}
-	}
-
-	return false
-}
-
-func (g *Graph) DetectCycle() bool {
-	visited := make(map[string]bool)
-
-	for v := range g.edge {
-		if !visited[v] {
-			if g.DFS(v, visited, "") {
-				return true
-			}
-		}
-	}
-
-	return false
-}
-
-func (g *Graph) CountEdges() int {
-	edgeMap := make(map[EdgePair]bool)
-	for node, neighbors := range g.edge {
-		for _, neighbor := range neighbors {
-			// Ensure the edge is always represented in the same way,
-			// regardless of the order of the nodes
-			edge := EdgePair{node, neighbor}
-			if node > neighbor {
-				edge = EdgePair{neighbor, node}
-			}
-			edgeMap[edge] = true
-		}
-	}
-	return len(edgeMap)
-}
+var (
+	log      = aoc.Logger("search")
+	parseLog = aoc.Logger("parser")
+)
 
-func (g *Graph) CountNodes() int {
-	visited := make(map[string]bool)
-	for node := range g.edge {
-		g.SimpleDFS(node, visited)
-	}
-	return len(visited)
-}
+type Graph = graph.Graph[string]
 
+// CountNodesInComponents returns the size of each group of connected nodes.
 func CountNodesInComponents(g *Graph) []int {
-	visited := make(map[string]bool)
 	counts := []int{}
-
-	for node := range g.edge {
-		if visited[node] {
-			continue
-		}
-
-		count := CountDFS(g, node, visited)
-		counts = append(counts, count)
+	for _, component := range g.Components() {
+		counts = append(counts, len(component))
 	}
-
 	return counts
 }
 
-func CountDFS(g *Graph, node string, visited map[string]bool) int {
-	visited[node] = true
-	count := 1
-
-	for _, neighbor := range g.edge[node] {
-		if !visited[neighbor] {
-			count += CountDFS(g, neighbor, visited)
-		}
-	}
-
-	return count
-}
-
-func (g *Graph) CountComponents() int {
-	visited := make(map[string]bool)
-	count := 0
-	for node := range g.edge {
-		if _, ok := visited[node]; !ok {
-			g.SimpleDFS(node, visited)
-			count++
-		}
-	}
-	return count
-}
-
-func (g *Graph) EdgeExists(node1, node2 string) bool {
-	for _, neighbor := range g.edge[node1] {
-		if neighbor == node2 {
-			return true
-		}
-	}
-	return false
-}
-
-func (g *Graph) IterateEdges(directed bool) []EdgePair {
-	var list []EdgePair
-
-	for node, edges := range g.edge {
-		for _, edge := range edges {
-			if directed || node < edge {
-				list = append(list, EdgePair{a: node, b: edge})
-			}
-		}
+func logGraph(msg string, g *Graph) {
+	if aoc.Debugging() {
+		log.Debug(msg, "cycles", g.HasCycle(), "nodes", g.Len(), "edges", g.EdgeCount(),
+			"components", CountNodesInComponents(g))
 	}
-
-	return list
-}
-
-func (g *Graph) String() string {
-	var result strings.Builder
-	for node, neighbors := range g.edge {
-		result.WriteString(node + ": ")
-		for _, neighbor := range neighbors {
-			result.WriteString(neighbor + " ")
-		}
-		result.WriteString("\n")
-	}
-	return result.String()
 }
 
 func arrayProduct(nums []int) int {
@@ -215,70 +47,106 @@ func arrayProduct(nums []int) int {
 // note that the Advent of Code problem uses the term "components" to refer to
 // groups of snow producing components (iow, nodes / vertexes in CompSci terms)
 // connected by "wires" (iow, edges in CompSci terms), not the traditional usage
-func FindWiresToCut(g *Graph, groups, toDisconnect int) *Graph {
+//
+// FindWiresToCut tries every toDisconnect wires in turn, which takes far too
+// long for a real diagram, but it stays to check CutInThree. It returns nil
+// if no wires split the graph into groups, and ctx's error if ctx is done
+// first.
+func FindWiresToCut(ctx context.Context, progress *aoc.Progress, g *Graph, groups, toDisconnect int) (*Graph, error) {
 	// Find all edges in the graph
-	wires := g.IterateEdges(false)
+	wires := g.Edges()
 	combos := combinations.Combinations(wires, toDisconnect)
 
-	if debug {
-		fmt.Println("trying", len(combos), "combinations of", len(wires), "wires")
-	}
+	log.Debug("cutting wires", "combinations", len(combos), "wires", len(wires))
+	defer progress.Done()
 	// Iterate over all edges and remove them from the graph
 	// If the graph contains a cycle after removing the edge,
 	// then it is a critical edge
 	for _, set := range combos {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		// Make a copy of the graph
-		newGraph := CloneGraph(g)
+		newGraph := g.Clone()
 
 		for _, edge := range set {
-			newGraph.DeleteEdge(edge.a, edge.b, false)
+			newGraph.RemoveEdge(edge.From, edge.To)
 		}
-		newGroups := newGraph.CountComponents()
+		newGroups := len(newGraph.Components())
 		if newGroups == groups {
-			return newGraph
+			return newGraph, nil
 		}
+		progress.Add(1)
 	}
-	return nil
+	return nil, nil
 }
 
-func main() {
-	flag.BoolVar(&debug, "debug", false, "enable debug mode")
-	flag.Parse()
-
-	if debug {
-		fmt.Println("Debug mode enabled")
-	}
-
-	before := NewGraph()
+// wiringLine is how every line of the diagram is written, checked only in
+// strict mode.
+var wiringLine = regexp.MustCompile(`^[a-z]+:( [a-z]+)+$`)
 
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		line := scanner.Text()
+// parseWiring reads the wiring diagram as a graph of components joined by
+// wires.
+func parseWiring(ctx context.Context, r io.Reader) (*Graph, error) {
+	g := graph.New[string](false)
 
-		re := regexp.MustCompile(`[:\s]+`)
-		nodes := re.Split(line, -1)
-		for i := 1; i < len(nodes); i++ {
-			before.AddEdge(nodes[0], nodes[i], false)
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range lines {
+		if line.Blank() {
+			continue
+		}
+		if parse.Strict && !wiringLine.MatchString(line.Text) {
+			return nil, line.Errorf("expected a component and those it is wired to, such as \"jqt: rhn xhk nvd\"")
+		}
+		node, wires, err := line.Cut(":")
+		if err != nil {
+			return nil, err
+		}
+		name := node.Fields()
+		if len(name) != 1 || strings.Contains(wires.Text, ":") {
+			if err := parse.Tolerate(ctx, parseLog, line.Errorf("expected one component before one colon")); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		for _, wire := range wires.Fields() {
+			g.AddEdge(name[0].Text, wire.Text)
 		}
 	}
+	return g, nil
+}
 
-	if err := scanner.Err(); err != nil {
-		fmt.Fprintln(os.Stderr, "reading standard input:", err)
+// CutInThree finds the three wires that split the components into two
+// groups, and returns the size of each group, or nil if there are no such
+// wires. Two components in different groups can only be reached from each
+// other through those three wires, so it looks for a component that the
+// first can only reach that way. It stops with ctx's error once ctx is done.
+func CutInThree(ctx context.Context, g *Graph) ([]int, error) {
+	nodes := g.Nodes()
+	for _, to := range nodes[min(1, len(nodes)):] {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if n, side, ok := g.MinCut(nodes[0], to, 3); ok && n == 3 {
+			return []int{len(side), g.Len() - len(side)}, nil
+		}
 	}
+	return nil, nil
+}
 
-	if debug {
-		//fmt.Println(g)
-		//fmt.Println(g.IterateEdges(false))
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	wiring, err := parseWiring(ctx, r)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	fmt.Println("BEFORE DELETION")
-	fmt.Println("Does the graph contain cycle(s)?", before.DetectCycle())
-	fmt.Println("Number of nodes in the graph   :", before.CountNodes())
-	fmt.Println("Number of edges in the graph   :", before.CountEdges())
-	fmt.Println("Number of components in graph  :", before.CountComponents())
-	fmt.Println("Number of nodes in components  :", CountNodesInComponents(before))
-
-	after := FindWiresToCut(before, 2, 3)
+	if aoc.Tracing() {
+		aoc.Trace(log, "wiring\n"+wiring.String())
+	}
+	logGraph("before cutting", wiring)
 
 	// https://adventofcode.com/2023/day/25
 	// solution = g.CloneGraph(solution)
@@ -286,17 +154,13 @@ func main() {
 	// solution.DeleteEdge("bvb", "cmg", false)
 	// solution.DeleteEdge("nvd", "jqt", false)
 
-	if after == nil {
-		fmt.Println("No solution found")
-		return
-	} else {
-		fmt.Println("AFTER DELETION")
-		fmt.Println("Does the graph contain cycle(s)?", after.DetectCycle())
-		fmt.Println("Number of nodes in the graph   :", after.CountNodes())
-		fmt.Println("Number of edges in the graph   :", after.CountEdges())
-		fmt.Println("Number of components in graph  :", after.CountComponents())
-		fmt.Println("Number of nodes in components  :", CountNodesInComponents(after))
-
-		fmt.Println("Part 1: ", arrayProduct(CountNodesInComponents(after)))
+	groups, err := CutInThree(ctx, wiring)
+	if err != nil {
+		return nil, nil, err
+	}
+	if groups == nil {
+		return nil, nil, errors.New("no solution found")
 	}
+	log.Debug("after cutting", "groups", groups)
+	return arrayProduct(groups), nil, nil
 }
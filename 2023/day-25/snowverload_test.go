@@ -0,0 +1,76 @@
+package day25
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/gen/gentest"
+	"github.com/havill/AdventOfCode/graph"
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+// readWires reads the wiring as its wires, each with its ends in order, so
+// that diagrams that list the same wires differently compare equal.
+func readWires(r io.Reader) ([]graph.Edge[string], error) {
+	g, err := parseWiring(context.Background(), r)
+	if err != nil {
+		return nil, err
+	}
+	wires := g.Edges()
+	for i, w := range wires {
+		if w.From > w.To {
+			wires[i].From, wires[i].To = w.To, w.From
+		}
+	}
+	slices.SortFunc(wires, func(a, b graph.Edge[string]) int {
+		return cmp.Or(cmp.Compare(a.From, b.From), cmp.Compare(a.To, b.To))
+	})
+	return wires, nil
+}
+
+func writeWires(wires []graph.Edge[string]) string {
+	var b strings.Builder
+	for _, w := range wires {
+		fmt.Fprintf(&b, "%s: %s\n", w.From, w.To)
+	}
+	return b.String()
+}
+
+func FuzzParseWiring(f *testing.F) {
+	parsetest.Seed(f,
+		"jqt: rhn xhk nvd\nrsh: frs pzl lsr\nxhk: hfx\ncmg: qnr nvd lhk bvb\n",
+		"a: a\n\nb:\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, readWires, writeWires)
+	})
+}
+
+// TestCutInThree checks the cut against trying every three wires, which
+// only gets through the smallest diagrams.
+func TestCutInThree(t *testing.T) {
+	product := func(cut func(context.Context, *Graph) ([]int, error)) func(io.Reader) (int, error) {
+		return func(r io.Reader) (int, error) {
+			g, err := parseWiring(context.Background(), r)
+			if err != nil {
+				return 0, err
+			}
+			groups, err := cut(context.Background(), g)
+			return arrayProduct(groups), err
+		}
+	}
+	exhaustive := func(ctx context.Context, g *Graph) ([]int, error) {
+		cut, err := FindWiresToCut(ctx, aoc.NewProgress(ctx, "wires", 0), g, 2, 3)
+		if err != nil {
+			return nil, err
+		}
+		return CountNodesInComponents(cut), nil
+	}
+	gentest.Compare(t, Generate, 12, 8, product(CutInThree), product(exhaustive))
+}
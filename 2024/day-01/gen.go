@@ -0,0 +1,28 @@
+package day01
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes two lists of size location IDs side by side. Some of the
+// right list are taken from the left, so that the lists have something in
+// common.
+func Generate(rng *rand.Rand, size int) []byte {
+	left := make([]int, size)
+	for i := range left {
+		left[i] = gen.Between(rng, 10000, 99999)
+	}
+	var b bytes.Buffer
+	for _, id := range left {
+		right := gen.Between(rng, 10000, 99999)
+		if rng.IntN(2) == 0 {
+			right = left[rng.IntN(len(left))]
+		}
+		fmt.Fprintf(&b, "%d   %d\n", id, right)
+	}
+	return b.Bytes()
+}
@@ -1,22 +1,27 @@
-package main
+package day01
 
 import (
-	"bufio"
-	"fmt"
+	"context"
+	"io"
 	"math"
-	"os"
 	"sort"
-	"strconv"
-	"strings"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/parse"
+)
+
+var (
+	log      = aoc.Logger("search")
+	parseLog = aoc.Logger("parser")
 )
 
 func sortSlice(slice []int) {
 	sort.Ints(slice)
 }
 
-func distanceList(leftList, rightList []int) []int {
+func distanceList(ctx context.Context, leftList, rightList []int) []int {
 	if len(leftList) != len(rightList) {
-		fmt.Println("Error: Lists are not of the same length")
+		log.WarnContext(ctx, "lists are not the same length", "left", len(leftList), "right", len(rightList))
 		return nil
 	}
 
@@ -49,49 +54,58 @@ func similarityScore(leftList, rightList []int) []int {
 	return similarityScore
 }
 
-func main() {
-	var leftList []int
-	var rightList []int
-
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		line := scanner.Text()
-		numbers := strings.Fields(line)
-		if len(numbers) != 2 {
-			fmt.Println("Each line must contain exactly two integers")
-			continue
+func parseLists(ctx context.Context, r io.Reader) (leftList, rightList []int, err error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, line := range lines {
+		numbers, err := line.Ints()
+		if err == nil && len(numbers) != 2 {
+			err = line.Errorf("expected two numbers, found %d", len(numbers))
 		}
-
-		left, err1 := strconv.Atoi(numbers[0])
-		right, err2 := strconv.Atoi(numbers[1])
-		if err1 != nil || err2 != nil {
-			fmt.Println("Invalid input, please enter integers")
+		if err != nil {
+			if err := parse.Tolerate(ctx, parseLog, err); err != nil {
+				return nil, nil, err
+			}
 			continue
 		}
 
-		leftList = append(leftList, left)
-		rightList = append(rightList, right)
+		leftList = append(leftList, numbers[0])
+		rightList = append(rightList, numbers[1])
 	}
+	return leftList, rightList, nil
+}
 
-	if err := scanner.Err(); err != nil {
-		fmt.Println("Error reading input:", err)
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	leftList, rightList, err := parseLists(ctx, r)
+	if err != nil {
+		return nil, nil, err
 	}
+	aoc.Parsed(ctx)
 
 	sortSlice(leftList)
 	sortSlice(rightList)
+	if aoc.Tracing() {
+		aoc.Trace(parseLog, "sorted", "left", leftList, "right", rightList)
+	}
 
-	//fmt.Println("Left List:", leftList)
-	//fmt.Println("Right List:", rightList)
-
-	distances := distanceList(leftList, rightList)
-	//fmt.Println("Distances:", distances)
+	distances := distanceList(ctx, leftList, rightList)
+	if aoc.Tracing() {
+		aoc.Trace(log, "distances", "distances", distances)
+	}
 
 	sum := sumSlice(distances)
-	fmt.Println("Sum of Distances:", sum)
+	aoc.Part1Done(ctx)
+	if err := ctx.Err(); err != nil {
+		return sum, nil, err
+	}
 
 	similarity := similarityScore(leftList, rightList)
-	//fmt.Println("Similarity Score:", similarity)
+	if aoc.Tracing() {
+		aoc.Trace(log, "similarity", "scores", similarity)
+	}
 
 	totalSimilarityScore := sumSlice(similarity)
-	fmt.Println("Total Similarity Score:", totalSimilarityScore)
+	return sum, totalSimilarityScore, nil
 }
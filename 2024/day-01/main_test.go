@@ -0,0 +1,38 @@
+package day01
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+type lists struct {
+	left, right []int
+}
+
+func readLists(r io.Reader) (lists, error) {
+	left, right, err := parseLists(context.Background(), r)
+	return lists{left, right}, err
+}
+
+func writeLists(l lists) string {
+	var b strings.Builder
+	for i := range l.left {
+		fmt.Fprintf(&b, "%d   %d\n", l.left[i], l.right[i])
+	}
+	return b.String()
+}
+
+func FuzzParseLists(f *testing.F) {
+	parsetest.Seed(f,
+		"3   4\n4   3\n2   5\n1   3\n3   9\n3   3\n",
+		"1 2 3\n\n-4 x\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, readLists, writeLists)
+	})
+}
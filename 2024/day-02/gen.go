@@ -0,0 +1,37 @@
+package day02
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes size reports of five to eight levels. Each starts out
+// safe, steadily rising or falling, and about half then have a level or
+// two changed, which may or may not make them unsafe.
+func Generate(rng *rand.Rand, size int) []byte {
+	var b bytes.Buffer
+	for range size {
+		levels := make([]int, gen.Between(rng, 5, 8))
+		levels[0] = gen.Between(rng, 30, 70)
+		direction := 1 - 2*rng.IntN(2)
+		for i := 1; i < len(levels); i++ {
+			levels[i] = levels[i-1] + direction*gen.Between(rng, 1, 3)
+		}
+		if rng.IntN(2) == 0 {
+			for range gen.Between(rng, 1, 2) {
+				levels[rng.IntN(len(levels))] += gen.Between(rng, -4, 4)
+			}
+		}
+		for i, level := range levels {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			fmt.Fprint(&b, level)
+		}
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
@@ -1,12 +1,17 @@
-package main
+package day02
 
 import (
-	"bufio"
-	"fmt"
+	"context"
+	"io"
 	"math"
-	"os"
-	"strconv"
-	"strings"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/parse"
+)
+
+var (
+	log      = aoc.Logger("search")
+	parseLog = aoc.Logger("parser")
 )
 
 func isSafe(report []int) bool {
@@ -31,9 +36,10 @@ func isSafe(report []int) bool {
 }
 
 func problemDampener(report []int) bool {
-	//fmt.Printf("testing %v\n", report)
 	if isSafe(report) {
-		//fmt.Println("...Safe without removing any level")
+		if aoc.Tracing() {
+			aoc.Trace(log, "safe", "report", report)
+		}
 		return true
 	}
 
@@ -41,62 +47,71 @@ func problemDampener(report []int) bool {
 		var badLevelRemoved []int
 		badLevelRemoved = append(badLevelRemoved, report[:i]...)
 		badLevelRemoved = append(badLevelRemoved, report[i+1:]...)
-		//fmt.Printf("...testing with %v\n", badLevelRemoved)
 		if isSafe(badLevelRemoved) {
-			/*
-				ending := "th"
-				if i == 0 {
-					ending = "st"
-				} else if i == 1 {
-					ending = "nd"
-				} else if i == 2 {
-					ending = "rd"
-				}
-				fmt.Printf("... Safe by removing the %d%s level, %d\n", i+1, ending, report[i])
-			*/
+			if aoc.Tracing() {
+				aoc.Trace(log, "safe without a level", "report", report, "removed", i+1, "level", report[i])
+			}
 			return true
 		}
 	}
-	//fmt.Println("...Unsafe regardless of which level is removed")
+	if aoc.Tracing() {
+		aoc.Trace(log, "unsafe", "report", report)
+	}
 	return false
 }
 
-func main() {
+func parseReports(ctx context.Context, r io.Reader) ([][]int, error) {
 	var reports [][]int
 
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		line := scanner.Text()
-		numbers := strings.Fields(line)
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range lines {
 		var levels []int
-		for _, number := range numbers {
-			level, err := strconv.Atoi(number)
+		for _, number := range line.Fields() {
+			level, err := number.Int()
 			if err != nil {
-				fmt.Println("Invalid input, please enter integers")
+				if err := parse.Tolerate(ctx, parseLog, err); err != nil {
+					return nil, err
+				}
 				continue
 			}
 			levels = append(levels, level)
 		}
+		if parse.Strict && len(levels) == 0 {
+			return nil, line.Errorf("expected a report")
+		}
 		reports = append(reports, levels)
 	}
+	return reports, nil
+}
 
-	if err := scanner.Err(); err != nil {
-		fmt.Println("Error reading input:", err)
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	reports, err := parseReports(ctx, r)
+	if err != nil {
+		return nil, nil, err
 	}
+	aoc.Parsed(ctx)
 
 	safeCount := 0
 	for _, report := range reports {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
 		if isSafe(report) {
 			safeCount++
 		}
 	}
-	fmt.Printf("Number of Safe Reports: %d\n", safeCount)
-
-	safeCount = 0
+	aoc.Part1Done(ctx)
+	dampenedCount := 0
 	for _, report := range reports {
+		if err := ctx.Err(); err != nil {
+			return safeCount, nil, err
+		}
 		if problemDampener(report) {
-			safeCount++
+			dampenedCount++
 		}
 	}
-	fmt.Printf("Number of Safe Reports with Dampening: %d\n", safeCount)
+	return safeCount, dampenedCount, nil
 }
@@ -0,0 +1,28 @@
+package day02
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+func writeReports(reports [][]int) string {
+	var b strings.Builder
+	for _, report := range reports {
+		b.WriteString(strings.Trim(fmt.Sprint(report), "[]"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func FuzzParseReports(f *testing.F) {
+	parsetest.Seed(f,
+		"7 6 4 2 1\n1 2 7 8 9\n9 7 6 2 1\n1 3 2 4 5\n8 6 4 4 1\n1 3 6 7 9\n",
+		"1 x 2\n\n3\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, parsetest.Background(parseReports), writeReports)
+	})
+}
@@ -0,0 +1,36 @@
+package day03
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// junk is the corruption that the instructions are scattered through.
+var junk = []string{
+	"!", "@", "#", "$", "%", "^", "&", "*", "(", ")", "[", "]", "{", "}", "<", ">",
+	"?", "+", "-", ",", ";", ":", "'", " ", "from()", "what()", "why()", "select()",
+	"mul", "mul[", "mul(", "mul )", "do", "don't", "mul(4*", "mul ( 2 , 4 )",
+}
+
+// Generate makes size lines of corrupted memory, with the multiplications
+// and the do() and don't() that switch them on and off among the junk.
+func Generate(rng *rand.Rand, size int) []byte {
+	var b bytes.Buffer
+	for range size {
+		for range gen.Between(rng, 5, 20) {
+			switch n := rng.IntN(10); {
+			case n < 4:
+				fmt.Fprintf(&b, "mul(%d,%d)", gen.Between(rng, 1, 999), gen.Between(rng, 1, 999))
+			case n < 5:
+				b.WriteString([]string{"do()", "do()", "don't()"}[rng.IntN(3)])
+			default:
+				b.WriteString(junk[rng.IntN(len(junk))])
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
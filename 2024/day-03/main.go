@@ -1,43 +1,54 @@
-package main
+package day03
 
 import (
 	"bufio"
-	"fmt"
-	"os"
+	"context"
+	"io"
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/havill/AdventOfCode/aoc"
 )
 
+var log = aoc.Logger("parser")
+
 func enableMul() {
 	// Placeholder function for enabling multiplication
-	fmt.Println("enableMul called")
+	aoc.Trace(log, "do()")
 }
 
 func disableMul() {
 	// Placeholder function for disabling multiplication
-	fmt.Println("disableMul called")
+	aoc.Trace(log, "don't()")
 }
 
-func multiplyFactors(factors string) int {
+func multiplyFactors(ctx context.Context, factors string) int {
 	parts := strings.Split(factors, ",")
 	if len(parts) != 2 {
-		fmt.Println("Invalid factors format")
+		log.WarnContext(ctx, "invalid factors", "factors", factors)
 		return 0
 	}
 
 	x, err1 := strconv.Atoi(parts[0])
 	y, err2 := strconv.Atoi(parts[1])
 	if err1 != nil || err2 != nil {
-		fmt.Println("Invalid integers in factors")
+		log.WarnContext(ctx, "invalid factors", "factors", factors)
 		return 0
 	}
 
 	return x * y
 }
 
-func main() {
-	scanner := bufio.NewScanner(os.Stdin)
+// instruction is one that can still be made out in the corrupted memory:
+// "mul" with the text of its factors, "do" or "don't".
+type instruction struct {
+	op      string
+	factors string
+}
+
+func parseMemory(r io.Reader) ([]instruction, error) {
+	scanner := bufio.NewScanner(r)
 	scanner.Split(bufio.ScanRunes)
 
 	var input string
@@ -46,42 +57,65 @@ func main() {
 	}
 
 	if err := scanner.Err(); err != nil {
-		fmt.Println("Error reading input:", err)
-		return
+		return nil, err
 	}
 
 	reMul := regexp.MustCompile(`mul\((\d{1,3}),(\d{1,3})\)`)
 	reDo := regexp.MustCompile(`do\(\)`)
 	reDont := regexp.MustCompile(`don't\(\)`)
 
-	state := "ENABLED"
-	sum := 0
-
+	var memory []instruction
 	for len(input) > 0 {
 		dontIndex := reDont.FindStringIndex(input)
 		doIndex := reDo.FindStringIndex(input)
 		mulIndex := reMul.FindStringSubmatchIndex(input)
 
 		if dontIndex != nil && (doIndex == nil || dontIndex[0] < doIndex[0]) && (mulIndex == nil || dontIndex[0] < mulIndex[0]) {
-			disableMul()
-			state = "DISABLED"
+			memory = append(memory, instruction{op: "don't"})
 			input = input[dontIndex[1]:]
 		} else if doIndex != nil && (dontIndex == nil || doIndex[0] < dontIndex[0]) && (mulIndex == nil || doIndex[0] < mulIndex[0]) {
-			enableMul()
-			state = "ENABLED"
+			memory = append(memory, instruction{op: "do"})
 			input = input[doIndex[1]:]
 		} else if mulIndex != nil && (dontIndex == nil || mulIndex[0] < dontIndex[0]) && (doIndex == nil || mulIndex[0] < doIndex[0]) {
-			if state == "ENABLED" {
-				factors := input[mulIndex[2]:mulIndex[5]]
-				result := multiplyFactors(factors)
-				sum += result
-				fmt.Printf("Result of mul(%s): %d\n", factors, result)
-			}
+			memory = append(memory, instruction{op: "mul", factors: input[mulIndex[2]:mulIndex[5]]})
 			input = input[mulIndex[1]:]
 		} else {
 			break
 		}
 	}
+	return memory, nil
+}
+
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	memory, err := parseMemory(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	state := "ENABLED"
+	sum := 0
+
+	for _, in := range memory {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		switch in.op {
+		case "don't":
+			disableMul()
+			state = "DISABLED"
+		case "do":
+			enableMul()
+			state = "ENABLED"
+		case "mul":
+			if state == "ENABLED" {
+				result := multiplyFactors(ctx, in.factors)
+				sum += result
+				if aoc.Tracing() {
+					aoc.Trace(log, "mul", "factors", in.factors, "result", result)
+				}
+			}
+		}
+	}
 
-	fmt.Printf("Sum: %d\n", sum)
+	return nil, sum, nil
 }
@@ -0,0 +1,26 @@
+package day03
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+func writeMemory(memory []instruction) string {
+	var b strings.Builder
+	for _, in := range memory {
+		b.WriteString(in.op + "(" + in.factors + ")")
+	}
+	return b.String()
+}
+
+func FuzzParseMemory(f *testing.F) {
+	parsetest.Seed(f,
+		"xmul(2,4)&mul[3,7]!^don't()_mul(5,5)+mul(32,64](mul(11,8)undo()?mul(8,5))",
+		"mul(1234,5)mul(1,2\nmul( 1,2)do()don't()",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, parseMemory, writeMemory)
+	})
+}
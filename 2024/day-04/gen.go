@@ -0,0 +1,44 @@
+package day04
+
+import (
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+	"github.com/havill/AdventOfCode/grid"
+)
+
+// Generate makes a size by size word search of random letters, with about
+// size more XMAS written into it every which way and size X-MAS crosses.
+func Generate(rng *rand.Rand, size int) []byte {
+	size = gen.Clamp(size, 4, 140)
+	search := grid.New[byte](size, size)
+	for p := range search.All() {
+		search.Set(p, gen.Pick(rng, "XMAS"))
+	}
+	random := func() grid.Point {
+		return grid.Point{X: rng.IntN(size), Y: rng.IntN(size)}
+	}
+	write := func(word string, at, dir grid.Point) {
+		end := at.Add(dir.Mul(len(word) - 1))
+		if !search.In(at) || !search.In(end) {
+			return
+		}
+		for i := range word {
+			search.Set(at.Add(dir.Mul(i)), word[i])
+		}
+	}
+	for range size {
+		write("XMAS", random(), grid.Directions8[rng.IntN(len(grid.Directions8))])
+
+		middle := random()
+		diagonals := []grid.Point{grid.North.Add(grid.West), grid.North.Add(grid.East)}
+		for _, d := range diagonals {
+			if rng.IntN(2) == 0 {
+				write("MAS", middle.Add(d), d.Mul(-1))
+			} else {
+				write("SAM", middle.Add(d), d.Mul(-1))
+			}
+		}
+	}
+	return gen.Grid(size, size, func(x, y int) byte { return search.At(grid.Point{X: x, Y: y}) })
+}
@@ -1,35 +1,25 @@
-package main
+package day04
 
 import (
-	"bufio"
-	"fmt"
-	"os"
+	"context"
+	"io"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/grid"
 )
 
-func xmasSearcher(wordSearch [][]rune) int {
-	directions := [][2]int{
-		{-1, 0}, {1, 0}, {0, -1}, {0, 1}, // up, down, left, right
-		{-1, -1}, {-1, 1}, {1, -1}, {1, 1}, // up-left, up-right, down-left, down-right
-	}
+var log = aoc.Logger("search")
 
-	rows := len(wordSearch)
-	cols := len(wordSearch[0])
+func xmasSearcher(wordSearch *grid.Grid[rune]) int {
 	count := 0
 
-	for i := 0; i < rows; i++ {
-		for j := 0; j < cols; j++ {
-			if wordSearch[i][j] == 'X' {
-				for _, dir := range directions {
-					x, y := i+dir[0], j+dir[1]
-					if x >= 0 && x < rows && y >= 0 && y < cols && wordSearch[x][y] == 'M' {
-						x2, y2 := x+dir[0], y+dir[1]
-						if x2 >= 0 && x2 < rows && y2 >= 0 && y2 < cols && wordSearch[x2][y2] == 'A' {
-							x3, y3 := x2+dir[0], y2+dir[1]
-							if x3 >= 0 && x3 < rows && y3 >= 0 && y3 < cols && wordSearch[x3][y3] == 'S' {
-								count++
-							}
-						}
-					}
+	for p, letter := range wordSearch.All() {
+		if letter == 'X' {
+			for _, dir := range grid.Directions8 {
+				if wordSearch.At(p.Add(dir)) == 'M' &&
+					wordSearch.At(p.Add(dir.Mul(2))) == 'A' &&
+					wordSearch.At(p.Add(dir.Mul(3))) == 'S' {
+					count++
 				}
 			}
 		}
@@ -38,22 +28,20 @@ func xmasSearcher(wordSearch [][]rune) int {
 	return count
 }
 
-func crossMasSearcher(wordSearch [][]rune) int {
-	rows := len(wordSearch)
-	cols := len(wordSearch[0])
+func crossMasSearcher(wordSearch *grid.Grid[rune]) int {
 	count := 0
 
-	for i := 0; i < rows; i++ {
-		for j := 0; j < cols; j++ {
-			if wordSearch[i][j] == 'A' {
-				// Check upper-left and lower-right
-				if i > 0 && j > 0 && i < rows-1 && j < cols-1 {
-					if wordSearch[i-1][j-1] == 'M' && wordSearch[i+1][j+1] == 'S' || wordSearch[i-1][j-1] == 'S' && wordSearch[i+1][j+1] == 'M' {
-						// Check upper-right and lower-left
-						if wordSearch[i-1][j+1] == 'M' && wordSearch[i+1][j-1] == 'S' || wordSearch[i-1][j+1] == 'S' && wordSearch[i+1][j-1] == 'M' {
-							count++
-						}
-					}
+	for p, letter := range wordSearch.All() {
+		if letter == 'A' {
+			upperLeft := wordSearch.At(p.Add(grid.North).Add(grid.West))
+			upperRight := wordSearch.At(p.Add(grid.North).Add(grid.East))
+			lowerLeft := wordSearch.At(p.Add(grid.South).Add(grid.West))
+			lowerRight := wordSearch.At(p.Add(grid.South).Add(grid.East))
+			// Check upper-left and lower-right
+			if upperLeft == 'M' && lowerRight == 'S' || upperLeft == 'S' && lowerRight == 'M' {
+				// Check upper-right and lower-left
+				if upperRight == 'M' && lowerLeft == 'S' || upperRight == 'S' && lowerLeft == 'M' {
+					count++
 				}
 			}
 		}
@@ -62,37 +50,21 @@ func crossMasSearcher(wordSearch [][]rune) int {
 	return count
 }
 
-func main() {
-	var wordSearch [][]rune
-	scanner := bufio.NewScanner(os.Stdin)
-
-	fmt.Println("Enter lines of text (Ctrl+D to end):")
-	for scanner.Scan() {
-		line := scanner.Text()
-		var chars []rune
-		for _, char := range line {
-			chars = append(chars, char)
-		}
-		wordSearch = append(wordSearch, chars)
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	wordSearch, err := grid.Read(r, grid.Expect("XMAS", grid.Runes))
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if err := scanner.Err(); err != nil {
-		fmt.Fprintln(os.Stderr, "reading standard input:", err)
+	if aoc.Debugging() {
+		log.Debug("word search\n" + wordSearch.String())
 	}
 
-	// Print the 2D array
-	/*
-		for _, line := range wordSearch {
-			for _, char := range line {
-				fmt.Printf("%c ", char)
-			}
-			fmt.Println()
-		}
-	*/
-
 	count := xmasSearcher(wordSearch)
-	fmt.Println("Number of 'XMAS' sequences found:", count)
-
-	count = crossMasSearcher(wordSearch)
-	fmt.Println("Number of cross 'MAS' sequences found:", count)
+	aoc.Part1Done(ctx)
+	if err := ctx.Err(); err != nil {
+		return count, nil, err
+	}
+	crossCount := crossMasSearcher(wordSearch)
+	return count, crossCount, nil
 }
@@ -0,0 +1,56 @@
+package day05
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/havill/AdventOfCode/gen"
+)
+
+// Generate makes rules for up to 49 pages that put every pair of them in
+// order, and then size updates of an odd number of pages. About half the
+// updates are already in order.
+func Generate(rng *rand.Rand, size int) []byte {
+	pages := rng.Perm(90)[:gen.Clamp(size, 3, 49)]
+	for i := range pages {
+		pages[i] += 10
+	}
+	rank := make(map[int]int)
+	for i, page := range pages {
+		rank[page] = i
+	}
+
+	var rules [][2]int
+	for i := range pages {
+		for j := i + 1; j < len(pages); j++ {
+			rules = append(rules, [2]int{pages[i], pages[j]})
+		}
+	}
+	rng.Shuffle(len(rules), func(i, j int) { rules[i], rules[j] = rules[j], rules[i] })
+
+	var b bytes.Buffer
+	for _, rule := range rules {
+		fmt.Fprintf(&b, "%d|%d\n", rule[0], rule[1])
+	}
+	b.WriteByte('\n')
+	for range size {
+		n := gen.Between(rng, 1, (min(len(pages), 23)-1)/2)*2 + 1
+		update := make([]int, n)
+		for i, j := range rng.Perm(len(pages))[:n] {
+			update[i] = pages[j]
+		}
+		if rng.IntN(2) == 0 {
+			slices.SortFunc(update, func(a, b int) int { return rank[a] - rank[b] })
+		}
+		for i, page := range update {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			fmt.Fprint(&b, page)
+		}
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
@@ -1,69 +1,86 @@
-package main
+package day05
 
 import (
-	"bufio"
+	"context"
 	"fmt"
-	"os"
-	"strconv"
-	"strings"
+	"io"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/graph"
+	"github.com/havill/AdventOfCode/parse"
+)
+
+var (
+	log      = aoc.Logger("search")
+	parseLog = aoc.Logger("parser")
 )
 
-func loadParseInput() (map[int][]int, [][]int) {
-	rules := make(map[int][]int)
+// loadParseInput reads the page ordering rules as a graph with an edge from
+// each page to every page that must come after it, and the updates.
+func loadParseInput(ctx context.Context, r io.Reader) (*graph.Graph[int], [][]int, error) {
+	rules := graph.New[int](true)
 	var updates [][]int
-	scanner := bufio.NewScanner(os.Stdin)
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	fmt.Println("Enter page ordering rules (blank line to end):")
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
-			break
+	i := 0
+	for ; i < len(lines) && lines[i].Text != ""; i++ {
+		before, after, err := lines[i].Pair("|")
+		var b, a int
+		if err == nil {
+			b, err = before.Int()
 		}
-		parts := strings.Split(line, "|")
-		if len(parts) != 2 {
-			fmt.Fprintln(os.Stderr, "invalid input format")
-			continue
+		if err == nil {
+			a, err = after.Int()
 		}
-		before, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
-		after, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
-		if err1 != nil || err2 != nil {
-			fmt.Fprintln(os.Stderr, "invalid integer value")
+		if err != nil {
+			if err := parse.Tolerate(ctx, parseLog, err); err != nil {
+				return nil, nil, err
+			}
 			continue
 		}
-		rules[before] = append(rules[before], after)
+		rules.AddEdge(b, a)
+	}
+	if parse.Strict && i == 0 {
+		return nil, nil, lines[0].Errorf("expected a rule such as \"47|53\" first")
+	}
+	if parse.Strict && i == len(lines) {
+		return nil, nil, parse.End(lines).Errorf("expected a blank line and then the updates")
 	}
 
-	fmt.Println("Enter updates (Ctrl+D to end):")
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
+	for _, line := range lines[min(i+1, len(lines)):] {
+		if line.Text == "" {
 			continue
 		}
-		parts := strings.Split(line, ",")
 		var pageNumbers []int
-		for _, part := range parts {
-			num, err := strconv.Atoi(strings.TrimSpace(part))
+		for _, part := range line.Split(",") {
+			num, err := part.Int()
 			if err != nil {
-				fmt.Fprintln(os.Stderr, "invalid integer value")
+				if err := parse.Tolerate(ctx, parseLog, err); err != nil {
+					return nil, nil, err
+				}
 				continue
 			}
 			pageNumbers = append(pageNumbers, num)
 		}
+		if len(pageNumbers) == 0 {
+			continue // every page was skipped, and there is no update left
+		}
+		if parse.Strict && len(pageNumbers)%2 == 0 {
+			return nil, nil, line.Errorf("%d pages, so there is no middle page", len(pageNumbers))
+		}
 		updates = append(updates, pageNumbers)
 	}
 
-	if err := scanner.Err(); err != nil {
-		fmt.Fprintln(os.Stderr, "reading standard input:", err)
-	}
-
-	return rules, updates
+	return rules, updates, nil
 }
 
-func isCorrectOrder(rules map[int][]int, update []int) bool {
+func isCorrectOrder(rules *graph.Graph[int], update []int) bool {
 	for i, pageNumber := range update {
-		rule := rules[pageNumber]
 		for j := 0; j < i; j++ {
-			if contains(rule, update[j]) {
+			if rules.HasEdge(pageNumber, update[j]) {
 				return false
 			}
 		}
@@ -71,60 +88,105 @@ func isCorrectOrder(rules map[int][]int, update []int) bool {
 	return true
 }
 
-func contains(slice []int, item int) bool {
-	for _, v := range slice {
-		if v == item {
-			return true
-		}
-	}
-	return false
-}
-
-func middlePageNumber(pages []int) int {
+func middlePageNumber(ctx context.Context, pages []int) int {
 	if len(pages)%2 == 0 {
-		fmt.Fprintln(os.Stderr, "array does not have an odd number of elements")
+		log.WarnContext(ctx, "no middle page", "pages", pages)
 		return -1
 	}
 	middleIndex := len(pages) / 2
 	return pages[middleIndex]
 }
 
-func reorderUntilCorrect(rules map[int][]int, update []int) []int {
-	for i, pageNumber := range update {
-		rule := rules[pageNumber]
-		for j := 0; j < i; j++ {
-			if contains(rule, update[j]) {
-				for k := j; k < len(update)-1; k++ {
-					tooEarly := update[k]
-					update[k] = update[k+1]
-					update[k+1] = tooEarly
-					if isCorrectOrder(rules, update) {
-						break
-					}
-				}
+// reorderUntilCorrect sorts the pages of an update by the rules that apply
+// to them. The rules as a whole go round in circles, but the ones between
+// the pages of any one update do not.
+func reorderUntilCorrect(rules *graph.Graph[int], update []int) ([]int, error) {
+	sorted, err := rules.Subgraph(update).TopoSort()
+	if err != nil {
+		return nil, fmt.Errorf("update %v: %w", update, err)
+	}
+	for _, page := range update {
+		if !rules.HasNode(page) { // no rule mentions it, so anywhere will do
+			sorted = append(sorted, page)
+		}
+	}
+	return sorted, nil
+}
+
+// insertionReorder sorts the pages of an update by checking every page
+// against every one after it, not only its neighbour, since the rules only
+// order some pairs: with just 2|1, the update 1,3,2 has no neighbours the
+// wrong way round. A later page that a rule puts first is moved in front,
+// and the place is checked again. It uses nothing but HasEdge, where
+// reorderUntilCorrect relies on Subgraph and TopoSort, so it is the check
+// on them. Rules that go round in circles between the pages are an error,
+// as they are there.
+func insertionReorder(rules *graph.Graph[int], update []int) ([]int, error) {
+	pages := append([]int(nil), update...)
+	moves := 0
+	for i := 0; i < len(pages); i++ {
+		for j := i + 1; j < len(pages); j++ {
+			if !rules.HasEdge(pages[j], pages[i]) {
+				continue
+			}
+			// each move puts an earlier page in place i, which without a
+			// cycle can happen at most len(pages) times
+			if moves++; moves > len(pages)*len(pages) {
+				return nil, fmt.Errorf("update %v: %w", update, graph.ErrCycle)
 			}
+			page := pages[j]
+			copy(pages[i+1:j+1], pages[i:j])
+			pages[i] = page
+			j = i
+		}
+	}
+	return pages, nil
+}
+
+// Reference answers part 2 by reordering with insertionReorder. Part 1 is only
+// a check of the rules, so there is nothing slower to check it against.
+func Reference(ctx context.Context, r io.Reader) (any, any, error) {
+	rules, updates, err := loadParseInput(ctx, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	sum := 0
+	for _, update := range updates {
+		if isCorrectOrder(rules, update) {
+			continue
 		}
+		pages, err := insertionReorder(rules, update)
+		if err != nil {
+			return nil, nil, err
+		}
+		sum += middlePageNumber(ctx, pages)
 	}
-	return update
+	return nil, sum, nil
 }
 
-func main() {
-	rules, updates := loadParseInput()
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	rules, updates, err := loadParseInput(ctx, r)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	middleCorrectSums := 0
 	middleIncorrectSums := 0
 
 	for _, update := range updates {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
 		if isCorrectOrder(rules, update) {
-			middleCorrectSums += middlePageNumber(update)
+			middleCorrectSums += middlePageNumber(ctx, update)
 		} else {
-			newOrder := reorderUntilCorrect(rules, update)
-			fmt.Println("Reordered to correct:", newOrder)
-			middleIncorrectSums += middlePageNumber(newOrder)
+			newOrder, err := reorderUntilCorrect(rules, update)
+			if err != nil {
+				return nil, nil, err
+			}
+			log.Debug("reordered", "update", update, "to", newOrder)
+			middleIncorrectSums += middlePageNumber(ctx, newOrder)
 		}
 	}
-	fmt.Print("Middle page number correct sums: ")
-	fmt.Println(middleCorrectSums)
-	fmt.Print("Middle page number incorrect sums: ")
-	fmt.Println(middleIncorrectSums)
+	return middleCorrectSums, middleIncorrectSums, nil
 }
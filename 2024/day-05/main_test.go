@@ -0,0 +1,141 @@
+package day05
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/gen/gentest"
+	"github.com/havill/AdventOfCode/graph"
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+type manual struct {
+	rules   []graph.Edge[int] // in order, so that the same rules compare equal
+	updates [][]int
+}
+
+func readManual(r io.Reader) (manual, error) {
+	rules, updates, err := loadParseInput(context.Background(), r)
+	if err != nil {
+		return manual{}, err
+	}
+	edges := rules.Edges()
+	slices.SortFunc(edges, func(a, b graph.Edge[int]) int {
+		return cmp.Or(cmp.Compare(a.From, b.From), cmp.Compare(a.To, b.To))
+	})
+	return manual{edges, updates}, nil
+}
+
+func writeManual(m manual) string {
+	var b strings.Builder
+	for _, rule := range m.rules {
+		fmt.Fprintf(&b, "%d|%d\n", rule.From, rule.To)
+	}
+	b.WriteString("\n")
+	for _, update := range m.updates {
+		pages := make([]string, len(update))
+		for i, page := range update {
+			pages[i] = strconv.Itoa(page)
+		}
+		b.WriteString(strings.Join(pages, ","))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func FuzzLoadParseInput(f *testing.F) {
+	parsetest.Seed(f,
+		"47|53\n97|13\n97|61\n75|29\n\n75,47,61,53,29\n97,61,53,29,13\n75,29,13\n",
+		"1|2\nx|3\n\n1,x,2\n\n\n3\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, readManual, writeManual)
+	})
+}
+
+func TestReorderUntilCorrect(t *testing.T) {
+	reordered := func(reorder func(*graph.Graph[int], []int) ([]int, error)) func(io.Reader) (int, error) {
+		return func(r io.Reader) (int, error) {
+			rules, updates, err := loadParseInput(context.Background(), r)
+			if err != nil {
+				return 0, err
+			}
+			sum := 0
+			for _, update := range updates {
+				if isCorrectOrder(rules, update) {
+					continue
+				}
+				pages, err := reorder(rules, update)
+				if err != nil {
+					return 0, err
+				}
+				if !isCorrectOrder(rules, pages) {
+					return 0, fmt.Errorf("%v was reordered to %v, which breaks a rule", update, pages)
+				}
+				sum += middlePageNumber(context.Background(), pages)
+			}
+			return sum, nil
+		}
+	}
+	gentest.Compare(t, Generate, 20, 100, reordered(reorderUntilCorrect), reordered(insertionReorder))
+}
+
+func TestInsertionReorder(t *testing.T) {
+	tests := []struct {
+		rules, update string
+		want          []int
+	}{
+		{"2|1", "1,3,2", []int{2, 1, 3}}, // no two neighbours break the rule
+		{"3|1\n2|1", "1,2,3", []int{2, 3, 1}},
+		{"4|1\n3|2", "1,2,3,4", []int{4, 1, 3, 2}},
+		{"1|2", "1,3,2", []int{1, 3, 2}},
+		{"1|2\n2|3\n3|1", "3,2,1", nil},
+	}
+	for _, tt := range tests {
+		rules, updates, err := loadParseInput(context.Background(), strings.NewReader(tt.rules+"\n\n"+tt.update+"\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, reorder := range []func(*graph.Graph[int], []int) ([]int, error){insertionReorder, reorderUntilCorrect} {
+			got, err := reorder(rules, updates[0])
+			if tt.want == nil {
+				if !errors.Is(err, graph.ErrCycle) {
+					t.Errorf("rules %q reordered %s to %v, %v, want a cycle", tt.rules, tt.update, got, err)
+				}
+				continue
+			}
+			if err != nil || !isCorrectOrder(rules, got) {
+				t.Errorf("rules %q reordered %s to %v, %v, which breaks a rule", tt.rules, tt.update, got, err)
+			}
+		}
+		if got, _ := insertionReorder(rules, updates[0]); tt.want != nil && !slices.Equal(got, tt.want) {
+			t.Errorf("rules %q: insertionReorder put %s as %v, want %v", tt.rules, tt.update, got, tt.want)
+		}
+	}
+}
+
+// TestReorderedInOrder is an update that the first way of reordering, which
+// moved a page one place at a time until the whole update was right, got
+// wrong: it stopped with 41 after 69 and 13 before 50, and 41 in the middle.
+func TestReorderedInOrder(t *testing.T) {
+	input := "69|97\n69|13\n69|50\n41|69\n41|97\n41|13\n41|50\n27|69\n27|41\n27|97\n27|13\n27|48\n27|50\n" +
+		"13|97\n48|69\n48|41\n48|97\n48|13\n48|50\n50|97\n50|13\n\n69,41,97,27,13,48,50\n"
+	rules, updates, err := loadParseInput(context.Background(), strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, err := reorderUntilCorrect(rules, updates[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isCorrectOrder(rules, pages) || middlePageNumber(context.Background(), pages) != 69 {
+		t.Errorf("reordered to %v, want 27,48,41,69,50,13,97", pages)
+	}
+}
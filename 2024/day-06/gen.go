@@ -0,0 +1,50 @@
+package day06
+
+import (
+	"math/rand/v2"
+
+	"github.com/havill/AdventOfCode/gen"
+	"github.com/havill/AdventOfCode/grid"
+)
+
+// Generate makes a size by size lab with obstructions scattered about and
+// the guard facing up from somewhere in it. A lab where the guard would walk
+// in circles or be boxed in is thrown away and another made, since part 1
+// follows the guard until they leave.
+func Generate(rng *rand.Rand, size int) []byte {
+	size = gen.Clamp(size, 1, 130)
+	for {
+		lab := grid.New[byte](size, size)
+		for p := range lab.All() {
+			lab.Set(p, gen.Pick(rng, "#.........."))
+		}
+		guard := grid.Point{X: rng.IntN(size), Y: rng.IntN(size)}
+		lab.Set(guard, '^')
+		if leaves(lab, guard) {
+			return gen.Grid(size, size, func(x, y int) byte { return lab.At(grid.Point{X: x, Y: y}) })
+		}
+	}
+}
+
+// leaves reports whether the guard starting at guard facing up walks out of
+// the lab.
+func leaves(lab *grid.Grid[byte], guard grid.Point) bool {
+	type state struct{ at, facing grid.Point }
+	seen := make(map[state]bool)
+	facing := grid.North
+	for lab.In(guard) {
+		turns := 0
+		for lab.At(guard.Add(facing)) == '#' {
+			if turns++; turns == 4 {
+				return false
+			}
+			facing = grid.Point{X: -facing.Y, Y: facing.X}
+		}
+		if seen[state{guard, facing}] {
+			return false
+		}
+		seen[state{guard, facing}] = true
+		guard = guard.Add(facing)
+	}
+	return true
+}
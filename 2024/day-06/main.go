@@ -1,12 +1,19 @@
-package main
+package day06
 
 import (
-	"bufio"
+	"context"
+	"errors"
 	"fmt"
-	"os"
+	"io"
 	"unicode"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/grid"
+	"github.com/havill/AdventOfCode/render"
 )
 
+var log = aoc.Logger("simulation")
+
 type Direction int
 
 const (
@@ -16,35 +23,15 @@ const (
 	West                        // 8 for west
 )
 
-func loadMap() [][]rune {
-	scanner := bufio.NewScanner(os.Stdin)
-	var lab [][]rune
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
-			break
-		}
-		row := []rune(line)
-		lab = append(lab, row)
-	}
-
-	if err := scanner.Err(); err != nil {
-		fmt.Fprintln(os.Stderr, "reading standard input:", err)
-	}
-
-	return lab
+func loadMap(r io.Reader) (*grid.Grid[rune], error) {
+	return grid.Read(r, grid.Expect(".#^v<>", grid.Runes))
 }
 
-func guardPosition(lab [][]rune) (int, int) {
-	for y, row := range lab {
-		for x, char := range row {
-			if char == '^' || char == 'v' || char == '>' || char == '<' {
-				return x, y
-			}
-		}
-	}
-	return -1, -1 // Return -1, -1 if no guard is found
+func guardPosition(lab *grid.Grid[rune]) (int, int) {
+	guard, _ := lab.Find(func(char rune) bool {
+		return char == '^' || char == 'v' || char == '>' || char == '<'
+	})
+	return guard.X, guard.Y // -1, -1 if no guard is found
 }
 
 func guardDirection(guard rune) (int, int) {
@@ -62,38 +49,27 @@ func guardDirection(guard rune) (int, int) {
 	}
 }
 
-func isObstacle(lab [][]rune, x, y int) bool {
-	if y >= 0 && y < len(lab) && x >= 0 && x < len(lab[y]) {
-		return lab[y][x] == '#' || lab[y][x] == 'O'
-	}
-	return false
+func isObstacle(lab *grid.Grid[rune], x, y int) bool {
+	char := lab.At(grid.Point{X: x, Y: y})
+	return char == '#' || char == 'O'
 }
 
-func isBlocked(lab [][]rune, x, y, dx, dy int) bool {
+func isBlocked(lab *grid.Grid[rune], x, y, dx, dy int) bool {
 	newX, newY := x+dx, y+dy
 	return isObstacle(lab, newX, newY)
 }
 
-func stillOnMap(x, y int, lab [][]rune) bool {
-	if y >= 0 && y < len(lab) && x >= 0 && x < len(lab[y]) {
-		return true
-	}
-	return false
+func stillOnMap(x, y int, lab *grid.Grid[rune]) bool {
+	return lab.In(grid.Point{X: x, Y: y})
 }
 
-func traveledRoute(lab [][]rune) int {
-	count := 0
-	for _, row := range lab {
-		for _, char := range row {
-			if char != '.' && char != '#' && char != 'O' {
-				count++
-			}
-		}
-	}
-	return count
+func traveledRoute(lab *grid.Grid[rune]) int {
+	return lab.Count(func(char rune) bool {
+		return char != '.' && char != '#' && char != 'O'
+	})
 }
 
-func moveGuard(x, y, dx, dy int, lab [][]rune) (int, int) {
+func moveGuard(x, y, dx, dy int, lab *grid.Grid[rune]) (int, int) {
 	// lab[y][x] = 'X'
 	x += dx
 	y += dy
@@ -211,62 +187,122 @@ func hexToDirection(hex rune) Direction {
 	}
 }
 
-func printLab(lab [][]rune) {
-	for _, row := range lab {
-		fmt.Println(string(row))
+func logLab(msg string, lab *grid.Grid[rune]) {
+	if aoc.Debugging() {
+		log.Debug(msg + "\n" + lab.String())
 	}
-	fmt.Println()
 }
 
-func main() {
-	lab := loadMap()
-	printLab(lab)
-
-	x, y := guardPosition(lab)
-	dx, dy := guardDirection(lab[y][x])
-
-	originX, originY := x, y
-	originDx, originDy := dx, dy
-	originLab := make([][]rune, len(lab))
-	for i := range lab {
-		originLab[i] = make([]rune, len(lab[i]))
-		copy(originLab[i], lab[i])
-	}
-
-	lab[y][x] = directionToHex(directionFromDelta(dx, dy))
-	fmt.Println("Initial direction:", lab[y][x])
+// patrol walks the guard from x, y, facing dx, dy, until they leave the
+// lab, marking each tile with the directions they crossed it in as a hex
+// digit. It stops with ctx's error if ctx is done first.
+func patrol(ctx context.Context, lab *grid.Grid[rune], x, y, dx, dy int) error {
+	start := grid.Point{X: x, Y: y}
+	guard := start
+	lab.Set(guard, directionToHex(directionFromDelta(dx, dy)))
+	log.Debug("guard", "at", guard, "facing", string(lab.At(guard)))
 
 	for stillOnMap(x, y, lab) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		for isBlocked(lab, x, y, dx, dy) {
 			dx, dy = clockwiseTurn(dx, dy)
 		}
-		if lab[y][x] == '.' {
-			lab[y][x] = '0'
+		guard = grid.Point{X: x, Y: y}
+		if lab.At(guard) == '.' {
+			lab.Set(guard, '0')
+		}
+		before := lab.At(guard)
+		lab.Set(guard, directionToHex(hexToDirection(lab.At(guard))|directionFromDelta(dx, dy)))
+		if aoc.Tracing() {
+			aoc.Trace(log, "step", "at", guard, "before", string(before), "after", string(lab.At(guard)))
 		}
-		fmt.Printf("Before direction: '%c'\n", lab[y][x])
-		lab[y][x] = directionToHex(hexToDirection(lab[y][x]) | directionFromDelta(dx, dy))
-		fmt.Printf("After direction: '%c'\n\n", lab[y][x])
 
 		x, y = moveGuard(x, y, dx, dy, lab)
+		if render.Screen != nil {
+			render.Screen.Draw(routeFrame(lab, start, grid.Point{X: x, Y: y}))
+		}
+	}
+	return nil
+}
+
+// Pictures are the ways a lab can be drawn once the guard has left it.
+var Pictures = []render.Picture{{Name: "route", Draw: drawRoute}}
+
+// drawRoute draws the guard's route through the lab, with where they
+// started picked out.
+func drawRoute(ctx context.Context, r io.Reader) (*render.Frame, error) {
+	lab, err := loadMap(r)
+	if err != nil {
+		return nil, err
+	}
+	x, y := guardPosition(lab)
+	if x == -1 && y == -1 {
+		return nil, errors.New("no guard found")
+	}
+	start := grid.Point{X: x, Y: y}
+	dx, dy := guardDirection(lab.At(start))
+	if err := patrol(ctx, lab, x, y, dx, dy); err != nil {
+		return nil, err
 	}
+	return routeFrame(lab, start, grid.Point{X: -1, Y: -1}), nil
+}
 
-	printLab(lab)
+// routeFrame draws the guard's route through the lab so far, with where
+// they started picked out in red and where they are now in green.
+func routeFrame(lab *grid.Grid[rune], start, guard grid.Point) *render.Frame {
+	return render.FromGrid(lab, func(p grid.Point, c rune) render.Cell {
+		switch {
+		case p == guard:
+			return render.Cell{Rune: '@', Fg: render.Black, Bg: render.Green}
+		case c == '#':
+			return render.Cell{Rune: '#', Fg: render.White, Bg: render.Black}
+		case c == '.':
+			return render.Cell{Rune: '.', Fg: render.Grey, Bg: render.Black}
+		case p == start:
+			return render.Cell{Rune: directionToBoxDrawing(hexToDirection(c)), Fg: render.Black, Bg: render.Red}
+		}
+		return render.Cell{Rune: directionToBoxDrawing(hexToDirection(c)), Fg: render.Yellow, Bg: render.Black}
+	})
+}
+
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	lab, err := loadMap(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	logLab("lab", lab)
+
+	x, y := guardPosition(lab)
+	if x == -1 && y == -1 {
+		return nil, nil, errors.New("no guard found")
+	}
+	guard := grid.Point{X: x, Y: y}
+	dx, dy := guardDirection(lab.At(guard))
+
+	originX, originY := x, y
+	originDx, originDy := dx, dy
+	originLab := lab.Clone()
+
+	if err := patrol(ctx, lab, x, y, dx, dy); err != nil {
+		return nil, nil, err
+	}
+	logLab("route", lab)
 
 	xCount := traveledRoute(lab)
-	fmt.Printf("Number of 'X' in the lab: %d\n", xCount)
 
 	// Iterate through the entire lab matrix, resetting x, y, dx, dy, and lab
-	for i := range lab {
-		for j := range lab[i] {
-			x, y = originX, originY
-			dx, dy = originDx, originDy
-			for k := range lab {
-				copy(lab[k], originLab[k])
-			}
-			if lab[i][j] == '.' {
-				lab[i][j] = 'O'
-			}
+	for p := range originLab.All() {
+		if err := ctx.Err(); err != nil {
+			return xCount, nil, err
+		}
+		x, y = originX, originY
+		dx, dy = originDx, originDy
+		lab = originLab.Clone()
+		if lab.At(p) == '.' {
+			lab.Set(p, 'O')
 		}
 	}
-
+	return xCount, nil, nil
 }
@@ -0,0 +1,45 @@
+package aoc
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AnswerPath is where the accepted answer to a part is kept. Like the inputs,
+// the answers are not committed.
+func (p Puzzle) AnswerPath(part int) string {
+	return filepath.Join(p.Dir(), fmt.Sprintf("answer-%d.txt", part))
+}
+
+// Answer returns the accepted answer to a part, or "" if none was recorded.
+func (p Puzzle) Answer(part int) (string, error) {
+	b, err := os.ReadFile(p.AnswerPath(part))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// SaveAnswer records the accepted answer to a part.
+func (p Puzzle) SaveAnswer(part int, answer string) error {
+	if err := os.MkdirAll(p.Dir(), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(p.AnswerPath(part), []byte(strings.TrimSpace(answer)+"\n"), 0o644)
+}
+
+// FormatAnswer turns whatever a Solver returned into the text that would be
+// typed into the puzzle page.
+func FormatAnswer(answer any) string {
+	if answer == nil {
+		return ""
+	}
+	return fmt.Sprint(answer)
+}
@@ -0,0 +1,65 @@
+// Package aoc holds the pieces shared by every puzzle so that any year and
+// day can be run the same way, whatever its original main looked like.
+package aoc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"path/filepath"
+
+	"github.com/havill/AdventOfCode/render"
+)
+
+// Solver reads a puzzle input and returns the answers to both parts. A part
+// that has not been solved is returned as nil. A long solver gives up soon
+// after ctx is done, returning ctx's error. Anything else a solver has to
+// say goes through a Logger or a Progress, never standard output, which is
+// kept for the answers.
+type Solver func(ctx context.Context, r io.Reader) (part1, part2 any, err error)
+
+// Generator makes up an input of the puzzle's shape, so that it can be run
+// without a real one. A source seeded the same way gives the same input.
+// Size is roughly how many lines or rows to make; a generator keeps it to
+// what its solver can get through quickly.
+type Generator func(rng *rand.Rand, size int) []byte
+
+// Make makes the input for seed, so that it can be made again from the seed
+// alone.
+func (g Generator) Make(seed uint64, size int) []byte {
+	return g(rand.New(rand.NewPCG(seed, 0)), max(size, 1))
+}
+
+type Puzzle struct {
+	Year      int
+	Day       int
+	Title     string
+	Solve     Solver
+	Generate  Generator // nil for a day without one
+	Reference Solver    // a slower solver to check Solve against, or nil
+	Pictures  []render.Picture
+}
+
+func (p Puzzle) String() string {
+	return fmt.Sprintf("%d day %d: %s", p.Year, p.Day, p.Title)
+}
+
+// Dir is where the puzzle's source lives, relative to the top of the repo.
+func (p Puzzle) Dir() string {
+	return filepath.Join(fmt.Sprint(p.Year), fmt.Sprintf("day-%02d", p.Day))
+}
+
+// InputPath is where the puzzle input is expected when none is given.
+func (p Puzzle) InputPath() string {
+	return filepath.Join(p.Dir(), "input.txt")
+}
+
+// Generated makes up an input for the puzzle from seed, or returns false if
+// the day has no generator.
+func (p Puzzle) Generated(seed uint64, size int) ([]byte, bool) {
+	if p.Generate == nil {
+		return nil, false
+	}
+	return p.Generate.Make(seed, size), true
+}
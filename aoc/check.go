@@ -0,0 +1,61 @@
+package aoc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"runtime/debug"
+)
+
+// PanicError is a panic in a solver, caught so that it can be reported like
+// any other error.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string { return fmt.Sprintf("panic: %v", e.Value) }
+
+// solveSafely runs solve on r, turning a panic into a PanicError.
+func solveSafely(ctx context.Context, solve Solver, r io.Reader) (part1, part2 any, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+	return solve(ctx, r)
+}
+
+// Check runs the solver on input, and the reference as well if there is
+// one, and returns an error if either panics or they give different answers
+// to a part the reference solves. An input that either of them rejects is
+// not a problem, so that shrinking one keeps it readable by both. The
+// reference goes first, being the one to trust about what makes sense.
+func (p Puzzle) Check(input []byte) error {
+	ctx := context.Background()
+	var want [2]any
+	if p.Reference != nil {
+		part1, part2, err := solveSafely(ctx, p.Reference, bytes.NewReader(input))
+		if _, panicked := err.(*PanicError); panicked {
+			return fmt.Errorf("reference: %w", err)
+		}
+		if err != nil {
+			return nil
+		}
+		want = [2]any{part1, part2}
+	}
+	part1, part2, err := solveSafely(ctx, p.Solve, bytes.NewReader(input))
+	if _, panicked := err.(*PanicError); panicked {
+		return err
+	}
+	if err != nil {
+		return nil
+	}
+	for part, got := range []any{part1, part2} {
+		if want[part] != nil && FormatAnswer(got) != FormatAnswer(want[part]) {
+			return fmt.Errorf("part %d is %s, but the reference says %s", part+1, FormatAnswer(got), FormatAnswer(want[part]))
+		}
+	}
+	return nil
+}
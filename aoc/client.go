@@ -0,0 +1,142 @@
+package aoc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBaseURL = "https://adventofcode.com"
+
+	// UserAgent tells the site's operators who is making the requests, as
+	// they ask of any automated tool.
+	UserAgent = "github.com/havill/AdventOfCode/cmd/aoc (+https://github.com/havill/AdventOfCode)"
+
+	// DefaultInterval is the least time between requests, to go easy on the
+	// site when fetching a whole year.
+	DefaultInterval = 3 * time.Second
+)
+
+// Client talks to the Advent of Code site, or anything that answers the same
+// way, as a logged in user.
+type Client struct {
+	BaseURL   string
+	Session   string // the value of the session cookie
+	UserAgent string
+	Interval  time.Duration
+	HTTP      *http.Client
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+func NewClient(baseURL, session string) *Client {
+	return &Client{
+		BaseURL:   strings.TrimRight(baseURL, "/"),
+		Session:   session,
+		UserAgent: UserAgent,
+		Interval:  DefaultInterval,
+		HTTP:      &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// LoadSession finds the session token: the AOC_SESSION environment variable
+// if it is set, or else the first line of file. An empty file name means
+// AOC_SESSION_FILE, or failing that aoc/session in the user's config
+// directory.
+func LoadSession(file string) (string, error) {
+	if token := strings.TrimSpace(os.Getenv("AOC_SESSION")); token != "" {
+		return token, nil
+	}
+	if file == "" {
+		file = os.Getenv("AOC_SESSION_FILE")
+	}
+	if file == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		file = filepath.Join(dir, "aoc", "session")
+	}
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("no session token: set AOC_SESSION or put it in %s", file)
+	} else if err != nil {
+		return "", err
+	}
+	token, _, _ := strings.Cut(string(data), "\n")
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", fmt.Errorf("no session token in %s", file)
+	}
+	return token, nil
+}
+
+// wait holds each request back until Interval has passed since the last
+// one was answered, and holds the rest back until done is called once this
+// one has been. Timing from the answer rather than the request keeps a slow
+// answer from eating into the gap.
+func (c *Client) wait() (done func()) {
+	c.mu.Lock()
+	if wait := c.Interval - time.Since(c.last); !c.last.IsZero() && wait > 0 {
+		time.Sleep(wait)
+	}
+	return func() {
+		c.last = time.Now()
+		c.mu.Unlock()
+	}
+}
+
+func (c *Client) do(method, path string, body io.Reader, contentType string) ([]byte, error) {
+	req, err := http.NewRequest(method, c.BaseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.UserAgent)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.AddCookie(&http.Cookie{Name: "session", Value: c.Session})
+
+	done := c.wait()
+	defer done()
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: %s%s", method, req.URL, resp.Status, firstLine(data))
+	}
+	return data, nil
+}
+
+// firstLine is the start of an error page, which usually says what went
+// wrong, such as the puzzle not being unlocked yet.
+func firstLine(data []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	if scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			return ": " + line
+		}
+	}
+	return ""
+}
+
+// Input downloads the puzzle input for the logged in user.
+func (c *Client) Input(year, day int) ([]byte, error) {
+	return c.do(http.MethodGet, fmt.Sprintf("/%d/day/%d/input", year, day), nil, "")
+}
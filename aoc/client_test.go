@@ -0,0 +1,110 @@
+package aoc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInput(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/2023/day/7/input" {
+			t.Errorf("got %s %s, want GET /2023/day/7/input", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("User-Agent"); got != UserAgent {
+			t.Errorf("User-Agent = %q, want %q", got, UserAgent)
+		}
+		cookie, err := r.Cookie("session")
+		if err != nil || cookie.Value != "secret" {
+			t.Errorf("session cookie = %v, %v, want secret", cookie, err)
+		}
+		w.Write([]byte("32T3K 765\n"))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL+"/", "secret")
+	data, err := c.Input(2023, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "32T3K 765\n" {
+		t.Errorf("Input = %q", data)
+	}
+}
+
+func TestInputError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Please don't repeatedly request this endpoint before it unlocks!", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	_, err := NewClient(srv.URL, "secret").Input(2030, 1)
+	if err == nil {
+		t.Fatal("Input succeeded, want an error")
+	}
+	for _, want := range []string{"404", "before it unlocks"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestInterval(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+		w.Write([]byte("x"))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "secret")
+	c.Interval = 50 * time.Millisecond
+	for day := 1; day <= 3; day++ {
+		if _, err := c.Input(2023, day); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for i := 1; i < len(times); i++ {
+		if gap := times[i].Sub(times[i-1]); gap < c.Interval {
+			t.Errorf("request %d came %v after the one before, want at least %v", i+1, gap, c.Interval)
+		}
+	}
+}
+
+func TestLoadSession(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "session")
+	if err := os.WriteFile(file, []byte("  from-file \n# comment\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("AOC_SESSION", "")
+	if got, err := LoadSession(file); err != nil || got != "from-file" {
+		t.Errorf("LoadSession(file) = %q, %v, want from-file", got, err)
+	}
+
+	t.Setenv("AOC_SESSION_FILE", file)
+	if got, err := LoadSession(""); err != nil || got != "from-file" {
+		t.Errorf("LoadSession with AOC_SESSION_FILE = %q, %v, want from-file", got, err)
+	}
+
+	t.Setenv("AOC_SESSION", "from-env")
+	if got, err := LoadSession(file); err != nil || got != "from-env" {
+		t.Errorf("LoadSession with AOC_SESSION = %q, %v, want from-env", got, err)
+	}
+
+	t.Setenv("AOC_SESSION", "")
+	if _, err := LoadSession(filepath.Join(dir, "missing")); err == nil {
+		t.Error("LoadSession of a missing file succeeded")
+	}
+}
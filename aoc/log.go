@@ -0,0 +1,151 @@
+package aoc
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// LevelTrace is for records made at every step of a solver, which are far
+// too many to want unless something is being tracked down.
+const LevelTrace = slog.LevelDebug - 4
+
+// Quiet, the level until SetLogging says otherwise, lets warnings through
+// and nothing else.
+const Quiet = slog.LevelWarn
+
+var (
+	logLevel slog.LevelVar
+	logOut   = &logWriter{w: os.Stderr}
+	handler  = pictureHandler{Handler: slog.NewTextHandler(logOut, &slog.HandlerOptions{
+		Level: &logLevel,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			switch {
+			case len(groups) > 0:
+			case a.Key == slog.TimeKey:
+				return slog.Attr{} // the order is what matters, not the time
+			case a.Key == slog.LevelKey && a.Value.Any() == LevelTrace:
+				a.Value = slog.StringValue("TRACE")
+			}
+			return a
+		},
+	})}
+)
+
+func init() { logLevel.Set(Quiet) }
+
+// logWriter lets SetLogging change where the records go after the loggers
+// have been made.
+type logWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *logWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// pictureHandler writes whatever follows the first line of a message as it
+// is, so that a grid logged as a message can still be read. It also passes
+// each record to the handler of the run it was made in, if there is one.
+type pictureHandler struct {
+	slog.Handler
+	attrs []slog.Attr // for the run's handler, which was made without them
+}
+
+type runHandlerKey struct{}
+
+// WithLogHandler returns a copy of ctx under which the records of every
+// Logger go to h as well as to where SetLogging sends them, so that a run's
+// warnings can be told apart from those of others running at the same
+// time. Only records made with the context, such as by log.WarnContext or
+// parse.Tolerate, are seen.
+func WithLogHandler(ctx context.Context, h slog.Handler) context.Context {
+	return context.WithValue(ctx, runHandlerKey{}, h)
+}
+
+func runHandler(ctx context.Context, level slog.Level) (slog.Handler, bool) {
+	h, ok := ctx.Value(runHandlerKey{}).(slog.Handler)
+	return h, ok && h.Enabled(ctx, level)
+}
+
+func (h pictureHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.Handler.Enabled(ctx, level) {
+		return true
+	}
+	_, ok := runHandler(ctx, level)
+	return ok
+}
+
+func (h pictureHandler) Handle(ctx context.Context, r slog.Record) error {
+	if run, ok := runHandler(ctx, r.Level); ok {
+		if err := run.WithAttrs(h.attrs).Handle(ctx, r.Clone()); err != nil {
+			return err
+		}
+	}
+	if !h.Handler.Enabled(ctx, r.Level) {
+		return nil
+	}
+	msg, picture, ok := strings.Cut(r.Message, "\n")
+	if !ok {
+		return h.Handler.Handle(ctx, r)
+	}
+	r.Message = msg
+	if err := h.Handler.Handle(ctx, r); err != nil {
+		return err
+	}
+	if !strings.HasSuffix(picture, "\n") {
+		picture += "\n"
+	}
+	_, err := io.WriteString(logOut, picture)
+	return err
+}
+
+func (h pictureHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return pictureHandler{h.Handler.WithAttrs(attrs), append(slices.Clip(h.attrs), attrs...)}
+}
+
+func (h pictureHandler) WithGroup(name string) slog.Handler {
+	return pictureHandler{h.Handler.WithGroup(name), h.attrs}
+}
+
+// Logger returns the logger for one subsystem of a solver, such as "parser",
+// "simulation" or "search". Loggers are usually made once, at package level.
+func Logger(subsystem string) *slog.Logger {
+	return slog.New(handler).With("sys", subsystem)
+}
+
+// SetLogging sends the records at level and above to w.
+func SetLogging(w io.Writer, level slog.Level) {
+	logOut.mu.Lock()
+	logOut.w = w
+	logOut.mu.Unlock()
+	logLevel.Set(level)
+}
+
+// Logging returns where records go and the level they are kept from, as
+// SetLogging last set them, so that they can be put back after a change.
+func Logging() (io.Writer, slog.Level) {
+	logOut.mu.Lock()
+	defer logOut.mu.Unlock()
+	return logOut.w, logLevel.Level()
+}
+
+// Debugging reports whether debug records are being kept. Like Tracing it is
+// for loops, where even the arguments to a discarded record would cost
+// something.
+func Debugging() bool { return logLevel.Level() <= slog.LevelDebug }
+
+// Tracing reports whether trace records are being kept.
+func Tracing() bool { return logLevel.Level() <= LevelTrace }
+
+// Trace makes a record at LevelTrace.
+func Trace(l *slog.Logger, msg string, args ...any) {
+	l.Log(context.Background(), LevelTrace, msg, args...)
+}
@@ -0,0 +1,77 @@
+package aoc
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	t.Cleanup(func() { SetLogging(io.Discard, Quiet) })
+	log := Logger("search")
+
+	SetLogging(&buf, slog.LevelDebug)
+	log.Debug("visited", "node", "AAA")
+	Trace(log, "edge", "from", "AAA")
+	if got, want := buf.String(), "level=DEBUG msg=visited sys=search node=AAA\n"; got != want {
+		t.Errorf("at debug level logged %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	SetLogging(&buf, LevelTrace)
+	Trace(log, "edge", "from", "AAA")
+	log.Debug("grid\n.#.\n#.#")
+	want := "level=TRACE msg=edge sys=search from=AAA\nlevel=DEBUG msg=grid sys=search\n.#.\n#.#\n"
+	if got := buf.String(); got != want {
+		t.Errorf("at trace level logged %q, want %q", got, want)
+	}
+
+	if w, level := Logging(); w != &buf || level != LevelTrace {
+		t.Errorf("Logging() = %v, %v, want what SetLogging set", w, level)
+	}
+}
+
+func TestQuietLoggingIsFree(t *testing.T) {
+	SetLogging(io.Discard, Quiet)
+	log := Logger("simulation")
+	allocs := testing.AllocsPerRun(100, func() {
+		if Tracing() {
+			Trace(log, "step", "at", strings.Repeat("x", 10))
+		}
+		log.Debug("step")
+	})
+	if allocs != 0 {
+		t.Errorf("quiet logging made %v allocations a record, want 0", allocs)
+	}
+}
+
+func TestWithLogHandler(t *testing.T) {
+	var global, run bytes.Buffer
+	t.Cleanup(func() { SetLogging(io.Discard, Quiet) })
+	SetLogging(&global, slog.LevelError)
+	log := Logger("parser")
+	ctx := WithLogHandler(context.Background(), slog.NewTextHandler(&run, &slog.HandlerOptions{
+		Level: slog.LevelWarn,
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	log.WarnContext(ctx, "skipped", "line", 2)
+	log.Warn("not this run's")
+	log.DebugContext(ctx, "too quiet for either")
+	log.ErrorContext(ctx, "both")
+	if got, want := run.String(), "level=WARN msg=skipped sys=parser line=2\nlevel=ERROR msg=both sys=parser\n"; got != want {
+		t.Errorf("the run's handler got %q, want %q", got, want)
+	}
+	if got, want := global.String(), "level=ERROR msg=both sys=parser\n"; got != want {
+		t.Errorf("the global handler got %q, want %q", got, want)
+	}
+}
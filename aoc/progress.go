@@ -0,0 +1,111 @@
+package aoc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// progressEvery is how often a Progress reports, and how long a loop has to
+// run before it reports at all.
+const progressEvery = time.Second
+
+var progressOut struct {
+	on atomic.Bool // w is not nil, so that Add needn't lock to find out
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// SetProgress sends progress reports to w, or turns them off if w is nil,
+// as they are until it is called.
+func SetProgress(w io.Writer) {
+	progressOut.mu.Lock()
+	progressOut.w = w
+	progressOut.on.Store(w != nil)
+	progressOut.mu.Unlock()
+}
+
+// Progress reports how far a long loop has got, how fast it is going and
+// how long it has left. It costs next to nothing when reports are off.
+type Progress struct {
+	name, what   string
+	total, done  int
+	start, shown time.Time
+}
+
+// NewProgress starts the reports on a loop over total things, what saying
+// what they are, such as "records". A total of 0 means it isn't known. ctx
+// is the solver's, which says which puzzle it is.
+func NewProgress(ctx context.Context, what string, total int) *Progress {
+	p := &Progress{what: what, total: total, start: time.Now()}
+	p.shown = p.start
+	if s, ok := timing(ctx); ok {
+		p.name = s.name
+	}
+	return p
+}
+
+// Add counts n more things done, and reports if it is time to. It only
+// locks to write a report, so that it can be called on every turn of a
+// tight loop.
+func (p *Progress) Add(n int) {
+	p.done += n
+	if !progressOut.on.Load() {
+		return
+	}
+	now := time.Now()
+	if now.Sub(p.shown) < progressEvery {
+		return
+	}
+	progressOut.mu.Lock()
+	defer progressOut.mu.Unlock()
+	if progressOut.w == nil {
+		return
+	}
+	p.shown = now
+	fmt.Fprintln(progressOut.w, p.report(now))
+}
+
+// Done reports how long the loop took, if it took long enough to have been
+// reported on.
+func (p *Progress) Done() {
+	progressOut.mu.Lock()
+	defer progressOut.mu.Unlock()
+	if progressOut.w == nil || p.shown == p.start {
+		return
+	}
+	fmt.Fprintf(progressOut.w, "%s%d %s in %v\n", p.prefix(), p.done, p.what, time.Since(p.start).Round(time.Millisecond))
+}
+
+func (p *Progress) prefix() string {
+	if p.name == "" {
+		return ""
+	}
+	return p.name + ": "
+}
+
+// report says how far the loop has got by now.
+func (p *Progress) report(now time.Time) string {
+	elapsed := now.Sub(p.start)
+	rate := float64(p.done) / elapsed.Seconds()
+	if p.total == 0 {
+		return fmt.Sprintf("%s%d %s, %s a second", p.prefix(), p.done, p.what, perSecond(rate))
+	}
+	left := "?"
+	if rate > 0 {
+		left = time.Duration(float64(p.total-p.done) / rate * float64(time.Second)).Round(time.Second).String()
+	}
+	return fmt.Sprintf("%s%d of %d %s (%.0f%%), %s a second, about %s left",
+		p.prefix(), p.done, p.total, p.what, 100*float64(p.done)/float64(p.total), perSecond(rate), left)
+}
+
+// perSecond writes a rate as a whole number unless it is a small one.
+func perSecond(rate float64) string {
+	if rate < 10 {
+		return fmt.Sprintf("%.2g", rate)
+	}
+	return fmt.Sprintf("%.0f", rate)
+}
@@ -0,0 +1,111 @@
+package aoc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressReport(t *testing.T) {
+	start := time.Date(2023, 12, 12, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		progress Progress
+		after    time.Duration
+		want     string
+	}{
+		{Progress{name: "2023 day 12", what: "records", total: 1000, done: 250}, 5 * time.Second,
+			"2023 day 12: 250 of 1000 records (25%), 50 a second, about 15s left"},
+		{Progress{what: "steps", done: 3}, 2 * time.Second, "3 steps, 1.5 a second"},
+		{Progress{what: "seeds", total: 10}, time.Second, "0 of 10 seeds (0%), 0 a second, about ? left"},
+	}
+	for _, tt := range tests {
+		tt.progress.start = start
+		if got := tt.progress.report(start.Add(tt.after)); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestSetProgress(t *testing.T) {
+	var buf bytes.Buffer
+	t.Cleanup(func() { SetProgress(nil) })
+
+	p := NewProgress(context.Background(), "records", 2)
+	p.start = p.start.Add(-time.Minute)
+	p.shown = p.start
+	p.Add(1)
+	SetProgress(&buf)
+	p.Add(1)
+	p.Done()
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "2 of 2 records") || !strings.HasPrefix(lines[1], "2 records in") {
+		t.Errorf("reported %q, want one report after SetProgress and then the end", buf.String())
+	}
+}
+
+// TestProgressAdd checks that Add doesn't take the lock between reports,
+// which would deadlock here as the lock is already held.
+func TestProgressAdd(t *testing.T) {
+	SetProgress(io.Discard)
+	t.Cleanup(func() { SetProgress(nil) })
+	p := NewProgress(context.Background(), "records", 0)
+	progressOut.mu.Lock()
+	defer progressOut.mu.Unlock()
+	for range 1000 {
+		p.Add(1)
+	}
+	if p.done != 1000 {
+		t.Errorf("counted %d, want 1000", p.done)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	stopped := false
+	p := Puzzle{Year: 2023, Day: 23, Solve: func(ctx context.Context, _ io.Reader) (any, any, error) {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		stopped = true
+		return nil, nil, ctx.Err()
+	}}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, _, err := p.Run(ctx, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want the deadline to be exceeded", err)
+	}
+	if !stopped {
+		t.Errorf("Run returned before the solver stopped")
+	}
+
+	p.Solve = func(context.Context, io.Reader) (any, any, error) { panic("no way through") }
+	var pe *PanicError
+	if _, _, _, err := p.Run(context.Background(), nil); !errors.As(err, &pe) {
+		t.Errorf("got %v, want a panic", err)
+	}
+}
+
+// TestRunMarks checks that a solver's marks are timed through its context,
+// whatever it does with the reader.
+func TestRunMarks(t *testing.T) {
+	p := Puzzle{Year: 2023, Day: 1, Solve: func(ctx context.Context, r io.Reader) (any, any, error) {
+		if _, err := io.ReadAll(bufio.NewReader(r)); err != nil {
+			return nil, nil, err
+		}
+		time.Sleep(5 * time.Millisecond)
+		Parsed(ctx)
+		time.Sleep(5 * time.Millisecond)
+		Part1Done(ctx)
+		return 1, 2, nil
+	}}
+	_, _, timing, err := p.Run(context.Background(), []byte("1\n2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timing.Parse < 5*time.Millisecond || timing.Part1 < 5*time.Millisecond || !timing.Split() {
+		t.Errorf("timed %+v, want parsing and part 1 to take at least 5ms each", timing)
+	}
+}
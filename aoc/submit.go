@@ -0,0 +1,110 @@
+package aoc
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Outcome int
+
+const (
+	Unrecognized  Outcome = iota // the page said something unexpected
+	Correct                      // the answer was accepted
+	TooHigh                      // wrong, and the right answer is lower
+	TooLow                       // wrong, and the right answer is higher
+	Wrong                        // wrong, with no hint which way
+	Wait                         // too soon after the last answer, so not checked
+	AlreadySolved                // the part was solved before, or is not unlocked
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Correct:
+		return "correct"
+	case TooHigh:
+		return "too high"
+	case TooLow:
+		return "too low"
+	case Wrong:
+		return "wrong"
+	case Wait:
+		return "wait"
+	case AlreadySolved:
+		return "already solved"
+	}
+	return "unrecognized"
+}
+
+// Verdict is what the site made of an answer.
+type Verdict struct {
+	Outcome Outcome
+	Wait    time.Duration // how long until another answer can be given
+	Message string        // the text of the page's reply
+}
+
+var (
+	articleRE = regexp.MustCompile(`(?s)<article[^>]*>(.*?)</article>`)
+	tagRE     = regexp.MustCompile(`<[^>]*>`)
+	spaceRE   = regexp.MustCompile(`\s+`)
+	leftRE    = regexp.MustCompile(`You have (?:(\d+)m )?(\d+)s left to wait`)
+	minutesRE = regexp.MustCompile(`wait (one|\d+) minutes? before trying again`)
+)
+
+// ParseVerdict reads the page the site replies with after an answer.
+func ParseVerdict(page []byte) Verdict {
+	text := string(page)
+	if m := articleRE.FindStringSubmatch(text); m != nil {
+		text = m[1]
+	}
+	text = html.UnescapeString(tagRE.ReplaceAllString(text, " "))
+	text = strings.TrimSpace(spaceRE.ReplaceAllString(text, " "))
+	v := Verdict{Message: text}
+
+	switch {
+	case strings.Contains(text, "That's the right answer"):
+		v.Outcome = Correct
+	case strings.Contains(text, "You gave an answer too recently"):
+		v.Outcome = Wait
+		if m := leftRE.FindStringSubmatch(text); m != nil {
+			minutes, _ := strconv.Atoi(m[1])
+			seconds, _ := strconv.Atoi(m[2])
+			v.Wait = time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+		}
+	case strings.Contains(text, "That's not the right answer"):
+		switch {
+		case strings.Contains(text, "your answer is too high"):
+			v.Outcome = TooHigh
+		case strings.Contains(text, "your answer is too low"):
+			v.Outcome = TooLow
+		default:
+			v.Outcome = Wrong
+		}
+		if m := minutesRE.FindStringSubmatch(text); m != nil {
+			minutes, err := strconv.Atoi(m[1])
+			if err != nil {
+				minutes = 1 // "one minute"
+			}
+			v.Wait = time.Duration(minutes) * time.Minute
+		}
+	case strings.Contains(text, "You don't seem to be solving the right level"):
+		v.Outcome = AlreadySolved
+	}
+	return v
+}
+
+// Submit gives the answer to a part of a puzzle.
+func (c *Client) Submit(year, day, part int, answer string) (Verdict, error) {
+	form := url.Values{"level": {strconv.Itoa(part)}, "answer": {answer}}
+	page, err := c.do(http.MethodPost, fmt.Sprintf("/%d/day/%d/answer", year, day),
+		strings.NewReader(form.Encode()), "application/x-www-form-urlencoded")
+	if err != nil {
+		return Verdict{}, err
+	}
+	return ParseVerdict(page), nil
+}
@@ -0,0 +1,65 @@
+package aoc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// page wraps a reply the way the site does.
+func page(article string) []byte {
+	return []byte(`<!DOCTYPE html><html><head><title>Day 1 - Advent of Code 2023</title></head><body>
+<header><h1><a href="/">Advent of Code</a></h1></header>
+<main>
+<article><p>` + article + `</p></article>
+</main></body></html>`)
+}
+
+func TestParseVerdict(t *testing.T) {
+	tests := []struct {
+		name    string
+		article string
+		outcome Outcome
+		wait    time.Duration
+	}{
+		{"correct", `That's the right answer!  You are <span class="day-success">one gold star</span> closer to restoring snow operations. <a href="/2023/day/1#part2">[Continue to Part Two]</a>`, Correct, 0},
+		{"too high", `That's not the right answer; your answer is too high.  If you're stuck, make sure you're using the full input data; there are also some general tips on the <a href="/2023/about">about page</a>, or you can ask for hints on the <a href="https://www.reddit.com/r/adventofcode/" target="_blank">subreddit</a>.  Please wait one minute before trying again. <a href="/2023/day/1">[Return to Day 1]</a>`, TooHigh, time.Minute},
+		{"too low", `That's not the right answer; your answer is too low.  Please wait 5 minutes before trying again. <a href="/2023/day/1">[Return to Day 1]</a>`, TooLow, 5 * time.Minute},
+		{"wrong", `That's not the right answer.  If you're stuck, make sure you're using the full input data. <a href="/2023/day/1">[Return to Day 1]</a>`, Wrong, 0},
+		{"wait seconds", `You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 38s left to wait. <a href="/2023/day/1">[Return to Day 1]</a>`, Wait, 38 * time.Second},
+		{"wait minutes", `You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 4m 2s left to wait. <a href="/2023/day/1">[Return to Day 1]</a>`, Wait, 4*time.Minute + 2*time.Second},
+		{"already solved", `You don't seem to be solving the right level.  Did you already complete it? <a href="/2023/day/1">[Return to Day 1]</a>`, AlreadySolved, 0},
+		{"unrecognized", `Something else entirely.`, Unrecognized, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := ParseVerdict(page(tt.article))
+			if v.Outcome != tt.outcome || v.Wait != tt.wait {
+				t.Errorf("got %v, wait %v; want %v, wait %v\n%s", v.Outcome, v.Wait, tt.outcome, tt.wait, v.Message)
+			}
+		})
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/2023/day/1/answer" {
+			t.Errorf("got %s %s, want POST /2023/day/1/answer", r.Method, r.URL.Path)
+		}
+		if level, answer := r.FormValue("level"), r.FormValue("answer"); level != "2" || answer != "281" {
+			t.Errorf("got level=%q answer=%q, want level=2 answer=281", level, answer)
+		}
+		w.Write(page(`That's the right answer!`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "secret")
+	v, err := c.Submit(2023, 1, 2, "281")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Outcome != Correct {
+		t.Errorf("Submit = %v, want correct", v.Outcome)
+	}
+}
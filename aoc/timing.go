@@ -0,0 +1,96 @@
+package aoc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Timing is how long each phase of one run of a solver took.
+type Timing struct {
+	Parse time.Duration
+	Solve time.Duration // both parts
+	Part1 time.Duration // Part1 and Part2 are 0 unless the solver marked where part 1 ended
+	Part2 time.Duration
+}
+
+func (t Timing) Total() time.Duration { return t.Parse + t.Solve }
+
+// Split reports whether the time was split between the two parts.
+func (t Timing) Split() bool { return t.Part1 != 0 || t.Part2 != 0 }
+
+// stopwatch times a run. It reads the input, so that parsing can be taken
+// to end at its last byte, and is kept in the run's context, so that the
+// solver can say how far it has got however it wraps the reader.
+type stopwatch struct {
+	r             io.Reader
+	name          string // the puzzle, for progress reports
+	parsed, part1 time.Time
+}
+
+type stopwatchKey struct{}
+
+// timing returns the stopwatch of the run that ctx belongs to, if any.
+func timing(ctx context.Context) (*stopwatch, bool) {
+	s, ok := ctx.Value(stopwatchKey{}).(*stopwatch)
+	return s, ok
+}
+
+func (s *stopwatch) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	if err == io.EOF && s.parsed.IsZero() {
+		s.parsed = time.Now()
+	}
+	return n, err
+}
+
+// Parsed marks the end of parsing when the run ctx belongs to is being
+// timed, and does nothing otherwise. Without it, parsing is taken to end
+// when the last of the input has been read.
+func Parsed(ctx context.Context) {
+	if s, ok := timing(ctx); ok {
+		s.parsed = time.Now()
+	}
+}
+
+// Part1Done marks the end of part 1 when the run ctx belongs to is being
+// timed, and does nothing otherwise. Solvers that work on both parts at
+// once do not call it.
+func Part1Done(ctx context.Context) {
+	if s, ok := timing(ctx); ok {
+		s.part1 = time.Now()
+	}
+}
+
+// Time runs the solver once on input and times its phases.
+func (p Puzzle) Time(input []byte) (Timing, error) {
+	_, _, t, err := p.Run(context.Background(), input)
+	return t, err
+}
+
+// Run solves input and times its phases. The solver is expected to stop
+// soon after ctx is done; Run waits for it either way, so nothing is left
+// running once it returns. If the solver
+// fails after ctx is done, ctx's error is returned in place of its own. A
+// panic in the solver is returned as a PanicError.
+func (p Puzzle) Run(ctx context.Context, input []byte) (part1, part2 any, t Timing, err error) {
+	s := &stopwatch{r: bytes.NewReader(input), name: fmt.Sprintf("%d day %d", p.Year, p.Day)}
+	start := time.Now()
+	part1, part2, err = solveSafely(context.WithValue(ctx, stopwatchKey{}, s), p.Solve, s)
+	if err != nil && ctx.Err() != nil {
+		return nil, nil, Timing{Solve: time.Since(start)}, ctx.Err()
+	}
+	end := time.Now()
+
+	if s.parsed.IsZero() {
+		s.parsed = end // it never read to the end
+	}
+	t = Timing{Parse: s.parsed.Sub(start), Solve: end.Sub(s.parsed)}
+	if !s.part1.IsZero() && !s.part1.Before(s.parsed) {
+		t.Part1 = s.part1.Sub(s.parsed)
+		t.Part2 = end.Sub(s.part1)
+	}
+	return part1, part2, t, err
+}
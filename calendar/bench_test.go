@@ -0,0 +1,49 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/havill/AdventOfCode/aoc"
+)
+
+// BenchmarkSolvers times every day on its input. The inputs are private, so
+// a day without one is timed on an input from its generator instead, and
+// only skipped if it has none; 'aoc fetch' gets the real ones.
+func BenchmarkSolvers(b *testing.B) {
+	for _, p := range All(0) {
+		b.Run(fmt.Sprintf("%d/day-%02d", p.Year, p.Day), func(b *testing.B) {
+			input, err := os.ReadFile(filepath.Join("..", p.InputPath()))
+			if errors.Is(err, fs.ErrNotExist) {
+				var ok bool
+				if input, ok = p.Generated(1, 100); !ok {
+					b.Skip("no input")
+				}
+			} else if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			var sum aoc.Timing
+			for i := 0; i < b.N; i++ {
+				t, err := p.Time(input)
+				if err != nil {
+					b.Fatal(err)
+				}
+				sum.Parse += t.Parse
+				sum.Part1 += t.Part1
+				sum.Part2 += t.Part2
+			}
+			perOp := func(d time.Duration) float64 { return float64(d.Nanoseconds()) / float64(b.N) }
+			b.ReportMetric(perOp(sum.Parse), "parse-ns/op")
+			if sum.Split() {
+				b.ReportMetric(perOp(sum.Part1), "part1-ns/op")
+				b.ReportMetric(perOp(sum.Part2), "part2-ns/op")
+			}
+		})
+	}
+}
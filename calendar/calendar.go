@@ -0,0 +1,99 @@
+// Package calendar lists every puzzle that has a solution in this repo.
+package calendar
+
+import (
+	"sort"
+
+	"github.com/havill/AdventOfCode/aoc"
+
+	y2023d01 "github.com/havill/AdventOfCode/2023/day-01"
+	y2023d02 "github.com/havill/AdventOfCode/2023/day-02"
+	y2023d03 "github.com/havill/AdventOfCode/2023/day-03"
+	y2023d04 "github.com/havill/AdventOfCode/2023/day-04"
+	y2023d05 "github.com/havill/AdventOfCode/2023/day-05"
+	y2023d06 "github.com/havill/AdventOfCode/2023/day-06"
+	y2023d07 "github.com/havill/AdventOfCode/2023/day-07"
+	y2023d08 "github.com/havill/AdventOfCode/2023/day-08"
+	y2023d09 "github.com/havill/AdventOfCode/2023/day-09"
+	y2023d10 "github.com/havill/AdventOfCode/2023/day-10"
+	y2023d11 "github.com/havill/AdventOfCode/2023/day-11"
+	y2023d12 "github.com/havill/AdventOfCode/2023/day-12"
+	y2023d13 "github.com/havill/AdventOfCode/2023/day-13"
+	y2023d14 "github.com/havill/AdventOfCode/2023/day-14"
+	y2023d15 "github.com/havill/AdventOfCode/2023/day-15"
+	y2023d16 "github.com/havill/AdventOfCode/2023/day-16"
+	y2023d17 "github.com/havill/AdventOfCode/2023/day-17"
+	y2023d18 "github.com/havill/AdventOfCode/2023/day-18"
+	y2023d19 "github.com/havill/AdventOfCode/2023/day-19"
+	y2023d21 "github.com/havill/AdventOfCode/2023/day-21"
+	y2023d23 "github.com/havill/AdventOfCode/2023/day-23"
+	y2023d24 "github.com/havill/AdventOfCode/2023/day-24"
+	y2023d25 "github.com/havill/AdventOfCode/2023/day-25"
+
+	y2024d01 "github.com/havill/AdventOfCode/2024/day-01"
+	y2024d02 "github.com/havill/AdventOfCode/2024/day-02"
+	y2024d03 "github.com/havill/AdventOfCode/2024/day-03"
+	y2024d04 "github.com/havill/AdventOfCode/2024/day-04"
+	y2024d05 "github.com/havill/AdventOfCode/2024/day-05"
+	y2024d06 "github.com/havill/AdventOfCode/2024/day-06"
+)
+
+var puzzles = []aoc.Puzzle{
+	{Year: 2023, Day: 1, Title: "Trebuchet?!", Solve: y2023d01.Solve, Generate: y2023d01.Generate},
+	{Year: 2023, Day: 2, Title: "Cube Conundrum", Solve: y2023d02.Solve, Generate: y2023d02.Generate},
+	{Year: 2023, Day: 3, Title: "Gear Ratios", Solve: y2023d03.Solve, Generate: y2023d03.Generate},
+	{Year: 2023, Day: 4, Title: "Scratchcards", Solve: y2023d04.Solve, Generate: y2023d04.Generate},
+	{Year: 2023, Day: 5, Title: "If You Give A Seed A Fertilizer", Solve: y2023d05.Solve, Generate: y2023d05.Generate, Reference: y2023d05.Reference},
+	{Year: 2023, Day: 6, Title: "Wait For It", Solve: y2023d06.Solve, Generate: y2023d06.Generate},
+	{Year: 2023, Day: 7, Title: "Camel Cards", Solve: y2023d07.Solve, Generate: y2023d07.Generate},
+	{Year: 2023, Day: 8, Title: "Haunted Wasteland", Solve: y2023d08.Solve, Generate: y2023d08.Generate},
+	{Year: 2023, Day: 9, Title: "Mirage Maintenance", Solve: y2023d09.Solve, Generate: y2023d09.Generate},
+	{Year: 2023, Day: 10, Title: "Pipe Maze", Solve: y2023d10.Solve, Generate: y2023d10.Generate, Reference: y2023d10.Reference, Pictures: y2023d10.Pictures},
+	{Year: 2023, Day: 11, Title: "Cosmic Expansion", Solve: y2023d11.Solve, Generate: y2023d11.Generate},
+	{Year: 2023, Day: 12, Title: "Hot Springs", Solve: y2023d12.Solve, Generate: y2023d12.Generate, Reference: y2023d12.Reference},
+	{Year: 2023, Day: 13, Title: "Point of Incidence", Solve: y2023d13.Solve, Generate: y2023d13.Generate},
+	{Year: 2023, Day: 14, Title: "Parabolic Reflector Dish", Solve: y2023d14.Solve, Generate: y2023d14.Generate},
+	{Year: 2023, Day: 15, Title: "Lens Library", Solve: y2023d15.Solve, Generate: y2023d15.Generate},
+	{Year: 2023, Day: 16, Title: "The Floor Will Be Lava", Solve: y2023d16.Solve, Generate: y2023d16.Generate, Pictures: y2023d16.Pictures},
+	{Year: 2023, Day: 17, Title: "Clumsy Crucible", Solve: y2023d17.Solve, Generate: y2023d17.Generate},
+	{Year: 2023, Day: 18, Title: "Lavaduct Lagoon", Solve: y2023d18.Solve, Generate: y2023d18.Generate, Pictures: y2023d18.Pictures},
+	{Year: 2023, Day: 19, Title: "Aplenty", Solve: y2023d19.Solve, Generate: y2023d19.Generate},
+	{Year: 2023, Day: 21, Title: "Step Counter", Solve: y2023d21.Solve, Generate: y2023d21.Generate},
+	{Year: 2023, Day: 23, Title: "A Long Walk", Solve: y2023d23.Solve, Generate: y2023d23.Generate},
+	{Year: 2023, Day: 24, Title: "Never Tell Me The Odds", Solve: y2023d24.Solve, Generate: y2023d24.Generate},
+	{Year: 2023, Day: 25, Title: "Snowverload", Solve: y2023d25.Solve, Generate: y2023d25.Generate},
+
+	{Year: 2024, Day: 1, Title: "Historian Hysteria", Solve: y2024d01.Solve, Generate: y2024d01.Generate},
+	{Year: 2024, Day: 2, Title: "Red-Nosed Reports", Solve: y2024d02.Solve, Generate: y2024d02.Generate},
+	{Year: 2024, Day: 3, Title: "Mull It Over", Solve: y2024d03.Solve, Generate: y2024d03.Generate},
+	{Year: 2024, Day: 4, Title: "Ceres Search", Solve: y2024d04.Solve, Generate: y2024d04.Generate},
+	{Year: 2024, Day: 5, Title: "Print Queue", Solve: y2024d05.Solve, Generate: y2024d05.Generate, Reference: y2024d05.Reference},
+	{Year: 2024, Day: 6, Title: "Guard Gallivant", Solve: y2024d06.Solve, Generate: y2024d06.Generate, Pictures: y2024d06.Pictures},
+}
+
+// Lookup finds the puzzle for the given year and day.
+func Lookup(year, day int) (aoc.Puzzle, bool) {
+	for _, p := range puzzles {
+		if p.Year == year && p.Day == day {
+			return p, true
+		}
+	}
+	return aoc.Puzzle{}, false
+}
+
+// All returns every puzzle in calendar order. A year of 0 means every year.
+func All(year int) []aoc.Puzzle {
+	var list []aoc.Puzzle
+	for _, p := range puzzles {
+		if year == 0 || p.Year == year {
+			list = append(list, p)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Year != list[j].Year {
+			return list[i].Year < list[j].Year
+		}
+		return list[i].Day < list[j].Day
+	})
+	return list
+}
@@ -0,0 +1,124 @@
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/parse"
+)
+
+// TestGenerated runs every solver on inputs from its generator, which have
+// to be the same each time for the same seed and good enough for -strict,
+// and checks it against its reference if it has one.
+func TestGenerated(t *testing.T) {
+	defer func(was bool) { parse.Strict = was }(parse.Strict)
+	parse.Strict = true
+
+	for _, p := range All(0) {
+		t.Run(fmt.Sprintf("%d/day-%02d", p.Year, p.Day), func(t *testing.T) {
+			if p.Generate == nil {
+				t.Skip("no generator")
+			}
+			for _, size := range []int{1, 10, 40} {
+				for seed := uint64(1); seed <= 3; seed++ {
+					input, _ := p.Generated(seed, size)
+					if again, _ := p.Generated(seed, size); !bytes.Equal(input, again) {
+						t.Fatalf("seed %d, size %d: made two different inputs", seed, size)
+					}
+					if _, _, err := p.Solve(context.Background(), bytes.NewReader(input)); err != nil {
+						t.Errorf("seed %d, size %d: %v\n%s", seed, size, err, input)
+					}
+					if err := p.Check(input); err != nil {
+						t.Errorf("seed %d, size %d: %v\n%s", seed, size, err, input)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestCancelled checks that every solver looks at its context, so that a
+// run that is out of time stops rather than going on in the background.
+func TestCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, p := range All(0) {
+		if p.Generate == nil {
+			continue
+		}
+		input, _ := p.Generated(1, 10)
+		if _, _, _, err := p.Run(ctx, input); !errors.Is(err, context.Canceled) {
+			t.Errorf("%d day %d: got %v, want it to be cancelled", p.Year, p.Day, err)
+		}
+	}
+}
+
+// TestShrunk checks every solver on the inputs 'aoc shrink' once cut down
+// from ones that tripped it up, which are kept with the day's source.
+func TestShrunk(t *testing.T) {
+	defer func(was bool) { parse.Strict = was }(parse.Strict)
+	parse.Strict = true
+
+	for _, p := range All(0) {
+		names, err := filepath.Glob(filepath.Join("..", p.Dir(), "testdata", "shrunk", "*"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range names {
+			input, err := os.ReadFile(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := p.Check(input); err != nil {
+				t.Errorf("%s: %v\n%s", name, err, input)
+			}
+		}
+	}
+}
+
+// TestQuiet checks that no solver writes to standard output or reads from
+// standard input, which the runner keeps for the answers and the input.
+func TestQuiet(t *testing.T) {
+	for _, p := range All(0) {
+		names, err := filepath.Glob(filepath.Join("..", p.Dir(), "*.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range names {
+			if strings.HasSuffix(name, "_test.go") {
+				continue
+			}
+			f, err := parser.ParseFile(token.NewFileSet(), name, nil, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ast.Inspect(f, func(n ast.Node) bool {
+				var call string
+				switch n := n.(type) {
+				case *ast.SelectorExpr:
+					if pkg, ok := n.X.(*ast.Ident); ok {
+						call = pkg.Name + "." + n.Sel.Name
+					}
+				case *ast.CallExpr:
+					if fn, ok := n.Fun.(*ast.Ident); ok {
+						call = fn.Name
+					}
+				}
+				switch call {
+				case "fmt.Print", "fmt.Printf", "fmt.Println", "os.Stdout", "os.Stdin", "print", "println":
+					t.Errorf("%s uses %s", name, call)
+				}
+				return true
+			})
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"runtime"
+	"text/tabwriter"
+	"time"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/calendar"
+)
+
+// benchResult is the average of the runs of one solver.
+type benchResult struct {
+	Runs   int           `json:"runs"`
+	Parse  time.Duration `json:"parse_ns"`
+	Solve  time.Duration `json:"solve_ns"`
+	Part1  time.Duration `json:"part1_ns,omitempty"`
+	Part2  time.Duration `json:"part2_ns,omitempty"`
+	Allocs int64         `json:"allocs_per_run"`
+	Bytes  int64         `json:"bytes_per_run"`
+	Input  string        `json:"input,omitempty"` // "generated" if there was no input.txt
+}
+
+func (r benchResult) total() time.Duration { return r.Parse + r.Solve }
+
+// noise is the least slowdown worth mentioning, since the fastest days
+// vary by more than any threshold from one run to the next.
+const noise = time.Millisecond
+
+func benchKey(p aoc.Puzzle) string { return fmt.Sprintf("%d/%02d", p.Year, p.Day) }
+
+// benchTime is about how long each solver is run for.
+const benchTime = time.Second
+
+// benchmark runs a solver on input for about benchTime, or once if that is
+// all it has time for. Like go test -bench, it runs it once to see how long
+// it takes, then again as many times as should fill benchTime, and counts
+// what the last batch of runs allocated.
+func benchmark(p aoc.Puzzle, input []byte) (benchResult, error) {
+	var res benchResult
+	for n, elapsed := 1, time.Duration(0); ; {
+		var sum aoc.Timing
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+		for i := 0; i < n; i++ {
+			t, err := p.Time(input)
+			if err != nil {
+				return res, fmt.Errorf("%d day %d: %w", p.Year, p.Day, err)
+			}
+			sum.Parse += t.Parse
+			sum.Solve += t.Solve
+			sum.Part1 += t.Part1
+			sum.Part2 += t.Part2
+		}
+		elapsed = time.Since(start)
+		runtime.ReadMemStats(&after)
+
+		runs := time.Duration(n)
+		res = benchResult{
+			Runs:   n,
+			Parse:  sum.Parse / runs,
+			Solve:  sum.Solve / runs,
+			Part1:  sum.Part1 / runs,
+			Part2:  sum.Part2 / runs,
+			Allocs: int64(after.Mallocs-before.Mallocs) / int64(n),
+			Bytes:  int64(after.TotalAlloc-before.TotalAlloc) / int64(n),
+		}
+		if elapsed >= benchTime || n >= 1e9 {
+			return res, nil
+		}
+		n = nextRuns(n, elapsed)
+	}
+}
+
+// nextRuns is how many runs should take benchTime, going by n runs having
+// taken elapsed: a fifth more than that, to be sure of getting there, but
+// never more than a hundred times as many at once, nor fewer than one more.
+func nextRuns(n int, elapsed time.Duration) int {
+	next := int64(benchTime) * int64(n) / max(int64(elapsed), 1)
+	next += next / 5
+	return int(min(max(next, int64(n)+1), 100*int64(n), 1e9))
+}
+
+func loadBaseline(path string) (map[string]benchResult, error) {
+	base := map[string]benchResult{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return base, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &base); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return base, nil
+}
+
+func saveBaseline(path string, base map[string]benchResult) error {
+	data, err := json.MarshalIndent(base, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// compare describes how a result differs from the baseline, and whether it
+// is slower by more than threshold, a fraction of the baseline.
+func compare(res benchResult, base benchResult, ok bool, threshold float64) (string, bool) {
+	if !ok || base.total() == 0 || base.Input != res.Input {
+		return "new", false
+	}
+	change := float64(res.total()-base.total()) / float64(base.total())
+	slower := change > threshold && res.total()-base.total() > noise
+	text := fmt.Sprintf("%+.0f%%", change*100)
+	if slower {
+		text += " SLOWER"
+	}
+	return text, slower
+}
+
+// round keeps about four significant figures.
+func round(d time.Duration) string {
+	switch {
+	case d == 0:
+		return "-"
+	case d >= time.Second:
+		d = d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		d = d.Round(time.Microsecond)
+	}
+	return d.String()
+}
+
+// isTerminal reports whether f is a terminal rather than a file or a pipe,
+// which is the only place a progress line can be overwritten.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func benchCommand(args []string) error {
+	var pf puzzleFlags
+	var baselinePath string
+	var save bool
+	var threshold float64
+
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	pf.register(fs)
+	fs.StringVar(&baselinePath, "baseline", "bench-baseline.json", "timings to compare against")
+	fs.BoolVar(&save, "save", false, "save these timings as the new baseline")
+	fs.Float64Var(&threshold, "threshold", 0.2, "how much slower than the baseline is flagged, as a fraction")
+	fs.Parse(args)
+
+	puzzles := calendar.All(pf.year)
+	if pf.day != 0 {
+		p, err := pf.lookup()
+		if err != nil {
+			return err
+		}
+		puzzles = []aoc.Puzzle{p}
+	}
+	base, err := loadBaseline(baselinePath)
+	if err != nil {
+		return err
+	}
+
+	// The solvers' logs are not part of the report.
+	out, level := aoc.Logging()
+	aoc.SetLogging(io.Discard, aoc.Quiet)
+	defer aoc.SetLogging(out, level)
+	progress := isTerminal(os.Stderr)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "YEAR\tDAY\tINPUT\tRUNS\tPARSE\tPART 1\tPART 2\tTOTAL\tALLOCS\tBYTES\tBASELINE\t")
+	slower, benched := 0, 0
+	for _, p := range puzzles {
+		input, from, err := allInput(p)
+		if from == "" {
+			continue // nothing to run it on
+		} else if err != nil {
+			return err
+		}
+		if progress {
+			fmt.Fprintf(os.Stderr, "benchmarking %d day %d...\r", p.Year, p.Day)
+		}
+
+		res, err := benchmark(p, input)
+		if err != nil {
+			fmt.Fprintf(tw, "%d\t%d\t%s\t\t\t\t\t\t\t\t%v\t\n", p.Year, p.Day, from, err)
+			continue
+		}
+		if from == "generated" {
+			res.Input = from
+		}
+		benched++
+		old, ok := base[benchKey(p)]
+		change, worse := compare(res, old, ok, threshold)
+		if worse {
+			slower++
+		}
+		part1, part2 := round(res.Part1), round(res.Part2)
+		if res.Part1 == 0 && res.Part2 == 0 {
+			part1, part2 = "-", "-"
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%s\t%d\t%s\t%s\t%s\t%s\t%d\t%d\t%s\t\n",
+			p.Year, p.Day, from, res.Runs, round(res.Parse), part1, part2, round(res.total()), res.Allocs, res.Bytes, change)
+		if save {
+			base[benchKey(p)] = res
+		}
+	}
+	if progress {
+		fmt.Fprint(os.Stderr, "\033[K") // clear the progress line
+	}
+	tw.Flush()
+
+	if benched == 0 {
+		return errors.New("no inputs to benchmark; see 'aoc fetch'")
+	}
+	if save {
+		if err := saveBaseline(baselinePath, base); err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stdout, "saved baseline to", baselinePath)
+	}
+	if slower > 0 {
+		return fmt.Errorf("%d days are more than %.0f%% slower than the baseline", slower, threshold*100)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextRuns(t *testing.T) {
+	tests := []struct {
+		n       int
+		elapsed time.Duration
+		want    int
+	}{
+		{1, 100 * time.Millisecond, 12},    // ten fill a second, and a fifth more
+		{1, time.Nanosecond, 100},          // at most a hundred times as many
+		{1, 0, 100},                        // too quick for the clock
+		{10, 900 * time.Millisecond, 13},   // 11 would do, plus a fifth
+		{1, 999 * time.Millisecond, 2},     // always at least one more
+		{5e8, 100 * time.Millisecond, 1e9}, // and no more than a billion
+	}
+	for _, tt := range tests {
+		if got := nextRuns(tt.n, tt.elapsed); got != tt.want {
+			t.Errorf("nextRuns(%d, %v) = %d, want %d", tt.n, tt.elapsed, got, tt.want)
+		}
+	}
+}
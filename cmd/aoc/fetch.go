@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/calendar"
+)
+
+// siteFlags are the flags of the commands that talk to the site.
+type siteFlags struct {
+	url, sessionFile string
+}
+
+func (sf *siteFlags) register(fs *flag.FlagSet) {
+	url := os.Getenv("AOC_URL")
+	if url == "" {
+		url = aoc.DefaultBaseURL
+	}
+	fs.StringVar(&sf.url, "url", url, "base URL of the site, or set AOC_URL")
+	fs.StringVar(&sf.sessionFile, "session-file", "", "file holding the session token when AOC_SESSION is not set (default <config dir>/aoc/session)")
+}
+
+func (sf *siteFlags) client() (*aoc.Client, error) {
+	session, err := aoc.LoadSession(sf.sessionFile)
+	if err != nil {
+		return nil, err
+	}
+	return aoc.NewClient(sf.url, session), nil
+}
+
+var errCached = errors.New("already downloaded, delete it to download it again")
+
+// fetchInput downloads the input for a puzzle to path, unless it is there
+// already. Inputs never change, so there is no reason to ask twice.
+func fetchInput(c *aoc.Client, year, day int, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s: %w", path, errCached)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	data, err := c.Input(year, day)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("%d day %d: empty input", year, day)
+	}
+
+	// write it under another name first, so that a download cut short is
+	// never mistaken for a cached input
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".input-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func fetchCommand(args []string) error {
+	var pf puzzleFlags
+	var sf siteFlags
+
+	fs := flag.NewFlagSet("fetch", flag.ExitOnError)
+	pf.register(fs)
+	sf.register(fs)
+	fs.Parse(args)
+
+	if pf.year == 0 {
+		return fmt.Errorf("-year is required")
+	}
+	if pf.day < 0 || pf.day > 25 {
+		return fmt.Errorf("-day must be 1 to 25")
+	}
+	c, err := sf.client()
+	if err != nil {
+		return err
+	}
+
+	// a single day need not be solved yet, but a whole year means the days
+	// that have solutions
+	puzzles := []aoc.Puzzle{{Year: pf.year, Day: pf.day}}
+	if pf.day == 0 {
+		puzzles = calendar.All(pf.year)
+	}
+	for _, p := range puzzles {
+		err := fetchInput(c, p.Year, p.Day, p.InputPath())
+		if errors.Is(err, errCached) && len(puzzles) > 1 {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("fetched %d day %d to %s\n", p.Year, p.Day, p.InputPath())
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/havill/AdventOfCode/aoc"
+)
+
+func TestFetchInput(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("1abc2\n"))
+	}))
+	defer srv.Close()
+
+	c := aoc.NewClient(srv.URL, "secret")
+	c.Interval = 0
+	path := filepath.Join(t.TempDir(), "2023", "day-01", "input.txt")
+
+	if err := fetchInput(c, 2023, 1, path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "1abc2\n" {
+		t.Errorf("cached input = %q", data)
+	}
+
+	if err := fetchInput(c, 2023, 1, path); !errors.Is(err, errCached) {
+		t.Errorf("second fetch returned %v, want errCached", err)
+	}
+	if requests != 1 {
+		t.Errorf("server was asked %d times, want 1", requests)
+	}
+}
+
+func TestFetchInputFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Puzzle inputs differ by user.  Please log in to get your puzzle input.", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	c := aoc.NewClient(srv.URL, "expired")
+	c.Interval = 0
+	dir := t.TempDir()
+	path := filepath.Join(dir, "input.txt")
+
+	if err := fetchInput(c, 2023, 1, path); err == nil {
+		t.Fatal("fetch succeeded, want an error")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("failed fetch left %d files behind", len(entries))
+	}
+}
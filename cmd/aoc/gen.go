@@ -0,0 +1,30 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func genCommand(args []string) error {
+	var pf puzzleFlags
+	var seed uint64
+	var size int
+
+	fs := flag.NewFlagSet("gen", flag.ExitOnError)
+	pf.register(fs)
+	fs.Uint64Var(&seed, "seed", 1, "random seed; the same seed makes the same input")
+	fs.IntVar(&size, "size", 100, "roughly how many lines or rows to make")
+	fs.Parse(args)
+
+	p, err := pf.lookup()
+	if err != nil {
+		return err
+	}
+	input, ok := p.Generated(seed, size)
+	if !ok {
+		return fmt.Errorf("%d day %d has no input generator", p.Year, p.Day)
+	}
+	_, err = os.Stdout.Write(input)
+	return err
+}
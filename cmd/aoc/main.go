@@ -0,0 +1,57 @@
+// Command aoc runs any of the Advent of Code solutions in this repo.
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+type command struct {
+	run   func(args []string) error
+	usage string
+}
+
+var commands = map[string]command{
+	"bench":  {benchCommand, "time the solvers and compare them with a saved baseline"},
+	"fetch":  {fetchCommand, "download puzzle inputs that are not here yet"},
+	"gen":    {genCommand, "make up an input of the right shape from a seed"},
+	"new":    {newCommand, "start a new day from the template"},
+	"run":    {runCommand, "solve a puzzle and print its answers"},
+	"serve":  {serveCommand, "show a dashboard of every puzzle in a web browser"},
+	"shrink": {shrinkCommand, "cut an input that trips a solver down to a small one that still does"},
+	"submit": {submitCommand, "give an answer to the site and record it if it is right"},
+	"verify": {verifyCommand, "check answers against the ones that were accepted"},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: aoc <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", name, commands[name].usage)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "run 'aoc <command> -h' for the flags of a command")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "aoc: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "aoc:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/calendar"
+)
+
+const calendarFile = "calendar/calendar.go"
+
+// A day starts out parsing its input into lines and saying that neither
+// part is solved, with the marks for aoc bench already in place.
+var solverTemplate = template.Must(template.New("solver").Parse(`package day{{printf "%02d" .Day}}
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/parse"
+)
+
+var errNotSolved = errors.New("not solved yet")
+
+func part1(lines []parse.Line) (any, error) {
+	return nil, errNotSolved
+}
+
+func part2(lines []parse.Line) (any, error) {
+	return nil, errNotSolved
+}
+
+func Solve(ctx context.Context, r io.Reader) (any, any, error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	aoc.Parsed(ctx)
+
+	answer1, err := part1(lines)
+	if err != nil {
+		return nil, nil, err
+	}
+	aoc.Part1Done(ctx)
+
+	answer2, err := part2(lines)
+	if err != nil {
+		return nil, nil, err
+	}
+	return answer1, answer2, nil
+}
+`))
+
+var testTemplate = template.Must(template.New("test").Parse(`package day{{printf "%02d" .Day}}
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/aoc"
+)
+
+// example is the example from the puzzle text.
+const example = ` + "``" + `
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		part1, part2 any // nil is not checked
+	}{
+		{"example", example, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.input == "" {
+				t.Skip("no input yet")
+			}
+			part1, part2, err := Solve(context.Background(), strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.part1 != nil && aoc.FormatAnswer(part1) != aoc.FormatAnswer(tt.part1) {
+				t.Errorf("part 1 = %v, want %v", part1, tt.part1)
+			}
+			if tt.part2 != nil && aoc.FormatAnswer(part2) != aoc.FormatAnswer(tt.part2) {
+				t.Errorf("part 2 = %v, want %v", part2, tt.part2)
+			}
+		})
+	}
+}
+`))
+
+var (
+	importLine = regexp.MustCompile(`^\ty(\d{4})d(\d{2}) "`)
+	puzzleLine = regexp.MustCompile(`^\t\{Year: (\d+), Day: (\d+),`)
+)
+
+// insertSorted puts entry among the lines that match pattern, in year and
+// day order, with a blank line between years as the calendar has them.
+func insertSorted(lines []string, pattern *regexp.Regexp, year, day int, entry string) ([]string, error) {
+	first, after := -1, -1 // after is the index of the last line before the new one
+	sameYear := false
+	for i, line := range lines {
+		m := pattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		y, _ := strconv.Atoi(m[1])
+		d, _ := strconv.Atoi(m[2])
+		if y == year && d == day {
+			return nil, fmt.Errorf("%d day %d is already in %s", year, day, calendarFile)
+		}
+		if y < year || y == year && d < day {
+			after = i
+			sameYear = y == year
+		} else if y == year {
+			sameYear = true
+		}
+	}
+	if first < 0 {
+		return nil, fmt.Errorf("can't find where the puzzles are listed in %s", calendarFile)
+	}
+
+	var add []string
+	at := after + 1
+	switch {
+	case after < 0:
+		// before everything, so the year is either the first or new
+		at = first
+		add = []string{entry}
+		if !sameYear {
+			add = append(add, "")
+		}
+	case sameYear:
+		add = []string{entry}
+	default:
+		// the first day of a new year, after the blank line ending the last
+		add = []string{"", entry}
+	}
+	return append(lines[:at], append(add, lines[at:]...)...), nil
+}
+
+// register adds the puzzle to the calendar source.
+func register(src []byte, p aoc.Puzzle) ([]byte, error) {
+	alias := fmt.Sprintf("y%dd%02d", p.Year, p.Day)
+	lines := strings.Split(string(src), "\n")
+	lines, err := insertSorted(lines, importLine, p.Year, p.Day,
+		fmt.Sprintf("\t%s %q", alias, "github.com/havill/AdventOfCode/"+filepath.ToSlash(p.Dir())))
+	if err != nil {
+		return nil, err
+	}
+	lines, err = insertSorted(lines, puzzleLine, p.Year, p.Day,
+		fmt.Sprintf("\t{Year: %d, Day: %d, Title: %q, Solve: %s.Solve},", p.Year, p.Day, p.Title, alias))
+	if err != nil {
+		return nil, err
+	}
+	return format.Source([]byte(strings.Join(lines, "\n")))
+}
+
+func execute(t *template.Template, p aoc.Puzzle) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, p); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+func newCommand(args []string) error {
+	var pf puzzleFlags
+	var title string
+
+	fs := flag.NewFlagSet("new", flag.ExitOnError)
+	pf.register(fs)
+	fs.StringVar(&title, "title", "", "puzzle title, as on its page")
+	fs.Parse(args)
+
+	if pf.year == 0 || pf.day < 1 || pf.day > 25 {
+		return errors.New("-year and a -day from 1 to 25 are required")
+	}
+	if _, ok := calendar.Lookup(pf.year, pf.day); ok {
+		return fmt.Errorf("%d day %d already has a solution", pf.year, pf.day)
+	}
+	p := aoc.Puzzle{Year: pf.year, Day: pf.day, Title: title}
+
+	src, err := os.ReadFile(calendarFile)
+	if err != nil {
+		return fmt.Errorf("%w (run aoc new from the top of the repo)", err)
+	}
+	cal, err := register(src, p)
+	if err != nil {
+		return err
+	}
+	solver, err := execute(solverTemplate, p)
+	if err != nil {
+		return err
+	}
+	test, err := execute(testTemplate, p)
+	if err != nil {
+		return err
+	}
+
+	// Only write anything once everything is ready, and never over a day
+	// that was started some other way.
+	if _, err := os.Stat(p.Dir()); err == nil {
+		return fmt.Errorf("%s already exists", p.Dir())
+	}
+	if err := os.MkdirAll(p.Dir(), 0o755); err != nil {
+		return err
+	}
+	err = writeAll([]newFile{
+		{filepath.Join(p.Dir(), "main.go"), solver},
+		{filepath.Join(p.Dir(), "main_test.go"), test},
+		{calendarFile, cal},
+	})
+	if err != nil {
+		// the calendar is renamed into place last, so it still doesn't
+		// know of the day, and nothing else should either; the year's
+		// directory goes too if the day was the first in it
+		os.RemoveAll(p.Dir())
+		os.Remove(filepath.Dir(p.Dir()))
+		return err
+	}
+	return nil
+}
+
+type newFile struct {
+	path string
+	data []byte
+}
+
+// writeAll writes every file under another name first and only renames them
+// into place once they have all been written, so that a failure part of the
+// way through leaves the files as they were.
+func writeAll(files []newFile) error {
+	var temps []string
+	defer func() {
+		for _, name := range temps {
+			os.Remove(name)
+		}
+	}()
+	for _, f := range files {
+		tmp, err := os.CreateTemp(filepath.Dir(f.path), ".new-*")
+		if err != nil {
+			return err
+		}
+		temps = append(temps, tmp.Name())
+		if _, err := tmp.Write(f.data); err != nil {
+			tmp.Close()
+			return err
+		}
+		if err := tmp.Close(); err != nil {
+			return err
+		}
+		if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+			return err
+		}
+	}
+	for i, f := range files {
+		if err := os.Rename(temps[i], f.path); err != nil {
+			return err
+		}
+		fmt.Println("wrote", f.path)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/aoc"
+)
+
+const testCalendar = `package calendar
+
+import (
+	"github.com/havill/AdventOfCode/aoc"
+
+	y2023d01 "github.com/havill/AdventOfCode/2023/day-01"
+	y2023d03 "github.com/havill/AdventOfCode/2023/day-03"
+)
+
+var puzzles = []aoc.Puzzle{
+	{Year: 2023, Day: 1, Title: "Trebuchet?!", Solve: y2023d01.Solve},
+	{Year: 2023, Day: 3, Title: "Gear Ratios", Solve: y2023d03.Solve},
+}
+`
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name  string
+		p     aoc.Puzzle
+		after string // the line the new entry should follow
+		gap   bool   // whether it starts a year
+	}{
+		{"between days", aoc.Puzzle{Year: 2023, Day: 2, Title: "Cube Conundrum"}, "y2023d01.Solve},", false},
+		{"new year", aoc.Puzzle{Year: 2024, Day: 1}, "y2023d03.Solve},", true},
+		{"earlier year", aoc.Puzzle{Year: 2022, Day: 5}, "var puzzles = []aoc.Puzzle{", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := register([]byte(testCalendar), tt.p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			src := string(out)
+			entry := tt.p.Title
+			if entry == "" {
+				entry = `Title: "",`
+			}
+			lines := strings.Split(src, "\n")
+			for i, line := range lines {
+				if !strings.Contains(line, entry) || !strings.Contains(line, "Year:") {
+					continue
+				}
+				prev := lines[i-1]
+				if tt.gap {
+					prev = lines[i-2]
+					if lines[i-1] != "" {
+						t.Errorf("no blank line before a new year:\n%s", src)
+					}
+				}
+				if !strings.HasSuffix(prev, tt.after) {
+					t.Errorf("entry follows %q, want %q:\n%s", prev, tt.after, src)
+				}
+				if !strings.Contains(src, "/"+filepath.ToSlash(tt.p.Dir())+`"`) {
+					t.Errorf("no import of %s:\n%s", tt.p.Dir(), src)
+				}
+				return
+			}
+			t.Fatalf("no entry for %v:\n%s", tt.p, src)
+		})
+	}
+
+	if _, err := register([]byte(testCalendar), aoc.Puzzle{Year: 2023, Day: 3}); err == nil {
+		t.Error("registering a day twice succeeded")
+	}
+}
+
+// TestWriteAll checks that nothing is written when one of the files can't
+// be, and that no temporary files are left behind either way.
+func TestWriteAll(t *testing.T) {
+	dir := t.TempDir()
+	day := filepath.Join(dir, "day-01")
+	if err := os.Mkdir(day, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	err := writeAll([]newFile{
+		{filepath.Join(day, "main.go"), []byte("package day01\n")},
+		{filepath.Join(dir, "calendar", "calendar.go"), []byte("package calendar\n")},
+	})
+	if err == nil {
+		t.Fatal("writing into a missing directory worked")
+	}
+	if entries, _ := os.ReadDir(day); len(entries) != 0 {
+		t.Errorf("a failed write left %v", entries)
+	}
+
+	files := []newFile{
+		{filepath.Join(day, "main.go"), []byte("package day01\n")},
+		{filepath.Join(dir, "calendar.go"), []byte("package calendar\n")},
+	}
+	if err := writeAll(files); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		if data, err := os.ReadFile(f.path); err != nil || string(data) != string(f.data) {
+			t.Errorf("%s: got %q, %v, want %q", f.path, data, err, f.data)
+		}
+	}
+	if entries, _ := os.ReadDir(day); len(entries) != 1 {
+		t.Errorf("%s has %v, want only main.go", day, entries)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/render"
+)
+
+// findPicture finds the puzzle's picture by name, or its first when the
+// name is empty.
+func findPicture(p aoc.Puzzle, name string) (render.Picture, error) {
+	if len(p.Pictures) == 0 {
+		return render.Picture{}, fmt.Errorf("%d day %d has no pictures", p.Year, p.Day)
+	}
+	var names []string
+	for _, pic := range p.Pictures {
+		if name == "" || pic.Name == name {
+			return pic, nil
+		}
+		names = append(names, pic.Name)
+	}
+	return render.Picture{}, fmt.Errorf("%d day %d has no picture %q, only %s", p.Year, p.Day, name, strings.Join(names, ", "))
+}
+
+// savePicture draws a picture of the input to a PNG file, an SVG file or
+// both, with each cell of the grid cell pixels wide. Drawing stops when ctx
+// is done.
+func savePicture(ctx context.Context, pic render.Picture, input []byte, pngFile, svgFile string, cell int) error {
+	frame, err := pic.Draw(ctx, bytes.NewReader(input))
+	if err != nil {
+		return err
+	}
+	for _, out := range []struct {
+		name  string
+		write func(*bytes.Buffer) error
+	}{
+		{pngFile, func(b *bytes.Buffer) error { return render.PNG(b, frame, cell) }},
+		{svgFile, func(b *bytes.Buffer) error { return render.SVG(b, frame, cell) }},
+	} {
+		if out.name == "" {
+			continue
+		}
+		var b bytes.Buffer
+		if err := out.write(&b); err != nil {
+			return err
+		}
+		if err := os.WriteFile(out.name, b.Bytes(), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/calendar"
+	"github.com/havill/AdventOfCode/parse"
+	"github.com/havill/AdventOfCode/render"
+)
+
+// puzzleFlags are the flags every command uses to pick a puzzle.
+type puzzleFlags struct {
+	year, day int
+}
+
+func (pf *puzzleFlags) register(fs *flag.FlagSet) {
+	fs.IntVar(&pf.year, "year", 0, "puzzle year, e.g. 2023")
+	fs.IntVar(&pf.day, "day", 0, "puzzle day, 1 to 25")
+}
+
+func (pf *puzzleFlags) lookup() (aoc.Puzzle, error) {
+	if pf.year == 0 || pf.day == 0 {
+		return aoc.Puzzle{}, fmt.Errorf("both -year and -day are required")
+	}
+	p, ok := calendar.Lookup(pf.year, pf.day)
+	if !ok {
+		return aoc.Puzzle{}, fmt.Errorf("no solution for %d day %d", pf.year, pf.day)
+	}
+	return p, nil
+}
+
+// logFlags are the flags that turn on the solvers' logging and progress
+// reports.
+type logFlags struct {
+	verbose, veryVerbose, progress bool
+}
+
+func (lf *logFlags) register(fs *flag.FlagSet) {
+	fs.BoolVar(&lf.verbose, "v", false, "log what the solver is doing to standard error")
+	fs.BoolVar(&lf.veryVerbose, "vv", false, "log every step the solver takes as well")
+	fs.BoolVar(&lf.progress, "progress", false, "report how far long loops have got, and how long they have left, to standard error")
+}
+
+func (lf *logFlags) apply() {
+	if lf.progress {
+		aoc.SetProgress(os.Stderr)
+	}
+	switch {
+	case lf.veryVerbose:
+		aoc.SetLogging(os.Stderr, aoc.LevelTrace)
+	case lf.verbose:
+		aoc.SetLogging(os.Stderr, slog.LevelDebug)
+	}
+}
+
+// withTimeout returns a context that is done after timeout, or one that
+// never is if timeout is 0.
+func withTimeout(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.Background(), func() {}
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// solveError says which puzzle err came from, and how long it was given if
+// it ran out of time.
+func solveError(p aoc.Puzzle, timeout time.Duration, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%d day %d: gave up after %v", p.Year, p.Day, timeout)
+	}
+	return fmt.Errorf("%d day %d: %w", p.Year, p.Day, err)
+}
+
+// openInput opens the named input, the puzzle's usual input file when the
+// name is empty, or standard input when the name is "-".
+func openInput(p aoc.Puzzle, name string) (io.ReadCloser, error) {
+	if name == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	if name == "" {
+		name = p.InputPath()
+	}
+	return os.Open(name)
+}
+
+func runCommand(args []string) error {
+	var pf puzzleFlags
+	var lf logFlags
+	var part int
+	var input string
+	var animate bool
+	var fps int
+	var color string
+	var record string
+	var format string
+	var timeout time.Duration
+	var all bool
+	var jobs int
+	var pngFile, svgFile, picture string
+	var cell int
+	var gifFile, gifPalette string
+	var delay time.Duration
+	var every int
+
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	pf.register(fs)
+	lf.register(fs)
+	fs.BoolVar(&parse.Strict, "strict", false, "reject anything in the input the solver does not expect")
+	fs.IntVar(&part, "part", 0, "only print the answer to part 1 or 2")
+	fs.StringVar(&input, "input", "", "puzzle input file, or - for standard input (default <year>/day-<dd>/input.txt)")
+	fs.BoolVar(&animate, "animate", false, "draw the simulation of days that have one")
+	fs.IntVar(&fps, "fps", 30, "most frames a second to animate, 0 for no limit")
+	fs.StringVar(&color, "color", "auto", "colours to animate with: auto, truecolor, 256 or plain")
+	fs.StringVar(&record, "record", "", "write the animation to an asciicast file instead of the terminal")
+	fs.StringVar(&format, "format", "text", "how to print the answers: text or json")
+	fs.DurationVar(&timeout, "timeout", 0, "give up after this long, e.g. 30s; 0 for never")
+	fs.BoolVar(&all, "all", false, "run every puzzle, or every one of -year, and print a summary")
+	fs.IntVar(&jobs, "j", runtime.NumCPU(), "how many puzzles -all runs at once")
+	fs.StringVar(&pngFile, "png", "", "draw a picture of what the solver made of the input to this PNG file")
+	fs.StringVar(&svgFile, "svg", "", "draw a picture of what the solver made of the input to this SVG file")
+	fs.StringVar(&picture, "picture", "", "which of the day's pictures -png and -svg draw (default the first)")
+	fs.IntVar(&cell, "cell", 8, "how many pixels wide each square of the grid is in -png, -svg and -gif")
+	fs.StringVar(&gifFile, "gif", "", "record the animation of days that have one to this GIF file")
+	fs.DurationVar(&delay, "delay", 50*time.Millisecond, "how long each frame of -gif is shown")
+	fs.IntVar(&every, "every", 1, "keep one frame of -gif in every this many")
+	fs.StringVar(&gifPalette, "palette", "exact", "colours of -gif: exact, plan9 or websafe")
+	fs.Parse(args)
+
+	if part < 0 || part > 2 {
+		return fmt.Errorf("-part must be 1 or 2")
+	}
+	lf.apply()
+	if format != "text" && format != "json" {
+		return fmt.Errorf("-format must be text or json")
+	}
+	if format == "json" && animate && record == "" {
+		return fmt.Errorf("-animate draws on standard output, so -format json needs -record")
+	}
+	if all {
+		if pf.day != 0 || part != 0 || input != "" || animate || record != "" || pngFile != "" || svgFile != "" || gifFile != "" {
+			return fmt.Errorf("-all can't be used with -day, -part, -input, -animate, -record, -png, -svg or -gif")
+		}
+		return runAll(calendar.All(pf.year), jobs, timeout, format, lf.verbose || lf.veryVerbose)
+	}
+	if cell < 1 {
+		return fmt.Errorf("-cell must be at least 1")
+	}
+	if every < 1 {
+		return fmt.Errorf("-every must be at least 1")
+	}
+	if gifFile != "" && (animate || record != "") {
+		return fmt.Errorf("-gif can't be used with -animate or -record")
+	}
+	p, err := pf.lookup()
+	if err != nil {
+		return err
+	}
+	var pic render.Picture
+	if pngFile != "" || svgFile != "" {
+		if pic, err = findPicture(p, picture); err != nil {
+			return err
+		}
+	}
+	f, err := openInput(p, input)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	var stop func() error
+	if animate || record != "" {
+		stop, err = startScreen(color, fps, record)
+		if err != nil {
+			return err
+		}
+	}
+	if gifFile != "" {
+		stop, err = startGIF(gifFile, cell, delay, every, gifPalette)
+		if err != nil {
+			return err
+		}
+	}
+	ctx, cancel := withTimeout(timeout)
+	defer cancel()
+	part1, part2, t, err := p.Run(ctx, data)
+	if stop != nil {
+		if err := stop(); err != nil {
+			return err
+		}
+	}
+	if err != nil {
+		return solveError(p, timeout, err)
+	}
+	if pngFile != "" || svgFile != "" {
+		if err := savePicture(ctx, pic, data, pngFile, svgFile, cell); err != nil {
+			return fmt.Errorf("%d day %d: drawing %s: %w", p.Year, p.Day, pic.Name, err)
+		}
+	}
+
+	answers := []any{part1, part2}
+	if format == "json" {
+		return writeResults(os.Stdout, p, part, answers, t, data)
+	}
+	if part != 0 {
+		if answers[part-1] == nil {
+			return fmt.Errorf("%d day %d part %d is not solved", p.Year, p.Day, part)
+		}
+		fmt.Println(answers[part-1])
+		return nil
+	}
+	for i, answer := range answers {
+		if answer == nil {
+			fmt.Printf("Part %d: not solved\n", i+1)
+		} else {
+			fmt.Printf("Part %d: %v\n", i+1, answer)
+		}
+	}
+	return nil
+}
+
+// result is what -format json prints for each part, one to a line.
+type result struct {
+	Year   int     `json:"year"`
+	Day    int     `json:"day"`
+	Part   int     `json:"part"`
+	Answer *string `json:"answer"` // null when the part is not solved
+	// Duration is the time spent on the part after parsing, or on both parts
+	// when the solver does not say where part 1 ends.
+	Duration time.Duration `json:"duration_ns"`
+	Parse    time.Duration `json:"parse_ns"`
+	Input    string        `json:"input_sha256"`
+}
+
+func writeResults(w io.Writer, p aoc.Puzzle, part int, answers []any, t aoc.Timing, input []byte) error {
+	enc := json.NewEncoder(w)
+	for _, r := range results(p, part, answers, t, input) {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// results makes the results of a run, of every part or only the one asked
+// for.
+func results(p aoc.Puzzle, part int, answers []any, t aoc.Timing, input []byte) []result {
+	sum := sha256.Sum256(input)
+	durations := []time.Duration{t.Solve, t.Solve}
+	if t.Split() {
+		durations = []time.Duration{t.Part1, t.Part2}
+	}
+	var rs []result
+	for i, answer := range answers {
+		if part != 0 && part != i+1 {
+			continue
+		}
+		r := result{Year: p.Year, Day: p.Day, Part: i + 1, Duration: durations[i], Parse: t.Parse, Input: hex.EncodeToString(sum[:])}
+		if answer != nil {
+			text := aoc.FormatAnswer(answer)
+			r.Answer = &text
+		}
+		rs = append(rs, r)
+	}
+	return rs
+}
+
+// startScreen sets up render.Screen for the days that animate, drawing to
+// the terminal or recording to a cast file. The returned stop function
+// finishes the animation and must be called before anything else is printed.
+func startScreen(color string, fps int, record string) (stop func() error, err error) {
+	if record == "" {
+		profile, err := render.ParseProfile(color, os.Stdout)
+		if err != nil {
+			return nil, err
+		}
+		render.Screen = render.New(os.Stdout, profile, fps)
+		return func() error {
+			defer func() { render.Screen = nil }()
+			return render.Screen.Close()
+		}, nil
+	}
+
+	profile := render.TrueColor // asciinema plays back any colour
+	if color != "auto" {
+		if profile, err = render.ParseProfile(color, nil); err != nil {
+			return nil, err
+		}
+	}
+	out, err := os.Create(record)
+	if err != nil {
+		return nil, err
+	}
+	cast := render.NewCast(out)
+	render.Screen = render.New(cast, profile, fps)
+	return func() error {
+		defer func() { render.Screen = nil }()
+		err := render.Screen.Close()
+		if cerr := cast.Close(); err == nil {
+			err = cerr
+		}
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+		return err
+	}, nil
+}
+
+// startGIF sets up render.Screen to record the animation to a GIF file,
+// which the returned stop function writes out.
+func startGIF(name string, cell int, delay time.Duration, every int, palette string) (stop func() error, err error) {
+	pal, err := render.ParsePalette(palette)
+	if err != nil {
+		return nil, err
+	}
+	out, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	render.Screen = render.NewGIF(out, cell, delay, every, pal)
+	return func() error {
+		defer func() { render.Screen = nil }()
+		err := render.Screen.Close()
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(name)
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/havill/AdventOfCode/aoc"
+)
+
+// outcome is how one puzzle went in a run of them all.
+type outcome struct {
+	puzzle   aoc.Puzzle
+	input    string // where the input came from
+	answers  []any
+	timing   aoc.Timing
+	data     []byte
+	duration time.Duration // from start to finish, whether or not it finished well
+	timeout  time.Duration
+	err      error
+}
+
+// status is what the summary says about how the run ended.
+func (o outcome) status() string {
+	var pe *aoc.PanicError
+	switch {
+	case o.err == nil:
+		return "ok"
+	case errors.As(o.err, &pe):
+		return "PANIC"
+	case errors.Is(o.err, context.DeadlineExceeded):
+		return "TIMEOUT"
+	case o.input == "":
+		return "no input"
+	default:
+		return "ERROR"
+	}
+}
+
+// allInput reads a puzzle's input for -all, making one up from seed 1 when
+// there isn't one, as the benchmarks do, so that every day with a generator
+// gets run.
+func allInput(p aoc.Puzzle) ([]byte, string, error) {
+	data, err := os.ReadFile(p.InputPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		if data, ok := p.Generated(1, 100); ok {
+			return data, "generated", nil
+		}
+		return nil, "", fmt.Errorf("no input")
+	}
+	return data, "input.txt", err
+}
+
+// runPuzzle runs one puzzle for -all. It returns only once the solver has
+// stopped, even when it runs out of time, so that a worker is never freed
+// while its solver is still using the CPU.
+func runPuzzle(p aoc.Puzzle, timeout time.Duration) outcome {
+	o := outcome{puzzle: p, timeout: timeout}
+	var err error
+	if o.data, o.input, err = allInput(p); err != nil {
+		o.err = err
+		return o
+	}
+	ctx, cancel := withTimeout(timeout)
+	defer cancel()
+	start := time.Now()
+	part1, part2, t, err := p.Run(ctx, o.data)
+	o.duration = time.Since(start)
+	o.answers, o.timing, o.err = []any{part1, part2}, t, err
+	return o
+}
+
+// runAll runs every puzzle on jobs workers and writes a summary of them in
+// calendar order, or their answers as JSON. It fails if any of them did.
+func runAll(puzzles []aoc.Puzzle, jobs int, timeout time.Duration, format string, stack bool) error {
+	outcomes := make([]outcome, len(puzzles))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for range max(jobs, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				outcomes[i] = runPuzzle(puzzles[i], timeout)
+			}
+		}()
+	}
+	for i := range puzzles {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	wall := time.Since(start)
+
+	failed := 0
+	for _, o := range outcomes {
+		if o.err == nil || o.input == "" {
+			continue // a day with nothing to run it on hasn't failed
+		}
+		failed++
+		var pe *aoc.PanicError
+		if stack && errors.As(o.err, &pe) {
+			fmt.Fprintf(os.Stderr, "%v\n%s\n", solveError(o.puzzle, o.timeout, o.err), pe.Stack)
+		}
+	}
+	if format == "json" {
+		for _, o := range outcomes {
+			if o.err != nil {
+				fmt.Fprintln(os.Stderr, "aoc:", solveError(o.puzzle, o.timeout, o.err))
+			} else if err := writeResults(os.Stdout, o.puzzle, 0, o.answers, o.timing, o.data); err != nil {
+				return err
+			}
+		}
+	} else {
+		writeSummary(os.Stdout, outcomes, wall)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d puzzles failed", failed, len(outcomes))
+	}
+	return nil
+}
+
+// writeSummary writes a table of the outcomes with the time they took all
+// together and one after another.
+func writeSummary(w io.Writer, outcomes []outcome, wall time.Duration) {
+	var sum time.Duration
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "YEAR\tDAY\tSTATUS\tTIME\tINPUT\tPART 1\tPART 2")
+	for _, o := range outcomes {
+		sum += o.duration
+		var answers []string
+		switch {
+		case o.input == "":
+		case errors.Is(o.err, context.DeadlineExceeded):
+			answers = []string{fmt.Sprintf("gave up after %v", o.timeout)}
+		case o.err != nil:
+			answers = []string{strings.SplitN(o.err.Error(), "\n", 2)[0]}
+		default:
+			for _, answer := range o.answers {
+				if answer == nil {
+					answers = append(answers, "-")
+				} else {
+					answers = append(answers, aoc.FormatAnswer(answer))
+				}
+			}
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%s\t%s\t%s\t%s\n",
+			o.puzzle.Year, o.puzzle.Day, o.status(), round(o.duration), o.input, strings.Join(answers, "\t"))
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "%d puzzles in %s, %s one after another\n", len(outcomes), round(wall), round(sum))
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand/v2"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/havill/AdventOfCode/aoc"
+)
+
+func TestRunPuzzle(t *testing.T) {
+	input := func(*rand.Rand, int) []byte { return []byte("1\n") }
+	puzzles := []aoc.Puzzle{
+		{Year: 2099, Day: 1, Generate: input, Solve: func(context.Context, io.Reader) (any, any, error) { return 42, nil, nil }},
+		{Year: 2099, Day: 2, Generate: input, Solve: func(context.Context, io.Reader) (any, any, error) { panic("off the map") }},
+		{Year: 2099, Day: 3, Generate: input, Solve: func(ctx context.Context, _ io.Reader) (any, any, error) {
+			<-ctx.Done()
+			return nil, nil, ctx.Err()
+		}},
+		{Year: 2099, Day: 4, Solve: func(context.Context, io.Reader) (any, any, error) { return 1, 2, nil }},
+	}
+	var outcomes []outcome
+	for _, p := range puzzles {
+		outcomes = append(outcomes, runPuzzle(p, 50*time.Millisecond))
+	}
+	var b bytes.Buffer
+	writeSummary(&b, outcomes, time.Second)
+
+	// the times vary, and the columns' widths with them, so only the rest is
+	// compared
+	got := regexp.MustCompile(`\d+(\.\d+)?(µs|ms|ns|s)\b`).ReplaceAllString(b.String(), "T")
+	got = regexp.MustCompile(` +`).ReplaceAllString(got, " ")
+	want := strings.Join([]string{
+		"YEAR DAY STATUS TIME INPUT PART 1 PART 2",
+		"2099 1 ok T generated 42 -",
+		"2099 2 PANIC T generated panic: off the map",
+		"2099 3 TIMEOUT T generated gave up after T",
+		"2099 4 no input - ",
+		"4 puzzles in T, T one after another",
+	}, "\n") + "\n"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestRunAllJobs checks that no more than -j solvers run at once, counting
+// the ones that have run out of time but not yet stopped.
+func TestRunAllJobs(t *testing.T) {
+	var mu sync.Mutex
+	running, most := 0, 0
+	slow := func(ctx context.Context, _ io.Reader) (any, any, error) {
+		mu.Lock()
+		running++
+		most = max(most, running)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}()
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond) // winding down
+		return nil, nil, ctx.Err()
+	}
+	input := func(*rand.Rand, int) []byte { return []byte("1\n") }
+	var puzzles []aoc.Puzzle
+	for day := 1; day <= 4; day++ {
+		puzzles = append(puzzles, aoc.Puzzle{Year: 2099, Day: day, Generate: input, Solve: slow})
+	}
+	if err := runAll(puzzles, 2, 10*time.Millisecond, "json", false); err == nil {
+		t.Errorf("every day ran out of time, but runAll didn't fail")
+	}
+	if most != 2 {
+		t.Errorf("%d solvers ran at once, want 2", most)
+	}
+}
@@ -0,0 +1,488 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/calendar"
+	"github.com/havill/AdventOfCode/parse"
+	"github.com/havill/AdventOfCode/render"
+)
+
+// Verdicts that only the dashboard gives, besides the ones verify does.
+const (
+	notRun    verdict = "not run"
+	generated verdict = "unchecked" // answered on a made-up input, which has no accepted answer
+)
+
+// dashboard is aoc serve: every puzzle, and how each went the last time it
+// was run from the dashboard.
+type dashboard struct {
+	puzzles  []aoc.Puzzle
+	timeout  time.Duration
+	maxInput int64 // the most that can be posted to /solve
+
+	running sync.Mutex // one run or picture at a time, however many are asked for
+	mu      sync.Mutex
+	runs    map[[2]int]outcome // by year and day
+}
+
+func newDashboard(puzzles []aoc.Puzzle, timeout time.Duration, maxInput int64) *dashboard {
+	return &dashboard{puzzles: puzzles, timeout: timeout, maxInput: maxInput, runs: make(map[[2]int]outcome)}
+}
+
+func (d *dashboard) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", d.index)
+	mux.HandleFunc("GET /{year}", d.year)
+	mux.HandleFunc("GET /{year}/{day}", d.day)
+	mux.HandleFunc("GET /picture/{year}/{day}/{name}", d.picture)
+	mux.HandleFunc("POST /run/{year}", d.runYear)
+	mux.HandleFunc("POST /run/{year}/{day}", d.runDay)
+	mux.HandleFunc("POST /solve/{year}/{day}", d.solve)
+	return mux
+}
+
+// page is what every page has, for the top and bottom of it.
+type page struct {
+	Title string
+	Years []int
+}
+
+func (d *dashboard) page(title string) page {
+	var years []int
+	for _, p := range d.puzzles {
+		if !slices.Contains(years, p.Year) {
+			years = append(years, p.Year)
+		}
+	}
+	return page{Title: title, Years: years}
+}
+
+// partView is one part of a puzzle as the dashboard shows it.
+type partView struct {
+	Verdict          verdict
+	Answer, Accepted string
+}
+
+// Good and Bad say how to colour the verdict.
+func (v partView) Good() bool { return v.Verdict == pass }
+func (v partView) Bad() bool {
+	return v.Verdict == fail || v.Verdict == broken || v.Verdict == timedOut
+}
+
+// dayView is one puzzle as the dashboard shows it.
+type dayView struct {
+	Puzzle   aoc.Puzzle
+	URL      string // the puzzle on the site
+	Run      bool   // whether it has been run yet
+	Input    string
+	Parts    []partView
+	Timing   aoc.Timing
+	Duration time.Duration
+	Error    string
+}
+
+func (v dayView) Time() string {
+	if !v.Run {
+		return ""
+	}
+	return round(v.Duration)
+}
+
+func (d *dashboard) view(p aoc.Puzzle) dayView {
+	d.mu.Lock()
+	o, ran := d.runs[[2]int{p.Year, p.Day}]
+	d.mu.Unlock()
+
+	v := dayView{Puzzle: p, URL: fmt.Sprintf("%s/%d/day/%d", aoc.DefaultBaseURL, p.Year, p.Day), Run: ran}
+	if ran {
+		v.Input, v.Timing, v.Duration = o.input, o.timing, o.duration
+		if o.err != nil {
+			v.Error = strings.SplitN(solveError(p, o.timeout, o.err).Error(), "\n", 2)[0]
+		}
+	}
+	for part := 1; part <= 2; part++ {
+		accepted, err := p.Answer(part)
+		pv := partView{Accepted: accepted}
+		switch {
+		case err != nil:
+			pv.Verdict, pv.Accepted = broken, err.Error()
+		case !ran:
+			pv.Verdict = notRun
+		case o.input == "":
+			pv.Verdict = noInput
+		case errors.Is(o.err, context.DeadlineExceeded):
+			pv.Verdict = timedOut
+		case o.err != nil:
+			pv.Verdict = broken
+		case o.answers[part-1] == nil:
+			pv.Verdict = unsolved
+		case o.input == "generated":
+			pv.Verdict, pv.Answer = generated, aoc.FormatAnswer(o.answers[part-1])
+		default:
+			pv.Answer = aoc.FormatAnswer(o.answers[part-1])
+			switch {
+			case accepted == "":
+				pv.Verdict = missing
+			case pv.Answer == accepted:
+				pv.Verdict = pass
+			default:
+				pv.Verdict = fail
+			}
+		}
+		v.Parts = append(v.Parts, pv)
+	}
+	return v
+}
+
+// lookup finds the puzzle named by the request's path, writing a 404 if
+// there isn't one.
+func (d *dashboard) lookup(w http.ResponseWriter, r *http.Request) (aoc.Puzzle, bool) {
+	year, yerr := strconv.Atoi(r.PathValue("year"))
+	day, derr := strconv.Atoi(r.PathValue("day"))
+	if yerr == nil && derr == nil {
+		for _, p := range d.puzzles {
+			if p.Year == year && p.Day == day {
+				return p, true
+			}
+		}
+	}
+	http.NotFound(w, r)
+	return aoc.Puzzle{}, false
+}
+
+func (d *dashboard) index(w http.ResponseWriter, r *http.Request) {
+	pg := d.page("Advent of Code")
+	type yearView struct {
+		Year, Days, Verified int
+	}
+	var years []yearView
+	for _, year := range pg.Years {
+		y := yearView{Year: year}
+		for _, p := range d.puzzles {
+			if p.Year == year {
+				y.Days++
+				for _, part := range d.view(p).Parts {
+					if part.Good() {
+						y.Verified++
+					}
+				}
+			}
+		}
+		years = append(years, y)
+	}
+	d.render(w, "index", struct {
+		page
+		Summary []yearView
+	}{pg, years})
+}
+
+func (d *dashboard) year(w http.ResponseWriter, r *http.Request) {
+	year, err := strconv.Atoi(r.PathValue("year"))
+	pg := d.page(fmt.Sprint(year))
+	if err != nil || !slices.Contains(pg.Years, year) {
+		http.NotFound(w, r)
+		return
+	}
+	// a cell for each day of December up to Christmas, empty if it isn't
+	// done
+	days := make([]*dayView, 25)
+	for _, p := range d.puzzles {
+		if p.Year == year && p.Day >= 1 && p.Day <= 25 {
+			v := d.view(p)
+			days[p.Day-1] = &v
+		}
+	}
+	type cell struct {
+		Day  int
+		View *dayView
+	}
+	var cells []cell
+	for i, v := range days {
+		cells = append(cells, cell{i + 1, v})
+	}
+	d.render(w, "year", struct {
+		page
+		Year  int
+		Cells []cell
+	}{pg, year, cells})
+}
+
+func (d *dashboard) day(w http.ResponseWriter, r *http.Request) {
+	p, ok := d.lookup(w, r)
+	if !ok {
+		return
+	}
+	d.render(w, "day", struct {
+		page
+		dayView
+	}{d.page(p.String()), d.view(p)})
+}
+
+func (d *dashboard) picture(w http.ResponseWriter, r *http.Request) {
+	p, ok := d.lookup(w, r)
+	if !ok {
+		return
+	}
+	name := r.PathValue("name")
+	i := slices.IndexFunc(p.Pictures, func(pic render.Picture) bool { return pic.Name == name })
+	if i < 0 {
+		http.NotFound(w, r)
+		return
+	}
+	view := struct {
+		page
+		Puzzle aoc.Puzzle
+		Name   string
+		Input  string
+		Rows   [][]span
+		Error  string
+	}{page: d.page(fmt.Sprintf("%s: %s", p, name)), Puzzle: p, Name: name}
+
+	data, input, err := allInput(p)
+	var frame *render.Frame
+	if err == nil {
+		view.Input = input
+		frame, err = d.draw(p.Pictures[i], data)
+	}
+	if err != nil {
+		view.Error = solveError(p, d.timeout, err).Error()
+		d.render(w, "picture", view)
+		return
+	}
+	// ?format=png or svg for the picture as an image, 8 pixels to a cell
+	var b bytes.Buffer
+	var contentType string
+	switch r.URL.Query().Get("format") {
+	case "png":
+		err, contentType = render.PNG(&b, frame, 8), "image/png"
+	case "svg":
+		err, contentType = render.SVG(&b, frame, 8), "image/svg+xml"
+	default:
+		view.Rows = frameRows(frame)
+		d.render(w, "picture", view)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	b.WriteTo(w)
+}
+
+// draw draws a picture of the input as a run would solve it: one at a time
+// with the solvers, and given up on after the dashboard's timeout.
+func (d *dashboard) draw(pic render.Picture, input []byte) (*render.Frame, error) {
+	d.running.Lock()
+	defer d.running.Unlock()
+	ctx, cancel := withTimeout(d.timeout)
+	defer cancel()
+	frame, err := pic.Draw(ctx, bytes.NewReader(input))
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return frame, err
+}
+
+func (d *dashboard) runDay(w http.ResponseWriter, r *http.Request) {
+	p, ok := d.lookup(w, r)
+	if !ok {
+		return
+	}
+	d.run([]aoc.Puzzle{p})
+	http.Redirect(w, r, fmt.Sprintf("/%d/%d", p.Year, p.Day), http.StatusSeeOther)
+}
+
+func (d *dashboard) runYear(w http.ResponseWriter, r *http.Request) {
+	year, err := strconv.Atoi(r.PathValue("year"))
+	var puzzles []aoc.Puzzle
+	for _, p := range d.puzzles {
+		if err == nil && p.Year == year {
+			puzzles = append(puzzles, p)
+		}
+	}
+	if len(puzzles) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	d.run(puzzles)
+	http.Redirect(w, r, fmt.Sprintf("/%d", year), http.StatusSeeOther)
+}
+
+// run runs the puzzles a CPU's worth at a time and keeps how they went.
+func (d *dashboard) run(puzzles []aoc.Puzzle) {
+	d.running.Lock()
+	defer d.running.Unlock()
+
+	next := make(chan aoc.Puzzle)
+	var wg sync.WaitGroup
+	for range min(runtime.NumCPU(), len(puzzles)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range next {
+				o := runPuzzle(p, d.timeout)
+				d.mu.Lock()
+				d.runs[[2]int{p.Year, p.Day}] = o
+				d.mu.Unlock()
+			}
+		}()
+	}
+	for _, p := range puzzles {
+		next <- p
+	}
+	close(next)
+	wg.Wait()
+}
+
+func (d *dashboard) render(w http.ResponseWriter, name string, data any) {
+	var b bytes.Buffer
+	if err := pages.ExecuteTemplate(&b, name, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	b.WriteTo(w)
+}
+
+// span is a run of cells in a row of a picture that are the same colours.
+type span struct {
+	Text  string
+	Style template.CSS
+}
+
+// frameRows breaks each row of a frame into spans, for drawing as text.
+func frameRows(f *render.Frame) [][]span {
+	rows := make([][]span, f.Height)
+	for y := range rows {
+		for x := 0; x < f.Width; x++ {
+			c := f.At(x, y)
+			text := " "
+			if c.Rune != 0 {
+				text = string(c.Rune)
+			}
+			if x > 0 && f.At(x-1, y).Fg == c.Fg && f.At(x-1, y).Bg == c.Bg {
+				rows[y][len(rows[y])-1].Text += text
+			} else {
+				rows[y] = append(rows[y], span{text, cellStyle(c)})
+			}
+		}
+	}
+	return rows
+}
+
+func cellStyle(c render.Cell) template.CSS {
+	var style []string
+	if c.Fg.Set {
+		style = append(style, fmt.Sprintf("color:#%02x%02x%02x", c.Fg.R, c.Fg.G, c.Fg.B))
+	}
+	if c.Bg.Set {
+		style = append(style, fmt.Sprintf("background:#%02x%02x%02x", c.Bg.R, c.Bg.G, c.Bg.B))
+	}
+	return template.CSS(strings.Join(style, ";"))
+}
+
+var pages = template.Must(template.New("pages").Funcs(template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+	"mod": func(i, n int) int { return i % n },
+}).Parse(`
+{{define "top"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+nav a { margin-right: 1em; }
+table { border-collapse: collapse; }
+td, th { padding: 0.3em 0.8em; text-align: left; }
+.calendar td { border: 1px solid #ccc; vertical-align: top; width: 9em; height: 4em; }
+.good { color: #080; }
+.bad { color: #c00; font-weight: bold; }
+.missing { color: #bbb; }
+pre.picture { background: #000; line-height: 1.1; display: inline-block; padding: 0.5em; }
+</style>
+</head>
+<body>
+<nav><a href="/">Advent of Code</a>{{range .Years}}<a href="/{{.}}">{{.}}</a>{{end}}</nav>
+<h1>{{.Title}}</h1>
+{{end}}
+
+{{define "bottom"}}</body>
+</html>
+{{end}}
+
+{{define "parts"}}{{range $i, $part := .}}<span class="{{if $part.Good}}good{{else if $part.Bad}}bad{{end}}" title="part {{$i | inc}}: {{$part.Verdict}}">{{if $part.Good}}★{{else}}☆{{end}}</span>{{end}}{{end}}
+
+{{define "index"}}{{template "top" .}}
+<table>
+<tr><th>Year</th><th>Days</th><th>Verified parts</th></tr>
+{{range .Summary}}<tr><td><a href="/{{.Year}}">{{.Year}}</a></td><td>{{.Days}}</td><td>{{.Verified}}</td></tr>
+{{end}}</table>
+{{template "bottom" .}}{{end}}
+
+{{define "year"}}{{template "top" .}}
+<form method="post" action="/run/{{.Year}}"><button>Run every day</button></form>
+<table class="calendar">
+{{range $i, $cell := .Cells}}{{if eq (mod $i 5) 0}}<tr>{{end}}<td>
+<b>{{$cell.Day}}</b>
+{{with $cell.View}}<a href="/{{.Puzzle.Year}}/{{.Puzzle.Day}}">{{.Puzzle.Title}}</a><br>
+{{template "parts" .Parts}} {{.Time}}{{else}}<span class="missing">not done</span>{{end}}
+</td>{{if eq (mod $i 5) 4}}</tr>
+{{end}}{{end}}
+</table>
+<p>★ verified against the accepted answer, ☆ anything else; hover for which.</p>
+{{template "bottom" .}}{{end}}
+
+{{define "day"}}{{template "top" .}}
+<p><a href="{{.URL}}">The puzzle</a></p>
+<form method="post" action="/run/{{.Puzzle.Year}}/{{.Puzzle.Day}}"><button>Run</button></form>
+{{if .Error}}<p class="bad">{{.Error}}</p>{{end}}
+<table>
+<tr><th>Part</th><th>Answer</th><th>Accepted</th><th>Verdict</th></tr>
+{{range $i, $part := .Parts}}<tr><td>{{$i | inc}}</td><td>{{$part.Answer}}</td><td>{{$part.Accepted}}</td><td class="{{if $part.Good}}good{{else if $part.Bad}}bad{{end}}">{{$part.Verdict}}</td></tr>
+{{end}}</table>
+{{if .Run}}<p>Last run on {{.Input}}: {{.Time}} in all, parsing {{.Timing.Parse}}{{if .Timing.Split}}, part 1 {{.Timing.Part1}}, part 2 {{.Timing.Part2}}{{end}}.</p>{{end}}
+{{with .Puzzle.Pictures}}<h2>Pictures</h2>
+<ul>{{range .}}<li><a href="/picture/{{$.Puzzle.Year}}/{{$.Puzzle.Day}}/{{.Name}}">{{.Name}}</a></li>{{end}}</ul>{{end}}
+{{template "bottom" .}}{{end}}
+
+{{define "picture"}}{{template "top" .}}
+<p><a href="/{{.Puzzle.Year}}/{{.Puzzle.Day}}">Back to the puzzle</a></p>
+{{if .Error}}<p class="bad">{{.Error}}</p>{{else}}<p>Drawn from {{.Input}}, also as <a href="?format=png">PNG</a> and <a href="?format=svg">SVG</a>.</p>
+<pre class="picture">{{range .Rows}}{{range .}}<span style="{{.Style}}">{{.Text}}</span>{{end}}
+{{end}}</pre>{{end}}
+{{template "bottom" .}}{{end}}
+`))
+
+func serveCommand(args []string) error {
+	var addr string
+	var timeout time.Duration
+	var maxInput int64
+
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	fs.StringVar(&addr, "addr", "localhost:8080", "address to listen on")
+	fs.DurationVar(&timeout, "timeout", time.Minute, "give up on a puzzle that takes longer than this when it is run or solved; 0 for never")
+	fs.Int64Var(&maxInput, "max-input", 1<<20, "the most bytes of input that can be posted to /solve")
+	fs.BoolVar(&parse.Strict, "strict", false, "reject anything in the input the solver does not expect")
+	fs.Parse(args)
+
+	d := newDashboard(calendar.All(0), timeout, maxInput)
+	fmt.Fprintf(os.Stderr, "serving the dashboard on http://%s/\n", addr)
+	return http.ListenAndServe(addr, d.handler())
+}
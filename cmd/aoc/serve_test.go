@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/render"
+)
+
+func TestServe(t *testing.T) {
+	input := func(*rand.Rand, int) []byte { return []byte("ab\n") }
+	draw := func(context.Context, io.Reader) (*render.Frame, error) {
+		f := render.NewFrame(2, 1)
+		f.Set(0, 0, render.Cell{Rune: '#', Fg: render.Yellow})
+		f.Set(1, 0, render.Cell{Rune: '.', Fg: render.Grey})
+		return f, nil
+	}
+	puzzles := []aoc.Puzzle{
+		{Year: 2099, Day: 1, Title: "Counting Sheep", Generate: input, Pictures: []render.Picture{
+			{Name: "flock", Draw: draw},
+			{Name: "field", Draw: func(context.Context, io.Reader) (*render.Frame, error) { return render.NewFrame(0, 0), nil }},
+			{Name: "fold", Draw: func(ctx context.Context, _ io.Reader) (*render.Frame, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}},
+		},
+			Solve: func(context.Context, io.Reader) (any, any, error) { return 42, nil, nil }},
+		{Year: 2099, Day: 2, Title: "Off the Map", Generate: input,
+			Solve: func(context.Context, io.Reader) (any, any, error) { panic("off the map") }},
+	}
+	server := httptest.NewServer(newDashboard(puzzles, 100*time.Millisecond, 1<<20).handler())
+	defer server.Close()
+
+	tests := []struct {
+		method, path string
+		status       int
+		want         []string
+	}{
+		{"GET", "/", 200, []string{`<a href="/2099">2099</a>`}},
+		{"GET", "/2099", 200, []string{"Counting Sheep", "Off the Map", "not done", "Run every day"}},
+		{"GET", "/2099/1", 200, []string{"Counting Sheep", "not run", `/picture/2099/1/flock`}},
+		{"POST", "/run/2099", 200, []string{"Counting Sheep"}}, // redirected back to the year
+		{"GET", "/2099/1", 200, []string{"<td>42</td>", "unchecked", "unsolved", "Last run on generated"}},
+		{"GET", "/2099/2", 200, []string{"panic: off the map", "error"}},
+		{"POST", "/run/2099/2", 200, []string{"Off the Map"}},
+		{"GET", "/picture/2099/1/flock", 200, []string{
+			`<span style="color:#ffff00">#</span><span style="color:#808080">.</span>`,
+			"Drawn from generated",
+		}},
+		{"GET", "/picture/2099/1/flock?format=svg", 200, []string{`<rect x="0" y="0" width="8" height="8" fill="#ffff00"/>`}},
+		{"GET", "/picture/2099/1/flock?format=png", 200, []string{"\x89PNG"}},
+		{"GET", "/picture/2099/1/herd", 404, nil},
+		{"GET", "/picture/2099/1/field?format=png", 500, nil}, // too small to be a PNG
+		{"GET", "/picture/2099/1/fold", 200, []string{"gave up after 100ms"}},
+		{"GET", "/2099/3", 404, nil},
+		{"GET", "/1999", 404, nil},
+		{"POST", "/run/1999", 404, nil},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(tt.method, server.URL+tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s %s: status %d, want %d", tt.method, tt.path, resp.StatusCode, tt.status)
+			continue
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(string(body), want) {
+				t.Errorf("%s %s: no %q in\n%s", tt.method, tt.path, want, body)
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/parse"
+	"github.com/havill/AdventOfCode/shrink"
+)
+
+func shrinkCommand(args []string) error {
+	var pf puzzleFlags
+	var lf logFlags
+	var input, output string
+
+	fs := flag.NewFlagSet("shrink", flag.ExitOnError)
+	pf.register(fs)
+	lf.register(fs)
+	fs.BoolVar(&parse.Strict, "strict", true, "only keep inputs the solver reads without complaint")
+	fs.StringVar(&input, "input", "", "input that shows the problem, or - for standard input (default <year>/day-<dd>/input.txt)")
+	fs.StringVar(&output, "o", "", "where to write the small input (default <year>/day-<dd>/testdata/shrunk/<sha256>)")
+	fs.Parse(args)
+	lf.apply()
+
+	p, err := pf.lookup()
+	if err != nil {
+		return err
+	}
+	f, err := openInput(p, input)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	problem := p.Check(data)
+	if problem == nil {
+		if p.Reference == nil {
+			return fmt.Errorf("%d day %d doesn't panic on that input, and has no reference to compare with", p.Year, p.Day)
+		}
+		return fmt.Errorf("%d day %d doesn't panic on that input, and agrees with its reference", p.Year, p.Day)
+	}
+	fmt.Fprintf(os.Stderr, "%d day %d: %v\n", p.Year, p.Day, problem)
+
+	// a panic has to stay a panic, and a wrong answer a wrong answer
+	var pe *aoc.PanicError
+	panicked := errors.As(problem, &pe)
+	small := shrink.Minimize(data, func(input []byte) bool {
+		err := p.Check(input)
+		return err != nil && errors.As(err, &pe) == panicked
+	})
+	problem = p.Check(small)
+
+	if output == "" {
+		sum := sha256.Sum256(small)
+		output = filepath.Join(p.Dir(), "testdata", "shrunk", hex.EncodeToString(sum[:8]))
+	}
+	if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(output, small, 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%d bytes down to %d: %v\n", len(data), len(small), problem)
+	fmt.Println(output)
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/parse"
+)
+
+// solved is what POST /solve/{year}/{day} answers with.
+type solved struct {
+	Results  []result   `json:"results,omitempty"` // as aoc run -format json prints them
+	Warnings []string   `json:"warnings,omitempty"`
+	Error    *solveFail `json:"error,omitempty"`
+}
+
+// solveFail is why there are no results, and where in the input it went
+// wrong when the parser says.
+type solveFail struct {
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+// solve runs a puzzle on the body of the request the same way aoc run does,
+// and answers with JSON. The timeout counts from when the request arrived.
+func (d *dashboard) solve(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if d.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+	}
+	p, ok := d.lookup(w, r)
+	if !ok {
+		return
+	}
+	input, err := io.ReadAll(http.MaxBytesReader(w, r.Body, d.maxInput))
+	var tooBig *http.MaxBytesError
+	if errors.As(err, &tooBig) {
+		writeSolved(w, http.StatusRequestEntityTooLarge, solved{Error: &solveFail{
+			Message: fmt.Sprintf("the input is over %d bytes", tooBig.Limit),
+		}})
+		return
+	} else if err != nil {
+		writeSolved(w, http.StatusBadRequest, solved{Error: &solveFail{Message: err.Error()}})
+		return
+	}
+
+	// The warnings are this run's alone, however many others are running;
+	// Run waits for the solver to stop, so none are added after it returns.
+	var warnings bytes.Buffer
+	ctx = aoc.WithLogHandler(ctx, slog.NewTextHandler(&warnings, &slog.HandlerOptions{
+		Level: slog.LevelWarn,
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	part1, part2, t, err := p.Run(ctx, input)
+
+	var s solved
+	for _, line := range strings.Split(warnings.String(), "\n") {
+		if line != "" {
+			s.Warnings = append(s.Warnings, line)
+		}
+	}
+	status := http.StatusOK
+	var pe *aoc.PanicError
+	var bad *parse.Error
+	switch {
+	case err == nil:
+		s.Results = results(p, 0, []any{part1, part2}, t, input)
+	case errors.Is(err, context.DeadlineExceeded):
+		status = http.StatusGatewayTimeout
+		s.Error = &solveFail{Message: fmt.Sprintf("gave up after %v", d.timeout)}
+	case errors.As(err, &pe):
+		status = http.StatusInternalServerError
+		s.Error = &solveFail{Message: err.Error()}
+	case errors.As(err, &bad):
+		status = http.StatusUnprocessableEntity
+		s.Error = &solveFail{Message: bad.Err.Error(), Line: bad.Line, Column: bad.Column}
+	default:
+		status = http.StatusUnprocessableEntity
+		s.Error = &solveFail{Message: err.Error()}
+	}
+	writeSolved(w, status, s)
+}
+
+func writeSolved(w http.ResponseWriter, status int, s solved) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(s)
+}
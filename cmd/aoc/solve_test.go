@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/parse"
+)
+
+func TestSolve(t *testing.T) {
+	log := aoc.Logger("parser")
+	// sum adds up the numbers on each line, skipping the ones that start
+	// with #
+	sum := func(ctx context.Context, r io.Reader) (any, any, error) {
+		lines, err := parse.Lines(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		total := 0
+		for _, line := range lines {
+			if strings.HasPrefix(line.Text, "#") {
+				if err := parse.Tolerate(ctx, log, line.Errorf("a comment")); err != nil {
+					return nil, nil, err
+				}
+				continue
+			}
+			n, err := line.Int()
+			if err != nil {
+				return nil, nil, err
+			}
+			total += n
+		}
+		return total, nil, nil
+	}
+	puzzles := []aoc.Puzzle{
+		{Year: 2099, Day: 1, Solve: sum},
+		{Year: 2099, Day: 2, Solve: func(context.Context, io.Reader) (any, any, error) { panic("off the map") }},
+		{Year: 2099, Day: 3, Solve: func(ctx context.Context, _ io.Reader) (any, any, error) {
+			<-ctx.Done()
+			return nil, nil, ctx.Err()
+		}},
+	}
+	server := httptest.NewServer(newDashboard(puzzles, 50*time.Millisecond, 16).handler())
+	defer server.Close()
+
+	tests := []struct {
+		path, input string
+		status      int
+		want        string
+	}{
+		{"/solve/2099/1", "1\n2\n", 200, `"answer":"3"`},
+		{"/solve/2099/1", "1\n2\n", 200, `"answer":null`},
+		{"/solve/2099/1", "1\n2\n", 200, `"input_sha256":"`},
+		{"/solve/2099/1", "1\n# two\n", 200, `"warnings":["level=WARN msg=\"skipping bad input\" sys=parser err=\"line 2, column 1: a comment\""]`},
+		{"/solve/2099/1", "1\nx\n", 422, `"error":{"message":"`},
+		{"/solve/2099/1", "1\nx\n", 422, `"line":2,"column":1}`},
+		{"/solve/2099/1", strings.Repeat("1\n", 9), 413, `"error":{"message":"the input is over 16 bytes"}`},
+		{"/solve/2099/2", "1\n", 500, `panic: off the map`},
+		{"/solve/2099/3", "1\n", 504, `"error":{"message":"gave up after 50ms"}`},
+		{"/solve/2099/4", "1\n", 404, ""},
+	}
+	for _, tt := range tests {
+		resp, err := server.Client().Post(server.URL+tt.path, "text/plain", strings.NewReader(tt.input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s %q: status %d, want %d: %s", tt.path, tt.input, resp.StatusCode, tt.status, body)
+			continue
+		}
+		if tt.status == 404 {
+			continue
+		}
+		if !json.Valid(body) {
+			t.Errorf("%s %q: not JSON: %s", tt.path, tt.input, body)
+		}
+		if !strings.Contains(string(body), tt.want) {
+			t.Errorf("%s %q: no %s in %s", tt.path, tt.input, tt.want, body)
+		}
+	}
+
+	// solves side by side each get their own warnings, and leave serve's
+	// own logging alone
+	var logged bytes.Buffer
+	aoc.SetLogging(&logged, slog.LevelDebug)
+	t.Cleanup(func() { aoc.SetLogging(os.Stderr, aoc.Quiet) })
+	var wg sync.WaitGroup
+	got := make([]solved, 8)
+	for i := range got {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			input := strings.Repeat("#\n", i%3+1)
+			resp, err := server.Client().Post(server.URL+"/solve/2099/1", "text/plain", strings.NewReader(input))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			json.NewDecoder(resp.Body).Decode(&got[i])
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+	for i, s := range got {
+		if len(s.Warnings) != i%3+1 {
+			t.Errorf("%d comments gave the warnings %q", i%3+1, s.Warnings)
+		}
+	}
+	if w, level := aoc.Logging(); w != &logged || level != slog.LevelDebug {
+		t.Errorf("logging to %v at %v after solving, want it left alone", w, level)
+	}
+	if n := strings.Count(logged.String(), "skipping bad input"); n != 15 {
+		t.Errorf("serve logged %d warnings, want all 15", n)
+	}
+}
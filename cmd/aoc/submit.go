@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/calendar"
+)
+
+// wrongWait is how long to hold off after a wrong answer when the site did
+// not say.
+const wrongWait = time.Minute
+
+// backoff remembers between runs when the site will take an answer again, so
+// that we stop ourselves rather than be told to wait.
+type backoff struct {
+	path string
+}
+
+func defaultBackoff() (backoff, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return backoff{}, err
+	}
+	return backoff{filepath.Join(dir, "aoc", "submit-wait")}, nil
+}
+
+// until is when the next answer may be given, or the zero time if now will do.
+func (b backoff) until() time.Time {
+	data, err := os.ReadFile(b.path)
+	if err != nil {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+func (b backoff) set(t time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(b.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(b.path, []byte(t.Format(time.RFC3339)+"\n"), 0o644)
+}
+
+// submit gives an answer to the site unless the part was solved already or
+// the site is still waiting out an earlier answer. A correct answer goes into
+// the answer store, and a wrong one starts the wait for the next.
+func submit(c *aoc.Client, b backoff, p aoc.Puzzle, part int, answer string) (aoc.Verdict, error) {
+	known, err := p.Answer(part)
+	if err != nil {
+		return aoc.Verdict{}, err
+	}
+	if known != "" {
+		return aoc.Verdict{Outcome: aoc.AlreadySolved, Message: "the accepted answer was " + known}, nil
+	}
+	if until := b.until(); time.Now().Before(until) {
+		return aoc.Verdict{}, fmt.Errorf("too soon after the last answer, try again in %v", time.Until(until).Round(time.Second))
+	}
+
+	v, err := c.Submit(p.Year, p.Day, part, answer)
+	if err != nil {
+		return v, err
+	}
+	switch v.Outcome {
+	case aoc.Correct:
+		err = p.SaveAnswer(part, answer)
+	case aoc.Wait:
+		err = b.set(time.Now().Add(v.Wait))
+	case aoc.TooHigh, aoc.TooLow, aoc.Wrong:
+		wait := v.Wait
+		if wait == 0 {
+			wait = wrongWait
+		}
+		err = b.set(time.Now().Add(wait))
+	}
+	return v, err
+}
+
+// solvedAnswer runs the solver on its input for the answer to a part.
+func solvedAnswer(p aoc.Puzzle, part int, input string) (string, error) {
+	f, err := openInput(p, input)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	part1, part2, err := p.Solve(context.Background(), f)
+	if err != nil {
+		return "", fmt.Errorf("%d day %d: %w", p.Year, p.Day, err)
+	}
+	answer := aoc.FormatAnswer([]any{part1, part2}[part-1])
+	if answer == "" {
+		return "", fmt.Errorf("%d day %d part %d is not solved", p.Year, p.Day, part)
+	}
+	return answer, nil
+}
+
+// stdinAnswer reads the answer printed by 'aoc run -part N'.
+func stdinAnswer() (string, error) {
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			return line, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", errors.New("no answer on standard input")
+}
+
+func submitCommand(args []string) error {
+	var pf puzzleFlags
+	var sf siteFlags
+	var part int
+	var input string
+
+	fs := flag.NewFlagSet("submit", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: aoc submit -year Y -day D -part N [flags] [answer | -]")
+		fmt.Fprintln(fs.Output(), "\nWith no answer the solver is run for it; - reads it from standard input.")
+		fs.PrintDefaults()
+	}
+	pf.register(fs)
+	sf.register(fs)
+	fs.IntVar(&part, "part", 0, "which part the answer is for, 1 or 2")
+	fs.StringVar(&input, "input", "", "puzzle input to solve when no answer is given (default <year>/day-<dd>/input.txt)")
+	fs.Parse(args)
+
+	if pf.year == 0 || pf.day < 1 || pf.day > 25 {
+		return fmt.Errorf("-year and a -day of 1 to 25 are required")
+	}
+	if part != 1 && part != 2 {
+		return fmt.Errorf("-part must be 1 or 2")
+	}
+	p, solved := calendar.Lookup(pf.year, pf.day)
+	if !solved {
+		p = aoc.Puzzle{Year: pf.year, Day: pf.day}
+	}
+
+	var answer string
+	var err error
+	switch fs.Arg(0) {
+	case "":
+		if !solved {
+			return fmt.Errorf("no solution for %d day %d, so give the answer", pf.year, pf.day)
+		}
+		answer, err = solvedAnswer(p, part, input)
+	case "-":
+		answer, err = stdinAnswer()
+	default:
+		answer = strings.TrimSpace(fs.Arg(0))
+	}
+	if err != nil {
+		return err
+	}
+
+	c, err := sf.client()
+	if err != nil {
+		return err
+	}
+	b, err := defaultBackoff()
+	if err != nil {
+		return err
+	}
+	v, err := submit(c, b, p, part, answer)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d day %d part %d: %s is %s\n", p.Year, p.Day, part, answer, v.Outcome)
+	switch v.Outcome {
+	case aoc.Correct:
+		fmt.Println("recorded in", p.AnswerPath(part))
+	case aoc.AlreadySolved:
+		fmt.Println(v.Message)
+	case aoc.Wait:
+		return fmt.Errorf("the site wants %v before the next answer", v.Wait)
+	case aoc.Unrecognized:
+		return fmt.Errorf("could not make sense of the reply: %s", v.Message)
+	default:
+		return fmt.Errorf("wrong answer, wait %v before the next", max(v.Wait, wrongWait))
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/havill/AdventOfCode/aoc"
+)
+
+// inTempDir runs the rest of the test in an empty directory, so that the
+// answer store is the test's own.
+func inTempDir(t *testing.T) {
+	t.Helper()
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(dir) })
+}
+
+// standIn answers every submission with reply and counts them.
+func standIn(t *testing.T, reply string) (*aoc.Client, *int) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("<main><article><p>" + reply + "</p></article></main>"))
+	}))
+	t.Cleanup(srv.Close)
+	c := aoc.NewClient(srv.URL, "secret")
+	c.Interval = 0
+	return c, &requests
+}
+
+func TestSubmitCorrect(t *testing.T) {
+	inTempDir(t)
+	c, requests := standIn(t, "That's the right answer!")
+	b := backoff{filepath.Join(t.TempDir(), "submit-wait")}
+	p := aoc.Puzzle{Year: 2023, Day: 1}
+
+	v, err := submit(c, b, p, 1, "142")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Outcome != aoc.Correct {
+		t.Fatalf("outcome = %v, want correct", v.Outcome)
+	}
+	if got, _ := p.Answer(1); got != "142" {
+		t.Errorf("recorded answer = %q, want 142", got)
+	}
+
+	// a part with a recorded answer is not sent again
+	v, err = submit(c, b, p, 1, "142")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Outcome != aoc.AlreadySolved || *requests != 1 {
+		t.Errorf("second submit: outcome %v after %d requests, want already solved after 1", v.Outcome, *requests)
+	}
+}
+
+func TestSubmitBackoff(t *testing.T) {
+	inTempDir(t)
+	c, requests := standIn(t, "That's not the right answer; your answer is too low.  Please wait one minute before trying again.")
+	b := backoff{filepath.Join(t.TempDir(), "submit-wait")}
+	p := aoc.Puzzle{Year: 2023, Day: 1}
+
+	v, err := submit(c, b, p, 1, "100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Outcome != aoc.TooLow {
+		t.Fatalf("outcome = %v, want too low", v.Outcome)
+	}
+	if wait := time.Until(b.until()); wait < 50*time.Second || wait > time.Minute {
+		t.Errorf("backoff is %v, want about a minute", wait)
+	}
+	if got, _ := p.Answer(1); got != "" {
+		t.Errorf("wrong answer was recorded as %q", got)
+	}
+
+	if _, err := submit(c, b, p, 1, "200"); err == nil {
+		t.Error("submit during the backoff succeeded")
+	}
+	if *requests != 1 {
+		t.Errorf("server was asked %d times, want 1", *requests)
+	}
+}
+
+func TestSubmitWait(t *testing.T) {
+	inTempDir(t)
+	c, _ := standIn(t, "You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 2m 30s left to wait.")
+	b := backoff{filepath.Join(t.TempDir(), "submit-wait")}
+
+	v, err := submit(c, b, aoc.Puzzle{Year: 2023, Day: 1}, 2, "281")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Outcome != aoc.Wait || v.Wait != 150*time.Second {
+		t.Errorf("got %v, wait %v; want wait, 2m30s", v.Outcome, v.Wait)
+	}
+	if wait := time.Until(b.until()); wait < 140*time.Second {
+		t.Errorf("backoff is %v, want about 2m30s", wait)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/havill/AdventOfCode/aoc"
+	"github.com/havill/AdventOfCode/calendar"
+	"github.com/havill/AdventOfCode/parse"
+)
+
+type verdict string
+
+const (
+	pass     verdict = "pass"
+	fail     verdict = "FAIL"
+	missing  verdict = "missing"  // no accepted answer has been recorded
+	unsolved verdict = "unsolved" // the solver has no answer for this part
+	noInput  verdict = "no input"
+	broken   verdict = "error"
+	timedOut verdict = "TIMEOUT" // it took longer than -timeout
+)
+
+type check struct {
+	puzzle    aoc.Puzzle
+	part      int
+	verdict   verdict
+	got, want string
+}
+
+// verify runs a puzzle on its own input and compares both parts with the
+// answers that were accepted, giving up after timeout unless it is 0.
+func verify(p aoc.Puzzle, timeout time.Duration) []check {
+	checks := []check{{puzzle: p, part: 1}, {puzzle: p, part: 2}}
+
+	input, err := os.ReadFile(p.InputPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		for i := range checks {
+			checks[i].verdict = noInput
+		}
+		return checks
+	}
+	if err != nil {
+		for i := range checks {
+			checks[i].verdict, checks[i].got = broken, err.Error()
+		}
+		return checks
+	}
+
+	ctx, cancel := withTimeout(timeout)
+	defer cancel()
+	part1, part2, _, err := p.Run(ctx, input)
+	if errors.Is(err, context.DeadlineExceeded) {
+		for i := range checks {
+			checks[i].verdict, checks[i].got = timedOut, fmt.Sprintf("over %v", timeout)
+			checks[i].want, _ = p.Answer(checks[i].part)
+		}
+		return checks
+	}
+	answers := []any{part1, part2}
+	for i := range checks {
+		c := &checks[i]
+		c.got = aoc.FormatAnswer(answers[i])
+		want, ansErr := p.Answer(c.part)
+		c.want = want
+		switch {
+		case err != nil:
+			c.verdict, c.got = broken, err.Error()
+		case ansErr != nil:
+			c.verdict, c.got = broken, ansErr.Error()
+		case c.got == "":
+			c.verdict = unsolved
+		case c.want == "":
+			c.verdict = missing
+		case c.got == c.want:
+			c.verdict = pass
+		default:
+			c.verdict = fail
+		}
+	}
+	return checks
+}
+
+func verifyCommand(args []string) error {
+	var pf puzzleFlags
+	var lf logFlags
+	var timeout time.Duration
+
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	pf.register(fs)
+	lf.register(fs)
+	fs.BoolVar(&parse.Strict, "strict", false, "reject anything in the input the solver does not expect")
+	fs.DurationVar(&timeout, "timeout", 0, "give up on any puzzle that takes longer than this, e.g. 1m; 0 for never")
+	fs.Parse(args)
+	lf.apply()
+
+	puzzles := calendar.All(pf.year)
+	if pf.day != 0 {
+		p, err := pf.lookup()
+		if err != nil {
+			return err
+		}
+		puzzles = []aoc.Puzzle{p}
+	}
+
+	var checks []check
+	for _, p := range puzzles {
+		checks = append(checks, verify(p, timeout)...)
+	}
+
+	failures := 0
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "YEAR\tDAY\tPART\tSTATUS\tGOT\tWANT")
+	for _, c := range checks {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%s\t%s\t%s\n", c.puzzle.Year, c.puzzle.Day, c.part, c.verdict, c.got, c.want)
+		if c.verdict == fail || c.verdict == broken || c.verdict == timedOut {
+			failures++
+		}
+	}
+	tw.Flush()
+
+	if failures > 0 {
+		return fmt.Errorf("%d of %d answers did not verify", failures, len(checks))
+	}
+	return nil
+}
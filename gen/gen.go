@@ -0,0 +1,65 @@
+// Package gen has the pieces the days' input generators share. Everything
+// takes its randomness from the caller, so the same seed makes the same
+// input.
+package gen
+
+import (
+	"bytes"
+	"math/rand/v2"
+)
+
+// Grid writes a width by height grid, one row per line, with each cell
+// given by cell.
+func Grid(width, height int, cell func(x, y int) byte) []byte {
+	var b bytes.Buffer
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			b.WriteByte(cell(x, y))
+		}
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
+
+// Pick chooses one of the bytes of choices. A byte given more than once is
+// chosen that much more often.
+func Pick(rng *rand.Rand, choices string) byte {
+	return choices[rng.IntN(len(choices))]
+}
+
+// Word makes a word of n bytes picked from alphabet.
+func Word(rng *rand.Rand, n int, alphabet string) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = Pick(rng, alphabet)
+	}
+	return string(b)
+}
+
+// Words makes count different words of n bytes picked from alphabet, none of
+// them one of taken. There have to be enough words to go round.
+func Words(rng *rand.Rand, count, n int, alphabet string, taken ...string) []string {
+	seen := make(map[string]bool)
+	for _, w := range taken {
+		seen[w] = true
+	}
+	words := make([]string, 0, count)
+	for len(words) < count {
+		w := Word(rng, n, alphabet)
+		if !seen[w] {
+			seen[w] = true
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// Between returns a number from lo to hi, both included.
+func Between(rng *rand.Rand, lo, hi int) int {
+	return lo + rng.IntN(hi-lo+1)
+}
+
+// Clamp keeps size from lo to hi.
+func Clamp(size, lo, hi int) int {
+	return min(max(size, lo), hi)
+}
@@ -0,0 +1,31 @@
+// Package gentest checks a solver against a slower one that is easier to
+// trust, on inputs made up by a day's generator. The slow one stays as the
+// oracle, so that every later speedup is checked too.
+package gentest
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/havill/AdventOfCode/aoc"
+)
+
+// Compare runs fast and slow on an input from generate for each seed from 1
+// to seeds, all of the given size, and fails at the first one where they
+// disagree, showing the seed and the input. The two have to reject the
+// same inputs as well.
+func Compare[T comparable](t *testing.T, generate aoc.Generator, size, seeds int, fast, slow func(io.Reader) (T, error)) {
+	t.Helper()
+	for seed := uint64(1); seed <= uint64(seeds); seed++ {
+		input := generate.Make(seed, size)
+		want, slowErr := slow(bytes.NewReader(input))
+		got, fastErr := fast(bytes.NewReader(input))
+		switch {
+		case (slowErr == nil) != (fastErr == nil):
+			t.Fatalf("seed %d, size %d: slow error %v, fast error %v, for\n%s", seed, size, slowErr, fastErr, input)
+		case slowErr == nil && got != want:
+			t.Fatalf("seed %d, size %d: got %v, want %v, for\n%s", seed, size, got, want, input)
+		}
+	}
+}
@@ -0,0 +1,5 @@
+module github.com/havill/AdventOfCode
+
+go 1.23.0
+
+require github.com/mxschmitt/golang-combinations v1.2.0
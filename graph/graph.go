@@ -0,0 +1,445 @@
+// Package graph is a directed or undirected graph with weighted edges,
+// for the days whose input is a network of things that lead to each other.
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrCycle is returned by TopoSort when the graph has a cycle.
+var ErrCycle = errors.New("graph has a cycle")
+
+type Edge[N comparable] struct {
+	From, To N
+	Weight   int
+}
+
+type Graph[N comparable] struct {
+	directed bool
+	order    map[N]int // when each node was added, to keep results stable
+	nodes    []N
+	out      map[N]map[N]int
+	in       map[N]map[N]int // the same maps as out when undirected
+}
+
+func New[N comparable](directed bool) *Graph[N] {
+	g := &Graph[N]{directed: directed, order: map[N]int{}, out: map[N]map[N]int{}}
+	g.in = g.out
+	if directed {
+		g.in = map[N]map[N]int{}
+	}
+	return g
+}
+
+func (g *Graph[N]) Directed() bool { return g.directed }
+
+// Len is the number of nodes.
+func (g *Graph[N]) Len() int { return len(g.order) }
+
+// AddNode adds n if it is not already in the graph.
+func (g *Graph[N]) AddNode(n N) {
+	if _, ok := g.order[n]; ok {
+		return
+	}
+	g.order[n] = len(g.nodes)
+	g.nodes = append(g.nodes, n)
+	g.out[n] = map[N]int{}
+	if g.directed {
+		g.in[n] = map[N]int{}
+	}
+}
+
+// AddEdge adds an edge of weight 1.
+func (g *Graph[N]) AddEdge(from, to N) {
+	g.AddWeightedEdge(from, to, 1)
+}
+
+// AddWeightedEdge adds an edge, adding its nodes too if need be. Adding an
+// edge that is already there changes its weight.
+func (g *Graph[N]) AddWeightedEdge(from, to N, weight int) {
+	g.AddNode(from)
+	g.AddNode(to)
+	g.out[from][to] = weight
+	g.in[to][from] = weight
+}
+
+func (g *Graph[N]) RemoveEdge(from, to N) {
+	if _, ok := g.order[from]; !ok {
+		return
+	}
+	delete(g.out[from], to)
+	if _, ok := g.order[to]; ok {
+		delete(g.in[to], from)
+	}
+}
+
+// RemoveNode removes n and every edge to or from it.
+func (g *Graph[N]) RemoveNode(n N) {
+	i, ok := g.order[n]
+	if !ok {
+		return
+	}
+	for m := range g.out[n] {
+		delete(g.in[m], n)
+	}
+	for m := range g.in[n] {
+		delete(g.out[m], n)
+	}
+	delete(g.out, n)
+	delete(g.in, n)
+	delete(g.order, n)
+	g.nodes = append(g.nodes[:i], g.nodes[i+1:]...)
+	for j := i; j < len(g.nodes); j++ {
+		g.order[g.nodes[j]] = j
+	}
+}
+
+func (g *Graph[N]) HasNode(n N) bool {
+	_, ok := g.order[n]
+	return ok
+}
+
+func (g *Graph[N]) HasEdge(from, to N) bool {
+	_, ok := g.out[from][to]
+	return ok
+}
+
+// Weight returns the weight of an edge, and false if there is no such edge.
+func (g *Graph[N]) Weight(from, to N) (int, bool) {
+	w, ok := g.out[from][to]
+	return w, ok
+}
+
+// Nodes lists the nodes in the order they were added.
+func (g *Graph[N]) Nodes() []N {
+	return append([]N(nil), g.nodes...)
+}
+
+func (g *Graph[N]) sorted(set map[N]int) []N {
+	list := make([]N, 0, len(set))
+	for n := range set {
+		list = append(list, n)
+	}
+	sort.Slice(list, func(i, j int) bool { return g.order[list[i]] < g.order[list[j]] })
+	return list
+}
+
+// Neighbors lists the nodes that n has an edge to.
+func (g *Graph[N]) Neighbors(n N) []N { return g.sorted(g.out[n]) }
+
+// Predecessors lists the nodes that have an edge to n. In an undirected graph
+// they are the same as its neighbors.
+func (g *Graph[N]) Predecessors(n N) []N { return g.sorted(g.in[n]) }
+
+// Edges lists every edge, once each even when the graph is undirected.
+func (g *Graph[N]) Edges() []Edge[N] {
+	var edges []Edge[N]
+	for _, from := range g.nodes {
+		for _, to := range g.Neighbors(from) {
+			if g.directed || g.order[from] <= g.order[to] {
+				edges = append(edges, Edge[N]{From: from, To: to, Weight: g.out[from][to]})
+			}
+		}
+	}
+	return edges
+}
+
+func (g *Graph[N]) EdgeCount() int {
+	count := 0
+	for from, tos := range g.out {
+		for to := range tos {
+			if g.directed || g.order[from] <= g.order[to] {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func (g *Graph[N]) Clone() *Graph[N] {
+	clone := New[N](g.directed)
+	clone.nodes = append(clone.nodes, g.nodes...)
+	for n, i := range g.order {
+		clone.order[n] = i
+		clone.out[n] = maps.Clone(g.out[n])
+		if g.directed {
+			clone.in[n] = maps.Clone(g.in[n])
+		}
+	}
+	return clone
+}
+
+// Subgraph is the graph of only the given nodes and the edges between them.
+func (g *Graph[N]) Subgraph(nodes []N) *Graph[N] {
+	sub := New[N](g.directed)
+	for _, n := range nodes {
+		if g.HasNode(n) {
+			sub.AddNode(n)
+		}
+	}
+	for _, from := range sub.nodes {
+		for to, w := range g.out[from] {
+			if sub.HasNode(to) {
+				sub.AddWeightedEdge(from, to, w)
+			}
+		}
+	}
+	return sub
+}
+
+// DFS visits every node reachable from start, depth first, without
+// recursing so that long chains cannot overflow the stack.
+func (g *Graph[N]) DFS(start N) iter.Seq[N] {
+	return func(yield func(N) bool) {
+		if !g.HasNode(start) {
+			return
+		}
+		visited := map[N]bool{}
+		stack := []N{start}
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if visited[n] {
+				continue
+			}
+			visited[n] = true
+			if !yield(n) {
+				return
+			}
+			next := g.Neighbors(n)
+			for i := len(next) - 1; i >= 0; i-- { // so the first neighbor is visited first
+				if !visited[next[i]] {
+					stack = append(stack, next[i])
+				}
+			}
+		}
+	}
+}
+
+// BFS visits every node reachable from start, nearest first.
+func (g *Graph[N]) BFS(start N) iter.Seq[N] {
+	return func(yield func(N) bool) {
+		if !g.HasNode(start) {
+			return
+		}
+		visited := map[N]bool{start: true}
+		queue := []N{start}
+		for len(queue) > 0 {
+			n := queue[0]
+			queue = queue[1:]
+			if !yield(n) {
+				return
+			}
+			for _, m := range g.Neighbors(n) {
+				if !visited[m] {
+					visited[m] = true
+					queue = append(queue, m)
+				}
+			}
+		}
+	}
+}
+
+// Reachable reports whether there is a path from one node to another.
+func (g *Graph[N]) Reachable(from, to N) bool {
+	for n := range g.DFS(from) {
+		if n == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Components groups the nodes that are connected to each other. Edge
+// directions are ignored, so a directed graph gives its weakly connected
+// components.
+func (g *Graph[N]) Components() [][]N {
+	seen := map[N]bool{}
+	var components [][]N
+	for _, start := range g.nodes {
+		if seen[start] {
+			continue
+		}
+		seen[start] = true
+		component := []N{}
+		stack := []N{start}
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			component = append(component, n)
+			for _, links := range []map[N]int{g.out[n], g.in[n]} {
+				for m := range links {
+					if !seen[m] {
+						seen[m] = true
+						stack = append(stack, m)
+					}
+				}
+			}
+		}
+		sort.Slice(component, func(i, j int) bool { return g.order[component[i]] < g.order[component[j]] })
+		components = append(components, component)
+	}
+	return components
+}
+
+// TopoSort orders the nodes of a directed graph so that every edge goes
+// forward. Nodes that could go in either order keep the order they were
+// added in.
+func (g *Graph[N]) TopoSort() ([]N, error) {
+	if !g.directed {
+		return nil, errors.New("cannot sort an undirected graph")
+	}
+	indegree := make(map[N]int, len(g.nodes))
+	for _, n := range g.nodes {
+		indegree[n] = len(g.in[n])
+	}
+	var ready, sorted []N
+	for _, n := range g.nodes {
+		if indegree[n] == 0 {
+			ready = append(ready, n)
+		}
+	}
+	for len(ready) > 0 {
+		n := ready[0]
+		ready = ready[1:]
+		sorted = append(sorted, n)
+		for _, m := range g.Neighbors(n) {
+			if indegree[m]--; indegree[m] == 0 {
+				ready = append(ready, m)
+			}
+		}
+	}
+	if len(sorted) != len(g.nodes) {
+		return nil, ErrCycle
+	}
+	return sorted, nil
+}
+
+// MinCut finds the least total weight of edges that would have to be cut
+// so that from could no longer reach to, giving up with false once it is
+// more than most. It also returns the nodes that from could still reach
+// after the cut, in the order they were added. In an undirected graph each
+// edge can be crossed either way, up to its weight in all.
+func (g *Graph[N]) MinCut(from, to N, most int) (int, []N, bool) {
+	// spare is how much more could go along each edge, counting what
+	// already goes the other way, as in Edmonds and Karp
+	spare := make(map[N]map[N]int, len(g.nodes))
+	for _, n := range g.nodes {
+		spare[n] = map[N]int{}
+	}
+	for n, links := range g.out {
+		for m, w := range links {
+			spare[n][m] += w
+			spare[m][n] += 0 // so that what goes this way can be sent back
+		}
+	}
+
+	// reach finds a path with something spare along it, or returns the
+	// nodes reached trying
+	reach := func() (map[N]N, bool) {
+		via := map[N]N{from: from}
+		queue := []N{from}
+		for len(queue) > 0 {
+			n := queue[0]
+			queue = queue[1:]
+			for _, m := range g.sorted(spare[n]) {
+				if _, seen := via[m]; !seen && spare[n][m] > 0 {
+					via[m] = n
+					if m == to {
+						return via, true
+					}
+					queue = append(queue, m)
+				}
+			}
+		}
+		return via, false
+	}
+
+	cut := 0
+	for {
+		via, ok := reach()
+		if !ok {
+			side := make([]N, 0, len(via))
+			for _, n := range g.nodes {
+				if _, ok := via[n]; ok {
+					side = append(side, n)
+				}
+			}
+			return cut, side, true
+		}
+		least := -1
+		for m := to; m != from; m = via[m] {
+			if w := spare[via[m]][m]; least < 0 || w < least {
+				least = w
+			}
+		}
+		for m := to; m != from; m = via[m] {
+			spare[via[m]][m] -= least
+			spare[m][via[m]] += least
+		}
+		if cut += least; cut > most {
+			return cut, nil, false
+		}
+	}
+}
+
+// HasCycle reports whether any path leads back to where it started. In an
+// undirected graph going back along the same edge does not count.
+func (g *Graph[N]) HasCycle() bool {
+	if g.directed {
+		_, err := g.TopoSort()
+		return err != nil
+	}
+	for n, links := range g.out {
+		if _, ok := links[n]; ok {
+			return true // a loop
+		}
+	}
+	// a forest of n nodes and c trees has exactly n - c edges
+	return g.EdgeCount() > g.Len()-len(g.Components())
+}
+
+// WriteDOT writes the graph in Graphviz's DOT language. Edges with a weight
+// other than 1 are labeled with it.
+func (g *Graph[N]) WriteDOT(w io.Writer, name string) error {
+	kind, arrow := "graph", "--"
+	if g.directed {
+		kind, arrow = "digraph", "->"
+	}
+	quote := func(n N) string { return strconv.Quote(fmt.Sprint(n)) }
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s {\n", kind, strconv.Quote(name))
+	for _, n := range g.nodes {
+		fmt.Fprintf(&sb, "\t%s;\n", quote(n))
+	}
+	for _, e := range g.Edges() {
+		fmt.Fprintf(&sb, "\t%s %s %s", quote(e.From), arrow, quote(e.To))
+		if e.Weight != 1 {
+			fmt.Fprintf(&sb, " [label=%d]", e.Weight)
+		}
+		sb.WriteString(";\n")
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (g *Graph[N]) String() string {
+	var sb strings.Builder
+	for _, n := range g.nodes {
+		sb.WriteString(fmt.Sprint(n) + ":")
+		for _, m := range g.Neighbors(n) {
+			sb.WriteString(" " + fmt.Sprint(m))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,146 @@
+package graph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// dressing is which clothes have to go on before which.
+func dressing() *Graph[string] {
+	g := New[string](true)
+	for _, e := range [][2]string{
+		{"shirt", "tie"}, {"tie", "jacket"}, {"trousers", "shoes"}, {"trousers", "belt"},
+		{"belt", "jacket"}, {"shirt", "belt"}, {"socks", "shoes"},
+	} {
+		g.AddEdge(e[0], e[1])
+	}
+	return g
+}
+
+func TestTopoSort(t *testing.T) {
+	g := dressing()
+	sorted, err := g.TopoSort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"shirt", "trousers", "socks", "tie", "belt", "shoes", "jacket"}
+	if !slices.Equal(sorted, want) {
+		t.Errorf("sorted %v, want %v", sorted, want)
+	}
+	if g.HasCycle() {
+		t.Errorf("found a cycle in %v", g)
+	}
+
+	g.AddEdge("jacket", "shirt")
+	if _, err := g.TopoSort(); !errors.Is(err, ErrCycle) {
+		t.Errorf("sorting a cycle gave %v, want ErrCycle", err)
+	}
+	if !g.HasCycle() {
+		t.Errorf("found no cycle once the jacket goes on before the shirt")
+	}
+	if _, err := New[string](false).TopoSort(); err == nil {
+		t.Errorf("sorted an undirected graph")
+	}
+}
+
+func TestHasCycle(t *testing.T) {
+	g := New[int](false)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(4, 5)
+	if g.HasCycle() {
+		t.Errorf("found a cycle in the forest %v", g)
+	}
+	g.AddEdge(3, 1)
+	if !g.HasCycle() {
+		t.Errorf("found no cycle in the triangle %v", g)
+	}
+	g.RemoveEdge(3, 1)
+	g.AddEdge(5, 5)
+	if !g.HasCycle() {
+		t.Errorf("found no cycle in the loop %v", g)
+	}
+}
+
+func TestComponents(t *testing.T) {
+	g := New[string](true)
+	g.AddEdge("a", "b")
+	g.AddNode("c")
+	g.AddEdge("f", "e")
+	g.AddEdge("d", "e") // e is reached from both, against the edges
+	got := g.Components()
+	want := [][]string{{"a", "b"}, {"c"}, {"f", "e", "d"}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("components are %v, want %v", got, want)
+	}
+	if len(New[int](false).Components()) != 0 {
+		t.Errorf("an empty graph has components")
+	}
+}
+
+func TestRemoveEdge(t *testing.T) {
+	g := New[string](false)
+	g.AddEdge("a", "b")
+	g.AddEdge("b", "c")
+	g.RemoveEdge("b", "a")
+	if g.HasEdge("a", "b") || g.HasEdge("b", "a") {
+		t.Errorf("a-b is still there after removing b-a")
+	}
+	if got := g.Neighbors("b"); !slices.Equal(got, []string{"c"}) {
+		t.Errorf("b leads to %v, want [c]", got)
+	}
+	if g.Len() != 3 || g.EdgeCount() != 1 {
+		t.Errorf("%d nodes and %d edges, want 3 and 1", g.Len(), g.EdgeCount())
+	}
+	g.RemoveEdge("x", "a") // neither is there to remove
+	g.RemoveEdge("a", "x")
+
+	d := dressing()
+	d.RemoveEdge("shirt", "tie")
+	if d.HasEdge("shirt", "tie") || len(d.Predecessors("tie")) != 0 {
+		t.Errorf("the shirt still goes on before the tie")
+	}
+	d.RemoveEdge("jacket", "tie") // the other way, which was never there
+	if !d.HasEdge("tie", "jacket") {
+		t.Errorf("removing jacket-tie removed tie-jacket")
+	}
+}
+
+func TestMinCut(t *testing.T) {
+	// the flow network of Cormen et al., whose most is 23
+	g := New[string](true)
+	for _, e := range []Edge[string]{
+		{"s", "v1", 16}, {"s", "v2", 13}, {"v1", "v3", 12}, {"v2", "v1", 4}, {"v2", "v4", 14},
+		{"v3", "v2", 9}, {"v3", "t", 20}, {"v4", "v3", 7}, {"v4", "t", 4},
+	} {
+		g.AddWeightedEdge(e.From, e.To, e.Weight)
+	}
+	cut, side, ok := g.MinCut("s", "t", 100)
+	if !ok || cut != 23 || !slices.Equal(side, []string{"s", "v1", "v2", "v4"}) {
+		t.Errorf("cut %d leaving %v (%v), want 23 leaving [s v1 v2 v4]", cut, side, ok)
+	}
+	if _, _, ok := g.MinCut("s", "t", 20); ok {
+		t.Errorf("a cut over 20 was not given up on")
+	}
+	if cut, side, ok := g.MinCut("t", "s", 100); !ok || cut != 0 || !slices.Equal(side, []string{"t"}) {
+		t.Errorf("cut %d leaving %v (%v) against the edges, want 0 leaving [t]", cut, side, ok)
+	}
+
+	// two groups of five, each wired to every other in the group, joined by
+	// three wires, as on 2023 day 25
+	u := New[int](false)
+	for _, group := range []int{0, 5} {
+		for i := 1; i <= 5; i++ {
+			for j := i + 1; j <= 5; j++ {
+				u.AddEdge(group+i, group+j)
+			}
+		}
+	}
+	for i := 1; i <= 3; i++ {
+		u.AddEdge(i, 5+i)
+	}
+	if cut, side, ok := u.MinCut(1, 10, 3); !ok || cut != 3 || !slices.Equal(side, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("cut %d leaving %v (%v), want 3 leaving [1 2 3 4 5]", cut, side, ok)
+	}
+}
@@ -0,0 +1,294 @@
+// Package grid is a rectangular grid of cells read from a puzzle input, for
+// the days whose input is a picture rather than a list.
+package grid
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/havill/AdventOfCode/parse"
+)
+
+// Point is a position in a grid, X across and Y down from the top left.
+type Point struct {
+	X, Y int
+}
+
+func (p Point) Add(q Point) Point { return Point{p.X + q.X, p.Y + q.Y} }
+func (p Point) Sub(q Point) Point { return Point{p.X - q.X, p.Y - q.Y} }
+func (p Point) Mul(k int) Point   { return Point{p.X * k, p.Y * k} }
+
+func (p Point) String() string {
+	return fmt.Sprintf("(%d,%d)", p.X, p.Y)
+}
+
+var (
+	North = Point{0, -1}
+	South = Point{0, 1}
+	East  = Point{1, 0}
+	West  = Point{-1, 0}
+)
+
+// Directions4 are the orthogonal steps, clockwise from north.
+var Directions4 = []Point{North, East, South, West}
+
+// Directions8 adds the diagonals, still clockwise from north.
+var Directions8 = []Point{
+	North, North.Add(East), East, South.Add(East),
+	South, South.Add(West), West, North.Add(West),
+}
+
+type Grid[T any] struct {
+	width, height int
+	cells         []T
+}
+
+func New[T any](width, height int) *Grid[T] {
+	return &Grid[T]{width: width, height: height, cells: make([]T, width*height)}
+}
+
+// Parse builds a grid from lines of text, using mapping to turn each rune into
+// a cell. Every line must be as long as the first.
+func Parse[T any](lines []string, mapping func(r rune) (T, error)) (*Grid[T], error) {
+	numbered := make([]parse.Line, len(lines))
+	for i, line := range lines {
+		numbered[i] = parse.NewLine(i+1, line)
+	}
+	return FromLines(numbered, mapping)
+}
+
+// FromLines is Parse for lines that keep their place in a larger input, so
+// that errors point at the right line.
+func FromLines[T any](lines []parse.Line, mapping func(r rune) (T, error)) (*Grid[T], error) {
+	g := &Grid[T]{height: len(lines)}
+	for y, line := range lines {
+		width := utf8.RuneCountInString(line.Text)
+		if y == 0 {
+			g.width = width
+			g.cells = make([]T, 0, g.width*g.height)
+		} else if width != g.width {
+			return nil, line.ErrorAt(len(line.Text), "%d cells wide, expected %d", width, g.width)
+		}
+		for x, r := range line.Text {
+			cell, err := mapping(r)
+			if err != nil {
+				return nil, line.ErrorAt(x, "%w", err)
+			}
+			g.cells = append(g.cells, cell)
+		}
+	}
+	return g, nil
+}
+
+// ErrorAt reports a problem with the cell at p of a grid from Read or Parse,
+// at its line and column in the input.
+func ErrorAt(p Point, format string, args ...any) error {
+	return &parse.Error{Line: p.Y + 1, Column: p.X + 1, Err: fmt.Errorf(format, args...)}
+}
+
+// Read is Parse for a whole input. Blank lines at the end are ignored, except
+// in strict mode, where the grid has to be all there is.
+func Read[T any](r io.Reader, mapping func(r rune) (T, error)) (*Grid[T], error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	if !parse.Strict {
+		for len(lines) > 0 && lines[len(lines)-1].Blank() {
+			lines = lines[:len(lines)-1]
+		}
+	}
+	return FromLines(lines, mapping)
+}
+
+// Expect wraps a mapping so that in strict mode it only takes the runes in
+// allowed. Outside strict mode it is the mapping as it was.
+func Expect[T any](allowed string, mapping func(r rune) (T, error)) func(r rune) (T, error) {
+	return func(r rune) (T, error) {
+		if parse.Strict && !strings.ContainsRune(allowed, r) {
+			var zero T
+			return zero, fmt.Errorf("unexpected %q, expected one of %q", r, allowed)
+		}
+		return mapping(r)
+	}
+}
+
+// Runes is the mapping for grids that keep the input characters as they are.
+func Runes(r rune) (rune, error) { return r, nil }
+
+// Bytes is like Runes, for inputs that are known to be ASCII.
+func Bytes(r rune) (byte, error) {
+	if r > 0x7f {
+		return 0, fmt.Errorf("not an ASCII character: %q", r)
+	}
+	return byte(r), nil
+}
+
+func (g *Grid[T]) Width() int  { return g.width }
+func (g *Grid[T]) Height() int { return g.height }
+
+// In reports whether p is inside the grid.
+func (g *Grid[T]) In(p Point) bool {
+	return p.X >= 0 && p.X < g.width && p.Y >= 0 && p.Y < g.height
+}
+
+// Get returns the cell at p, and false if p is outside the grid.
+func (g *Grid[T]) Get(p Point) (T, bool) {
+	if !g.In(p) {
+		var zero T
+		return zero, false
+	}
+	return g.cells[p.Y*g.width+p.X], true
+}
+
+// At returns the cell at p, or the zero value if p is outside the grid.
+func (g *Grid[T]) At(p Point) T {
+	v, _ := g.Get(p)
+	return v
+}
+
+// Set changes the cell at p, and reports false if p is outside the grid.
+func (g *Grid[T]) Set(p Point, v T) bool {
+	if !g.In(p) {
+		return false
+	}
+	g.cells[p.Y*g.width+p.X] = v
+	return true
+}
+
+// Row returns row y. Changing it changes the grid.
+func (g *Grid[T]) Row(y int) []T {
+	return g.cells[y*g.width : (y+1)*g.width]
+}
+
+// All visits every cell, a row at a time from the top left.
+func (g *Grid[T]) All() iter.Seq2[Point, T] {
+	return func(yield func(Point, T) bool) {
+		for i, v := range g.cells {
+			if !yield(Point{i % g.width, i / g.width}, v) {
+				return
+			}
+		}
+	}
+}
+
+func (g *Grid[T]) neighbors(p Point, directions []Point) iter.Seq2[Point, T] {
+	return func(yield func(Point, T) bool) {
+		for _, d := range directions {
+			n := p.Add(d)
+			if v, ok := g.Get(n); ok && !yield(n, v) {
+				return
+			}
+		}
+	}
+}
+
+// Neighbors4 visits the cells north, east, south and west of p that are
+// inside the grid.
+func (g *Grid[T]) Neighbors4(p Point) iter.Seq2[Point, T] {
+	return g.neighbors(p, Directions4)
+}
+
+// Neighbors8 is Neighbors4 plus the diagonals.
+func (g *Grid[T]) Neighbors8(p Point) iter.Seq2[Point, T] {
+	return g.neighbors(p, Directions8)
+}
+
+// Find returns the first cell, reading from the top left, that matches.
+func (g *Grid[T]) Find(match func(T) bool) (Point, bool) {
+	for p, v := range g.All() {
+		if match(v) {
+			return p, true
+		}
+	}
+	return Point{-1, -1}, false
+}
+
+// FindAll returns every cell that matches.
+func (g *Grid[T]) FindAll(match func(T) bool) []Point {
+	var found []Point
+	for p, v := range g.All() {
+		if match(v) {
+			found = append(found, p)
+		}
+	}
+	return found
+}
+
+// Count returns how many cells match.
+func (g *Grid[T]) Count(match func(T) bool) int {
+	count := 0
+	for _, v := range g.cells {
+		if match(v) {
+			count++
+		}
+	}
+	return count
+}
+
+func (g *Grid[T]) Clone() *Grid[T] {
+	clone := New[T](g.width, g.height)
+	copy(clone.cells, g.cells)
+	return clone
+}
+
+// Rotate returns a copy of the grid turned a quarter clockwise, so that the
+// west edge becomes the north edge.
+func (g *Grid[T]) Rotate() *Grid[T] {
+	rotated := New[T](g.height, g.width)
+	for p, v := range g.All() {
+		rotated.Set(Point{g.height - 1 - p.Y, p.X}, v)
+	}
+	return rotated
+}
+
+// Transpose returns a copy of the grid flipped about its leading diagonal, so
+// that rows become columns.
+func (g *Grid[T]) Transpose() *Grid[T] {
+	transposed := New[T](g.height, g.width)
+	for p, v := range g.All() {
+		transposed.Set(Point{p.Y, p.X}, v)
+	}
+	return transposed
+}
+
+// Map builds a grid of the same size by converting each cell.
+func Map[T, U any](g *Grid[T], convert func(p Point, v T) U) *Grid[U] {
+	mapped := New[U](g.width, g.height)
+	for p, v := range g.All() {
+		mapped.Set(p, convert(p, v))
+	}
+	return mapped
+}
+
+// Fprint writes the grid a row per line, using format to draw each cell.
+func (g *Grid[T]) Fprint(w io.Writer, format func(p Point, v T) string) error {
+	bw := bufio.NewWriter(w)
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			p := Point{x, y}
+			bw.WriteString(format(p, g.At(p)))
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// String draws the grid with each cell's default format.
+func (g *Grid[T]) String() string {
+	var sb strings.Builder
+	g.Fprint(&sb, func(_ Point, v T) string {
+		if r, ok := any(v).(rune); ok {
+			return string(r)
+		}
+		if b, ok := any(v).(byte); ok {
+			return string(rune(b))
+		}
+		return fmt.Sprint(v)
+	})
+	return sb.String()
+}
@@ -0,0 +1,179 @@
+package grid
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/parse"
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+func readMap(r io.Reader) (*Grid[rune], error) {
+	return Read(r, Expect(".#", Runes))
+}
+
+func FuzzRead(f *testing.F) {
+	parsetest.Seed(f,
+		"#.##..##.\n..#.##.#.\n##......#\n",
+		"...\n.#.\n...\n\n\n",
+		"..\n...\n",
+		"\n",
+	)
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, readMap, (*Grid[rune]).String)
+	})
+}
+
+// letters is a grid of runes with each row on a line of its own.
+func letters(t *testing.T, rows ...string) *Grid[rune] {
+	t.Helper()
+	g, err := Parse(rows, Runes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestRotateTranspose(t *testing.T) {
+	g := letters(t, "ab", "cd", "ef")
+	tests := []struct {
+		name string
+		got  *Grid[rune]
+		want string
+	}{
+		{"Rotate", g.Rotate(), "eca\nfdb\n"}, // the west edge, e c a, is now north
+		{"Rotate four times", g.Rotate().Rotate().Rotate().Rotate(), "ab\ncd\nef\n"},
+		{"Transpose", g.Transpose(), "ace\nbdf\n"},
+		{"Transpose twice", g.Transpose().Transpose(), "ab\ncd\nef\n"},
+	}
+	for _, tt := range tests {
+		if got := tt.got.String(); got != tt.want {
+			t.Errorf("%s gave\n%s, want\n%s", tt.name, got, tt.want)
+		}
+		if tt.got.Width()*tt.got.Height() != 6 {
+			t.Errorf("%s is %dx%d", tt.name, tt.got.Width(), tt.got.Height())
+		}
+	}
+	if g.String() != "ab\ncd\nef\n" {
+		t.Errorf("turning a copy changed the grid to\n%s", g)
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	g := letters(t, "abc", "def", "ghi")
+	tests := []struct {
+		at     Point
+		n4, n8 string
+	}{
+		{Point{0, 0}, "bd", "bed"},
+		{Point{2, 2}, "fh", "fhe"},
+		{Point{1, 0}, "cea", "cfeda"},
+		{Point{0, 1}, "aeg", "abehg"},
+		{Point{1, 1}, "bfhd", "bcfihgda"},
+		{Point{-1, -1}, "", "a"},
+		{Point{3, 1}, "f", "ifc"},
+	}
+	collect := func(seq func(func(Point, rune) bool)) string {
+		var s []rune
+		for p, v := range seq {
+			if g.At(p) != v {
+				t.Errorf("visited %q at %v, which has %q", v, p, g.At(p))
+			}
+			s = append(s, v)
+		}
+		return string(s)
+	}
+	for _, tt := range tests {
+		if got := collect(g.Neighbors4(tt.at)); got != tt.n4 {
+			t.Errorf("Neighbors4(%v) = %q, want %q", tt.at, got, tt.n4)
+		}
+		if got := collect(g.Neighbors8(tt.at)); got != tt.n8 {
+			t.Errorf("Neighbors8(%v) = %q, want %q", tt.at, got, tt.n8)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	g := letters(t, "a#.", "..#")
+	tests := []struct {
+		r    rune
+		want Point
+		ok   bool
+		all  []Point
+	}{
+		{'#', Point{1, 0}, true, []Point{{1, 0}, {2, 1}}},
+		{'a', Point{0, 0}, true, []Point{{0, 0}}},
+		{'x', Point{-1, -1}, false, nil},
+	}
+	for _, tt := range tests {
+		is := func(r rune) bool { return r == tt.r }
+		if p, ok := g.Find(is); p != tt.want || ok != tt.ok {
+			t.Errorf("Find(%q) = %v, %v, want %v, %v", tt.r, p, ok, tt.want, tt.ok)
+		}
+		if all := g.FindAll(is); !slices.Equal(all, tt.all) {
+			t.Errorf("FindAll(%q) = %v, want %v", tt.r, all, tt.all)
+		}
+		if n := g.Count(is); n != len(tt.all) {
+			t.Errorf("Count(%q) = %d, want %d", tt.r, n, len(tt.all))
+		}
+	}
+}
+
+func TestGetSet(t *testing.T) {
+	g := letters(t, "ab", "cd")
+	tests := []struct {
+		p  Point
+		in bool
+	}{
+		{Point{0, 0}, true},
+		{Point{1, 1}, true},
+		{Point{2, 0}, false},
+		{Point{0, 2}, false},
+		{Point{-1, 0}, false},
+		{Point{0, -1}, false},
+	}
+	for _, tt := range tests {
+		before := g.String()
+		if v, ok := g.Get(tt.p); ok != tt.in || !ok && v != 0 {
+			t.Errorf("Get(%v) = %q, %v, want inside %v", tt.p, v, ok, tt.in)
+		}
+		if ok := g.Set(tt.p, 'x'); ok != tt.in {
+			t.Errorf("Set(%v) = %v, want %v", tt.p, ok, tt.in)
+		}
+		if !tt.in && g.String() != before {
+			t.Errorf("Set(%v) outside the grid changed it to\n%s", tt.p, g)
+		}
+		if tt.in && g.At(tt.p) != 'x' {
+			t.Errorf("Set(%v) left %q", tt.p, g.At(tt.p))
+		}
+	}
+}
+
+func TestFromLinesErrors(t *testing.T) {
+	defer func(was bool) { parse.Strict = was }(parse.Strict)
+	parse.Strict = true
+	tests := []struct {
+		input        string
+		line, column int
+	}{
+		{"ab\nabc\n", 2, 4},  // one too many, pointed at where it should have ended
+		{"abc\nab\n", 2, 3},  // one too few
+		{"éé\nééé\n", 2, 4},  // columns are runes, not bytes
+		{"..\n.x\n", 2, 2},   // a rune Expect doesn't allow
+		{"..\n..\n\n", 3, 1}, // strict about blank lines at the end
+		{"..\n\n..\n", 2, 1}, // or in the middle
+	}
+	for _, tt := range tests {
+		_, err := Read(strings.NewReader(tt.input), Expect(".éabc", Runes))
+		var pe *parse.Error
+		if !errors.As(err, &pe) || pe.Line != tt.line || pe.Column != tt.column {
+			t.Errorf("%q: got %v, want an error at line %d, column %d", tt.input, err, tt.line, tt.column)
+		}
+	}
+	if p := ErrorAt(Point{2, 4}, "off").(*parse.Error); p.Line != 5 || p.Column != 3 {
+		t.Errorf("ErrorAt((2,4)) is at line %d, column %d, want 5, 3", p.Line, p.Column)
+	}
+}
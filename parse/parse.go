@@ -0,0 +1,330 @@
+// Package parse reads the line-by-line puzzle inputs, so that a malformed
+// input fails with the line and column where it went wrong instead of
+// quietly becoming a 0.
+package parse
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Error is a problem at a place in the input. Line and Column count from 1;
+// a Column of 0 means the whole line.
+type Error struct {
+	Line, Column int
+	Err          error
+}
+
+func (e *Error) Error() string {
+	if e.Column == 0 {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Line is a line of input, or a piece of one, that remembers where it came
+// from so that errors can point at it.
+type Line struct {
+	Text   string
+	Number int    // line number, from 1
+	full   string // the whole line Text was cut from
+	start  int    // byte offset of Text in full
+}
+
+// NewLine makes a line that is not part of a larger input.
+func NewLine(number int, text string) Line {
+	return Line{Text: text, Number: number, full: text}
+}
+
+// Strict makes the solvers reject anything in their input that they do not
+// expect, instead of skipping it or making the best of it. Lines also checks
+// that the input is not blank, has no control characters and ends with a
+// newline, as every puzzle input does, so that a download that was cut
+// short is noticed.
+//
+// It is one setting for the whole process: aoc sets it from -strict before
+// any solver starts and leaves it alone after, since aoc serve runs solvers
+// and draws pictures at the same time. Only tests that don't run in
+// parallel change it while they run.
+var Strict bool
+
+// Tolerate is for input a solver has always skipped. In strict mode it
+// returns err; otherwise it logs err as a warning and returns nil, and the
+// caller carries on without the bad part. The warning is made with ctx, the
+// solver's, so that it reaches whoever is collecting the run's warnings.
+func Tolerate(ctx context.Context, log *slog.Logger, err error) error {
+	if Strict {
+		return err
+	}
+	log.WarnContext(ctx, "skipping bad input", "err", err)
+	return nil
+}
+
+// lastByte remembers the end of what has been read through it.
+type lastByte struct {
+	r    io.Reader
+	last byte
+}
+
+func (l *lastByte) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	if n > 0 {
+		l.last = p[n-1]
+	}
+	return n, err
+}
+
+// Lines reads every line of r.
+func Lines(r io.Reader) ([]Line, error) {
+	var lines []Line
+	end := &lastByte{r: r}
+	scanner := bufio.NewScanner(end)
+	scanner.Buffer(nil, 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		// however many carriage returns a copy has left, they are the
+		// end of the line and not part of it
+		lines = append(lines, NewLine(n, strings.TrimRight(scanner.Text(), "\r")))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if Strict {
+		if err := check(lines, end.last); err != nil {
+			return nil, err
+		}
+	}
+	return lines, nil
+}
+
+// check is what Lines looks for in strict mode.
+func check(lines []Line, last byte) error {
+	if !slices.ContainsFunc(lines, func(l Line) bool { return !l.Blank() }) {
+		return errors.New("the input is empty")
+	}
+	for _, l := range lines {
+		for i, r := range l.Text {
+			if unicode.IsControl(r) && r != '\t' {
+				return l.ErrorAt(i, "unexpected %q", r)
+			}
+		}
+	}
+	if last != '\n' {
+		return End(lines).Errorf("no newline at the end; the input may have been cut short")
+	}
+	return nil
+}
+
+// End is the empty piece at the very end of lines, for errors about
+// something that the input ran out before.
+func End(lines []Line) Line {
+	if len(lines) == 0 {
+		return NewLine(1, "")
+	}
+	l := lines[len(lines)-1]
+	return l.sub(len(l.Text), len(l.Text))
+}
+
+// Sections reads r as groups of lines separated by blank lines. Runs of blank
+// lines, and blank lines at either end, do not make empty sections.
+func Sections(r io.Reader) ([][]Line, error) {
+	lines, err := Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	var sections [][]Line
+	var section []Line
+	for _, l := range lines {
+		if l.Blank() {
+			if section != nil {
+				sections = append(sections, section)
+				section = nil
+			}
+			continue
+		}
+		section = append(section, l)
+	}
+	if section != nil {
+		sections = append(sections, section)
+	}
+	return sections, nil
+}
+
+// Column is where Text begins in the original line, counting runes from 1.
+func (l Line) Column() int {
+	return utf8.RuneCountInString(l.full[:l.start]) + 1
+}
+
+func (l Line) String() string { return l.Text }
+
+func (l Line) Blank() bool { return strings.TrimSpace(l.Text) == "" }
+
+// sub is the piece of l from byte i to j.
+func (l Line) sub(i, j int) Line {
+	return Line{Text: l.Text[i:j], Number: l.Number, full: l.full, start: l.start + i}
+}
+
+// Errorf reports a problem at the start of l.
+func (l Line) Errorf(format string, args ...any) error {
+	return l.ErrorAt(0, format, args...)
+}
+
+// ErrorAt reports a problem at byte i of l.
+func (l Line) ErrorAt(i int, format string, args ...any) error {
+	return &Error{Line: l.Number, Column: l.sub(i, i).Column(), Err: fmt.Errorf(format, args...)}
+}
+
+// Only reports the first rune of l that is not in allowed.
+func (l Line) Only(allowed string) error {
+	for i, r := range l.Text {
+		if !strings.ContainsRune(allowed, r) {
+			return l.ErrorAt(i, "unexpected %q", r)
+		}
+	}
+	return nil
+}
+
+// Trim drops the white space around l.
+func (l Line) Trim() Line {
+	i := len(l.Text) - len(strings.TrimLeftFunc(l.Text, unicode.IsSpace))
+	j := len(strings.TrimRightFunc(l.Text, unicode.IsSpace))
+	if i > j {
+		return l.sub(j, j)
+	}
+	return l.sub(i, j)
+}
+
+// Cut splits l around the first sep, like strings.Cut, but it is an error for
+// sep to be missing.
+func (l Line) Cut(sep string) (before, after Line, err error) {
+	i := strings.Index(l.Text, sep)
+	if i < 0 {
+		return Line{}, Line{}, l.ErrorAt(len(l.Text), "expected %q", sep)
+	}
+	return l.sub(0, i), l.sub(i+len(sep), len(l.Text)), nil
+}
+
+// Split splits l around every sep, for lists such as "a,b,c".
+func (l Line) Split(sep string) []Line {
+	var parts []Line
+	i := 0
+	for {
+		j := strings.Index(l.Text[i:], sep)
+		if j < 0 {
+			return append(parts, l.sub(i, len(l.Text)))
+		}
+		parts = append(parts, l.sub(i, i+j))
+		i += j + len(sep)
+	}
+}
+
+// Fields splits l around runs of white space.
+func (l Line) Fields() []Line {
+	var fields []Line
+	start := -1
+	for i, r := range l.Text {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				fields = append(fields, l.sub(start, i))
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		fields = append(fields, l.sub(start, len(l.Text)))
+	}
+	return fields
+}
+
+// Int reads l, less any surrounding white space, as a decimal integer.
+func (l Line) Int() (int, error) {
+	t := l.Trim()
+	if t.Text == "" {
+		return 0, l.ErrorAt(len(l.Text), "expected a number")
+	}
+	n, err := strconv.Atoi(t.Text)
+	if err != nil {
+		var numErr *strconv.NumError
+		if errors.As(err, &numErr) && numErr.Err == strconv.ErrRange {
+			return 0, t.Errorf("number out of range: %q", t.Text)
+		}
+		return 0, t.Errorf("not a number: %q", t.Text)
+	}
+	return n, nil
+}
+
+// Ints reads the integers in l separated by white space, as in "41 48 83".
+func (l Line) Ints() ([]int, error) {
+	return ints(l.Fields())
+}
+
+// IntList reads the integers in l separated by sep, as in "3,1,4".
+func (l Line) IntList(sep string) ([]int, error) {
+	return ints(l.Split(sep))
+}
+
+func ints(fields []Line) ([]int, error) {
+	list := make([]int, 0, len(fields))
+	for _, f := range fields {
+		n, err := f.Int()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, n)
+	}
+	return list, nil
+}
+
+// Labeled splits lines like "Time:   7  15   30" into the label before the
+// colon and the integers after it.
+func (l Line) Labeled() (label string, values []int, err error) {
+	before, after, err := l.Cut(":")
+	if err != nil {
+		return "", nil, err
+	}
+	values, err = after.Ints()
+	return strings.TrimSpace(before.Text), values, err
+}
+
+// Pair splits lines like "41 48 | 83 86" into the pieces either side of sep.
+func (l Line) Pair(sep string) (left, right Line, err error) {
+	left, right, err = l.Cut(sep)
+	return left.Trim(), right.Trim(), err
+}
+
+// Field is one key=value pair of a record.
+type Field struct {
+	Key   string
+	Value Line
+}
+
+// Record reads lines like "x=787,m=2655" as fields separated by sep, each a
+// key and a value either side of "=". The fields are in the order given.
+func (l Line) Record(sep string) ([]Field, error) {
+	var fields []Field
+	for _, part := range l.Split(sep) {
+		part = part.Trim()
+		key, value, err := part.Cut("=")
+		if err != nil {
+			return nil, err
+		}
+		key = key.Trim()
+		if key.Text == "" {
+			return nil, key.Errorf("missing key before %q", "=")
+		}
+		fields = append(fields, Field{Key: key.Text, Value: value.Trim()})
+	}
+	return fields, nil
+}
@@ -0,0 +1,136 @@
+package parse_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/parse"
+	"github.com/havill/AdventOfCode/parse/parsetest"
+)
+
+func readText(r io.Reader) ([]string, error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	text := make([]string, len(lines))
+	for i, l := range lines {
+		text[i] = l.Text
+	}
+	return text, nil
+}
+
+func writeText(text []string) string {
+	var b strings.Builder
+	for _, t := range text {
+		b.WriteString(t)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func FuzzLines(f *testing.F) {
+	parsetest.Seed(f, "1abc2\npqr3stu8vwx\n", "a\r\nb", "", "\n\n", "tab\there\n")
+	f.Fuzz(func(t *testing.T, input string, strict bool) {
+		parsetest.RoundTrip(t, input, strict, readText, writeText)
+	})
+}
+
+func TestEnd(t *testing.T) {
+	lines, err := parse.Lines(strings.NewReader("seeds: 79 14\n\nsoil map:\n50 98 2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pe *parse.Error
+	if err := parse.End(lines).Errorf("no fertilizer map"); !errors.As(err, &pe) || pe.Line != 4 || pe.Column != 8 {
+		t.Errorf("got %v, want line 4, column 8", err)
+	}
+	if err := parse.End(nil).Errorf("no seeds"); err.Error() != "line 1, column 1: no seeds" {
+		t.Errorf("got %v at the end of nothing, want line 1, column 1", err)
+	}
+}
+
+func TestErrorColumns(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		do     func(l parse.Line) error
+		column int
+	}{
+		{"Cut without sep", "abc def", func(l parse.Line) error { _, _, err := l.Cut(":"); return err }, 8},
+		{"Cut without sep after é", "é b", func(l parse.Line) error { _, _, err := l.Cut(":"); return err }, 4},
+		{"Int of nothing", "   ", func(l parse.Line) error { _, err := l.Int(); return err }, 4},
+		{"Int of empty", "", func(l parse.Line) error { _, err := l.Int(); return err }, 1},
+		{"Int out of range", " 99999999999999999999 ", func(l parse.Line) error { _, err := l.Int(); return err }, 2},
+		{"Int not a number", "  1x", func(l parse.Line) error { _, err := l.Int(); return err }, 3},
+		{"Int after a Cut", "a: 12x", func(l parse.Line) error {
+			_, after, _ := l.Cut(":")
+			_, err := after.Int()
+			return err
+		}, 4},
+		{"Ints", "1  2 x 4", func(l parse.Line) error { _, err := l.Ints(); return err }, 6},
+		{"IntList", "3,1,,4", func(l parse.Line) error { _, err := l.IntList(","); return err }, 5},
+		{"Labeled without colon", "Time 7", func(l parse.Line) error { _, _, err := l.Labeled(); return err }, 7},
+		{"Labeled bad value", "Time: 7 x", func(l parse.Line) error { _, _, err := l.Labeled(); return err }, 9},
+		{"Labeled after é", "é: ü 7", func(l parse.Line) error { _, _, err := l.Labeled(); return err }, 4},
+		{"Pair without sep", "41 48 83", func(l parse.Line) error { _, _, err := l.Pair("|"); return err }, 9},
+		{"Record missing key", "x=1,=2", func(l parse.Line) error { _, err := l.Record(","); return err }, 5},
+		{"Record missing key after space", "x=1,  = 2", func(l parse.Line) error { _, err := l.Record(","); return err }, 7},
+		{"Record missing =", "x=1, m", func(l parse.Line) error { _, err := l.Record(","); return err }, 7},
+		{"Only", "ab·c", func(l parse.Line) error { return l.Only("abc") }, 3},
+		{"ErrorAt a byte after é", "éa", func(l parse.Line) error { return l.ErrorAt(2, "here") }, 2},
+	}
+	for _, tt := range tests {
+		err := tt.do(parse.NewLine(3, tt.text))
+		var pe *parse.Error
+		if !errors.As(err, &pe) || pe.Line != 3 || pe.Column != tt.column {
+			t.Errorf("%s: %q gave %v, want an error at line 3, column %d", tt.name, tt.text, err, tt.column)
+		}
+	}
+}
+
+func TestPieceColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		pieces  func(l parse.Line) []parse.Line
+		want    []string
+		columns []int
+	}{
+		{"Split", "1,2,,3", func(l parse.Line) []parse.Line { return l.Split(",") },
+			[]string{"1", "2", "", "3"}, []int{1, 3, 5, 6}},
+		{"Split on a longer sep", "a -> é -> b", func(l parse.Line) []parse.Line { return l.Split(" -> ") },
+			[]string{"a", "é", "b"}, []int{1, 6, 11}},
+		{"Split without sep", "abc", func(l parse.Line) []parse.Line { return l.Split(",") },
+			[]string{"abc"}, []int{1}},
+		{"Fields", "  ab\tc  dé f", func(l parse.Line) []parse.Line { return l.Fields() },
+			[]string{"ab", "c", "dé", "f"}, []int{3, 6, 9, 12}},
+		{"Fields of blanks", " \t ", func(l parse.Line) []parse.Line { return l.Fields() },
+			nil, nil},
+		{"Pair", "41 48 | 83", func(l parse.Line) []parse.Line {
+			left, right, _ := l.Pair("|")
+			return []parse.Line{left, right}
+		}, []string{"41 48", "83"}, []int{1, 9}},
+		{"Cut", "ü: 7", func(l parse.Line) []parse.Line {
+			before, after, _ := l.Cut(":")
+			return []parse.Line{before, after}
+		}, []string{"ü", " 7"}, []int{1, 3}},
+		{"Trim", "  é  ", func(l parse.Line) []parse.Line { return []parse.Line{l.Trim()} },
+			[]string{"é"}, []int{3}},
+	}
+	for _, tt := range tests {
+		pieces := tt.pieces(parse.NewLine(1, tt.text))
+		if len(pieces) != len(tt.want) {
+			t.Errorf("%s: %q gave %d pieces, want %d", tt.name, tt.text, len(pieces), len(tt.want))
+			continue
+		}
+		for i, p := range pieces {
+			if p.Text != tt.want[i] || p.Column() != tt.columns[i] || p.Number != 1 {
+				t.Errorf("%s: %q piece %d is %q at column %d, want %q at column %d",
+					tt.name, tt.text, i, p.Text, p.Column(), tt.want[i], tt.columns[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Package parsetest checks parsers against the property their fuzz tests
+// share: any input is either rejected or read as a model that can be
+// written back out as text the parser reads the same way.
+package parsetest
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/havill/AdventOfCode/parse"
+)
+
+// RoundTrip reads input with read, in strict mode if strict is set. If read
+// accepts it, the model it returns is written with write, and read must
+// accept that too and return an equal model. A panic in read fails the test
+// through the fuzzer, as any other would.
+func RoundTrip[M any](t *testing.T, input string, strict bool, read func(io.Reader) (M, error), write func(M) string) {
+	t.Helper()
+	defer func(was bool) { parse.Strict = was }(parse.Strict)
+	parse.Strict = strict
+
+	model, err := read(strings.NewReader(input))
+	if err != nil {
+		return
+	}
+	text := write(model)
+	again, err := read(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("read %q, but not %q, which was written from it: %v", input, text, err)
+	}
+	if !reflect.DeepEqual(model, again) {
+		t.Fatalf("read %q as\n%#v\nbut %q, which was written from it, as\n%#v", input, model, text, again)
+	}
+}
+
+// Background lets a parser that takes the solver's context be read by
+// RoundTrip, with context.Background.
+func Background[M any](read func(context.Context, io.Reader) (M, error)) func(io.Reader) (M, error) {
+	return func(r io.Reader) (M, error) { return read(context.Background(), r) }
+}
+
+// Seed adds each of the inputs to the corpus of f, both strictly and not.
+func Seed(f *testing.F, inputs ...string) {
+	for _, input := range inputs {
+		f.Add(input, false)
+		f.Add(input, true)
+	}
+}
@@ -0,0 +1,96 @@
+package render
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Cast records everything written to it as an asciicast v2 file, the format
+// asciinema plays back, so that an animation can be shared and replayed
+// without running the solver again. Each write is one output event, stamped
+// with the time since the first one.
+type Cast struct {
+	w             *bufio.Writer
+	enc           *json.Encoder
+	start         time.Time
+	width, height int
+	started       bool
+}
+
+// NewCast records to w. The screen is 80x24 unless Resize says otherwise
+// before the first write.
+func NewCast(w io.Writer) *Cast {
+	c := &Cast{w: bufio.NewWriter(w), width: 80, height: 24}
+	c.enc = json.NewEncoder(c.w)
+	c.enc.SetEscapeHTML(false)
+	return c
+}
+
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Env       map[string]string `json:"env"`
+}
+
+// Resize sets the size of the screen in the header, or records a resize
+// event once output has begun.
+func (c *Cast) Resize(width, height int) {
+	if !c.started {
+		c.width, c.height = width, height
+		return
+	}
+	if width != c.width || height != c.height {
+		c.width, c.height = width, height
+		// a failed write stays with the buffer, so Close returns it
+		c.event("r", fmt.Sprintf("%dx%d", width, height))
+	}
+}
+
+func (c *Cast) Write(p []byte) (int, error) {
+	if !c.started {
+		if err := c.header(); err != nil {
+			return 0, err
+		}
+	}
+	// there is no terminal driver to turn line feeds into new lines
+	data := strings.ReplaceAll(string(p), "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\n", "\r\n")
+	if err := c.event("o", data); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *Cast) header() error {
+	c.started = true
+	c.start = time.Now()
+	return c.enc.Encode(castHeader{
+		Version:   2,
+		Width:     c.width,
+		Height:    c.height,
+		Timestamp: c.start.Unix(),
+		Env:       map[string]string{"TERM": "xterm-256color"},
+	})
+}
+
+func (c *Cast) event(kind, data string) error {
+	elapsed := time.Since(c.start).Seconds()
+	return c.enc.Encode([]any{elapsed, kind, data})
+}
+
+// Close writes out whatever has not been written yet, and returns the first
+// error in writing the recording. It does not close the underlying writer.
+func (c *Cast) Close() error {
+	if !c.started {
+		if err := c.header(); err != nil {
+			return err
+		}
+	}
+	return c.w.Flush()
+}
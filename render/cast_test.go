@@ -0,0 +1,98 @@
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCast records two frames, the second a row taller, and checks what
+// asciinema would read back.
+func TestCast(t *testing.T) {
+	var b bytes.Buffer
+	c := NewCast(&b)
+	r := New(c, Plain, 0)
+	start := time.Now().Unix()
+	r.Draw(flag())
+	tall := NewFrame(2, 3)
+	tall.Set(0, 2, Cell{Rune: '@', Fg: Green})
+	r.Draw(tall)
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(b.String(), "\033[") {
+		t.Errorf("a plain cast has colours in it")
+	}
+	lines := bufio.NewScanner(&b)
+	if !lines.Scan() {
+		t.Fatal("no header")
+	}
+	var header map[string]any
+	if err := json.Unmarshal(lines.Bytes(), &header); err != nil {
+		t.Fatalf("header %s: %v", lines.Bytes(), err)
+	}
+	if header["version"] != 2.0 || header["width"] != 2.0 || header["height"] != 3.0 {
+		t.Errorf("header %s, want version 2 and a 2x3 screen", lines.Bytes())
+	}
+	if stamp, _ := header["timestamp"].(float64); int64(stamp) < start || int64(stamp) > time.Now().Unix() {
+		t.Errorf("timestamp %v, want the time of the first frame", header["timestamp"])
+	}
+	if env, _ := header["env"].(map[string]any); env["TERM"] != "xterm-256color" {
+		t.Errorf("env %v, want TERM=xterm-256color", header["env"])
+	}
+
+	type event struct {
+		time       float64
+		kind, data string
+	}
+	var events []event
+	for lines.Scan() {
+		var e []any
+		if err := json.Unmarshal(lines.Bytes(), &e); err != nil || len(e) != 3 {
+			t.Fatalf("event %s: %v", lines.Bytes(), err)
+		}
+		at, _ := e[0].(float64)
+		kind, _ := e[1].(string)
+		data, _ := e[2].(string)
+		events = append(events, event{at, kind, data})
+	}
+	want := []event{
+		{0, "o", "#.\r\n  \r\n\r\n"},
+		{0, "r", "2x4"},
+		{0, "o", "  \r\n  \r\n@ \r\n\r\n"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("%d events, want %d: %v", len(events), len(want), events)
+	}
+	for i, e := range events {
+		if e.kind != want[i].kind || e.data != want[i].data {
+			t.Errorf("event %d is %q %q, want %q %q", i, e.kind, e.data, want[i].kind, want[i].data)
+		}
+		if e.time < 0 || i > 0 && e.time < events[i-1].time {
+			t.Errorf("event %d is at %v, before the one before it", i, e.time)
+		}
+	}
+}
+
+func TestCastWriteError(t *testing.T) {
+	cast := NewCast(&brokenPipe{})
+	r := New(cast, TrueColor, 0)
+	for range 3 {
+		r.Draw(flag())
+	}
+	if err := r.Close(); err != nil {
+		t.Errorf("the renderer gave %v before the recording was written", err)
+	}
+	if err := cast.Close(); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Close gave %v, want %v", err, os.ErrClosed)
+	}
+}
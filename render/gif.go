@@ -0,0 +1,153 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"slices"
+	"time"
+)
+
+// gifHold is how long the last frame of a GIF stays up before it starts
+// again, in hundredths of a second, so that the end can be seen.
+const gifHold = 200
+
+// ParsePalette picks the colours of a GIF by name: "exact" for the colours
+// each frame uses, or the fixed "plan9" or "websafe" ones.
+func ParsePalette(name string) (color.Palette, error) {
+	switch name {
+	case "exact":
+		return nil, nil
+	case "plan9":
+		return palette.Plan9, nil
+	case "websafe":
+		return palette.WebSafe, nil
+	}
+	return nil, fmt.Errorf("unknown palette %q: want exact, plan9 or websafe", name)
+}
+
+// GIF records the frames drawn to it as an animated GIF, drawn as Image
+// draws them, and writes it out when it is closed. Each frame only keeps
+// the part of the picture that changed, so that a long simulation that
+// moves a little at a time stays small.
+type GIF struct {
+	w       io.Writer
+	size    int
+	delay   int           // between frames, in hundredths of a second
+	every   int           // how many frames are drawn for each one kept
+	palette color.Palette // nil for the colours each frame uses
+	anim    gif.GIF
+	prev    *image.RGBA // the last frame kept
+	drawn   int
+	skipped *Frame // the last frame drawn, if it wasn't kept
+}
+
+// NewGIF records to w with each cell size pixels wide, keeping one frame in
+// every and showing each for delay. A nil palette uses the colours of each
+// frame, or the Plan 9 ones for a frame with more than 256.
+func NewGIF(w io.Writer, size int, delay time.Duration, every int, palette color.Palette) *GIF {
+	return &GIF{
+		w:       w,
+		size:    size,
+		delay:   max(1, int(delay/(10*time.Millisecond))),
+		every:   max(1, every),
+		palette: palette,
+	}
+}
+
+// Draw records the next frame, unless it is one of the ones being skipped.
+// Frames are kept in memory until Close writes them, so it never fails.
+func (g *GIF) Draw(f *Frame) error {
+	g.drawn++
+	if (g.drawn-1)%g.every != 0 {
+		g.skipped = &Frame{Width: f.Width, Height: f.Height, Cells: slices.Clone(f.Cells)}
+		return nil
+	}
+	g.skipped = nil
+	g.add(f)
+	return nil
+}
+
+func (g *GIF) add(f *Frame) {
+	img := Image(f, g.size)
+	bounds := img.Bounds()
+	if g.prev != nil && g.prev.Bounds() == bounds {
+		bounds = changed(g.prev, img)
+		if bounds.Empty() {
+			g.anim.Delay[len(g.anim.Delay)-1] += g.delay
+			return
+		}
+	}
+	g.prev = img
+
+	pal := g.palette
+	if pal == nil {
+		pal = colors(f)
+	}
+	p := image.NewPaletted(bounds, pal)
+	draw.Draw(p, bounds, img, bounds.Min, draw.Src)
+	g.anim.Image = append(g.anim.Image, p)
+	g.anim.Delay = append(g.anim.Delay, g.delay)
+	g.anim.Disposal = append(g.anim.Disposal, gif.DisposalNone)
+	g.anim.Config.Width = max(g.anim.Config.Width, img.Bounds().Dx())
+	g.anim.Config.Height = max(g.anim.Config.Height, img.Bounds().Dy())
+}
+
+// changed is the smallest rectangle around the pixels of b that differ from
+// those of a, which are the same size.
+func changed(a, b *image.RGBA) image.Rectangle {
+	var r image.Rectangle
+	bounds := b.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		i := a.PixOffset(bounds.Min.X, y)
+		j := i + 4*bounds.Dx()
+		if slices.Equal(a.Pix[i:j], b.Pix[i:j]) {
+			continue
+		}
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if a.RGBAAt(x, y) != b.RGBAAt(x, y) {
+				r = r.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return r
+}
+
+// colors are the colours a frame is drawn in, if there are few enough for
+// a GIF.
+func colors(f *Frame) color.Palette {
+	seen := make(map[Color]bool)
+	var pal color.Palette
+	for _, c := range f.Cells {
+		fg, bg := c.colors()
+		for _, col := range []Color{fg, bg} {
+			if !seen[col] {
+				seen[col] = true
+				pal = append(pal, color.RGBA{col.R, col.G, col.B, 255})
+			}
+		}
+		if len(pal) > 256 {
+			return palette.Plan9
+		}
+	}
+	return pal
+}
+
+// Close writes the GIF, ending on the last frame drawn even if it would have
+// been skipped. It does not close the underlying writer.
+func (g *GIF) Close() error {
+	if g.skipped != nil {
+		g.add(g.skipped)
+		g.skipped = nil
+	}
+	if len(g.anim.Image) == 0 {
+		return errors.New("no frames were drawn, so there is nothing to make a GIF of")
+	}
+	g.anim.Delay[len(g.anim.Delay)-1] = max(g.anim.Delay[len(g.anim.Delay)-1], gifHold)
+	return gif.EncodeAll(g.w, &g.anim)
+}
@@ -0,0 +1,59 @@
+package render
+
+import (
+	"bytes"
+	"image"
+	"image/gif"
+	"testing"
+	"time"
+)
+
+func TestGIF(t *testing.T) {
+	// a wall moving one cell right each frame, then staying put
+	frame := func(x int) *Frame {
+		f := NewFrame(4, 2)
+		f.Set(x, 1, Cell{Rune: '#', Fg: Red})
+		return f
+	}
+	var b bytes.Buffer
+	g := NewGIF(&b, 2, 30*time.Millisecond, 2, nil)
+	for _, x := range []int{0, 1, 2, 2, 2, 3} {
+		g.Draw(frame(x))
+	}
+	if err := g.Close(); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// frames 0, 2 and 2 are kept, and 3 because it is the last; the second 2
+	// adds to the time of the first
+	want := []image.Rectangle{
+		image.Rect(0, 0, 8, 4),
+		image.Rect(0, 2, 6, 4), // where the wall was and is now
+		image.Rect(4, 2, 8, 4),
+	}
+	if anim.Config.Width != 8 || anim.Config.Height != 4 {
+		t.Errorf("GIF is %dx%d, want 8x4", anim.Config.Width, anim.Config.Height)
+	}
+	if len(anim.Image) != len(want) {
+		t.Fatalf("%d frames, want %d", len(anim.Image), len(want))
+	}
+	for i, img := range anim.Image {
+		if img.Bounds() != want[i] {
+			t.Errorf("frame %d covers %v, want %v", i, img.Bounds(), want[i])
+		}
+	}
+	if got := anim.Delay; got[0] != 3 || got[1] != 6 || got[2] != gifHold {
+		t.Errorf("delays are %v, want [3 6 %d]", got, gifHold)
+	}
+	if r, g, b, _ := anim.Image[2].At(6, 3).RGBA(); r>>8 != 255 || g != 0 || b != 0 {
+		t.Errorf("the wall is not red at the end")
+	}
+
+	if err := NewGIF(&b, 2, time.Second, 1, nil).Close(); err == nil {
+		t.Errorf("a GIF with no frames was written")
+	}
+}
@@ -0,0 +1,215 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// A Palette is a run of colours for showing an amount, from the colour of
+// none to the colour of the most.
+type Palette []Color
+
+// Heat goes from black through red and yellow to white, like something
+// getting hotter.
+var Heat = Palette{Black, RGB(160, 0, 0), RGB(255, 96, 0), Yellow, White}
+
+// Shade is the colour of v out of most, blended between the two nearest
+// colours of the palette.
+func (p Palette) Shade(v, most int) Color {
+	if v <= 0 || most <= 0 {
+		return p[0]
+	}
+	if v >= most {
+		return p[len(p)-1]
+	}
+	at := float64(v) / float64(most) * float64(len(p)-1)
+	i := int(at)
+	t := at - float64(i)
+	mix := func(a, b uint8) uint8 { return uint8(float64(a) + t*(float64(b)-float64(a)) + 0.5) }
+	a, b := p[i], p[i+1]
+	return RGB(mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B))
+}
+
+// strokes are what a rune is drawn with in an image, since there is no font
+// to draw it with.
+type strokes uint8
+
+const (
+	north strokes = 1 << iota // a line from the middle of the cell to its top
+	east
+	south
+	west
+	rising  // a line from the bottom left corner to the top right
+	falling // a line from the top left corner to the bottom right
+	solid   // the whole cell
+)
+
+// runeStrokes are the runes that have a shape in an image: the lines of
+// pipes, paths and mirrors, and walls. Anything else, such as a letter or
+// a '.', is only its background.
+var runeStrokes = map[rune]strokes{
+	'│': north | south, '|': north | south,
+	'─': east | west, '-': east | west,
+	'└': north | east, '┘': north | west, '┐': south | west, '┌': south | east,
+	'├': north | south | east, '┤': north | south | west,
+	'┬': east | west | south, '┴': east | west | north,
+	'┼': north | south | east | west, '+': north | south | east | west,
+	'╵': north, '╶': east, '╷': south, '╴': west,
+	'/': rising, '\\': falling,
+	'#': solid, '█': solid,
+}
+
+// colors are what a cell is drawn in, black and white for whatever the
+// cell leaves to the terminal.
+func (c Cell) colors() (fg, bg Color) {
+	fg, bg = c.Fg, c.Bg
+	if !fg.Set {
+		fg = White
+	}
+	if !bg.Set {
+		bg = Black
+	}
+	return fg, bg
+}
+
+// lineWidth is how thick the lines of a cell size wide are.
+func lineWidth(size int) int {
+	return max(1, size/4)
+}
+
+// bars are the lines of s that run from the middle of a cell size wide to
+// its edges.
+func (s strokes) bars(size int) []image.Rectangle {
+	w := lineWidth(size)
+	lo := size/2 - w/2
+	hi := lo + w
+	var bars []image.Rectangle
+	for _, b := range []struct {
+		s   strokes
+		bar image.Rectangle
+	}{
+		{north, image.Rect(lo, 0, hi, hi)},
+		{east, image.Rect(lo, lo, size, hi)},
+		{south, image.Rect(lo, lo, hi, size)},
+		{west, image.Rect(0, lo, hi, hi)},
+	} {
+		if s&b.s != 0 {
+			bars = append(bars, b.bar)
+		}
+	}
+	return bars
+}
+
+// diagonal reports whether the middle of the pixel at x, y of a cell size
+// wide is on a diagonal line of s, which are as thick as the bars.
+func (s strokes) diagonal(x, y, size int) bool {
+	w := lineWidth(size)
+	return s&rising != 0 && abs(x+y+1-size) <= w ||
+		s&falling != 0 && abs(y-x) <= w
+}
+
+// diagonals are the outlines of the diagonal lines of s, for SVG.
+func (s strokes) diagonals(size int) [][]image.Point {
+	w := lineWidth(size)
+	var shapes [][]image.Point
+	if s&rising != 0 {
+		shapes = append(shapes, []image.Point{{0, size - w}, {size - w, 0}, {size, 0}, {size, w}, {w, size}, {0, size}})
+	}
+	if s&falling != 0 {
+		shapes = append(shapes, []image.Point{{0, 0}, {w, 0}, {size, size - w}, {size, size}, {size - w, size}, {0, w}})
+	}
+	return shapes
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Image draws f with each cell a square size pixels wide, filled with its
+// background and with the shape of its rune over it in its foreground.
+func Image(f *Frame, size int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, f.Width*size, f.Height*size))
+	for y := 0; y < f.Height; y++ {
+		for x := 0; x < f.Width; x++ {
+			c := f.At(x, y)
+			fg, bg := c.colors()
+			cell := image.Rect(x*size, y*size, (x+1)*size, (y+1)*size)
+			s := runeStrokes[c.Rune]
+			if s&solid != 0 {
+				fill(img, cell, fg)
+				continue
+			}
+			fill(img, cell, bg)
+			for _, bar := range s.bars(size) {
+				fill(img, bar.Add(cell.Min), fg)
+			}
+			if s&(rising|falling) != 0 {
+				for py := 0; py < size; py++ {
+					for px := 0; px < size; px++ {
+						if s.diagonal(px, py, size) {
+							img.SetRGBA(cell.Min.X+px, cell.Min.Y+py, color.RGBA{fg.R, fg.G, fg.B, 255})
+						}
+					}
+				}
+			}
+		}
+	}
+	return img
+}
+
+func fill(img *image.RGBA, r image.Rectangle, c Color) {
+	rgba := color.RGBA{c.R, c.G, c.B, 255}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetRGBA(x, y, rgba)
+		}
+	}
+}
+
+// PNG writes f to w as a PNG image, as Image draws it.
+func PNG(w io.Writer, f *Frame, size int) error {
+	return png.Encode(w, Image(f, size))
+}
+
+// SVG writes f to w as an SVG image that looks the same as Image draws it.
+func SVG(w io.Writer, f *Frame, size int) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" shape-rendering="crispEdges">`+"\n",
+		f.Width*size, f.Height*size)
+	for y := 0; y < f.Height; y++ {
+		for x := 0; x < f.Width; x++ {
+			c := f.At(x, y)
+			fg, bg := c.colors()
+			s := runeStrokes[c.Rune]
+			if s&solid != 0 {
+				bg = fg
+			}
+			fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", x*size, y*size, size, size, hex(bg))
+			for _, bar := range s.bars(size) {
+				bar = bar.Add(image.Pt(x*size, y*size))
+				fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", bar.Min.X, bar.Min.Y, bar.Dx(), bar.Dy(), hex(fg))
+			}
+			for _, shape := range s.diagonals(size) {
+				var points []string
+				for _, p := range shape {
+					points = append(points, fmt.Sprintf("%d,%d", x*size+p.X, y*size+p.Y))
+				}
+				fmt.Fprintf(b, `<polygon points="%s" fill="%s"/>`+"\n", strings.Join(points, " "), hex(fg))
+			}
+		}
+	}
+	fmt.Fprintln(b, "</svg>")
+	return b.Flush()
+}
+
+func hex(c Color) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
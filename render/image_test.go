@@ -0,0 +1,89 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestShade(t *testing.T) {
+	tests := []struct {
+		v, most int
+		want    Color
+	}{
+		{0, 4, Black},
+		{4, 4, White},
+		{9, 4, White},
+		{1, 0, Black},
+		{1, 4, RGB(160, 0, 0)},
+		{3, 8, RGB(208, 48, 0)}, // half way from red to orange
+	}
+	for _, tt := range tests {
+		if got := Heat.Shade(tt.v, tt.most); got != tt.want {
+			t.Errorf("Shade(%d, %d) = %v, want %v", tt.v, tt.most, got, tt.want)
+		}
+	}
+}
+
+func TestImage(t *testing.T) {
+	f := NewFrame(3, 1)
+	f.Set(0, 0, Cell{Rune: '#', Fg: Red, Bg: Blue})
+	f.Set(1, 0, Cell{Rune: '─', Fg: Yellow})
+	f.Set(2, 0, Cell{Rune: 'I', Fg: Black, Bg: Green})
+
+	var b bytes.Buffer
+	if err := PNG(&b, f, 8); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := img.Bounds().Size(); got.X != 24 || got.Y != 8 {
+		t.Fatalf("image is %v, want 24x8", got)
+	}
+	rgba := func(c Color) color.RGBA { return color.RGBA{c.R, c.G, c.B, 255} }
+	for _, tt := range []struct {
+		x, y int
+		want Color
+	}{
+		{0, 0, Red},     // a wall fills its cell
+		{8, 0, Black},   // no background is black
+		{8, 4, Yellow},  // a line goes through the middle
+		{15, 4, Yellow}, // right to the edge
+		{12, 7, Black},
+		{20, 4, Green}, // a letter is only its background
+	} {
+		if got := color.RGBAModel.Convert(img.At(tt.x, tt.y)); got != rgba(tt.want) {
+			t.Errorf("pixel %d,%d is %v, want %v", tt.x, tt.y, got, rgba(tt.want))
+		}
+	}
+
+	b.Reset()
+	if err := SVG(&b, f, 8); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg" width="24" height="8"`,
+		`<rect x="0" y="0" width="8" height="8" fill="#ff0000"/>`,
+		`<rect x="8" y="3" width="5" height="2" fill="#ffff00"/>`, // the west half of the line
+		`<rect x="16" y="0" width="8" height="8" fill="#00ff00"/>`,
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("no %s in\n%s", want, b.String())
+		}
+	}
+	d := xml.NewDecoder(&b)
+	for {
+		if _, err := d.Token(); err != nil {
+			if err != io.EOF {
+				t.Errorf("SVG is not XML: %v", err)
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,319 @@
+// Package render draws grids in the terminal, so that any day can animate
+// its simulation the same way. Colours are turned down to what the terminal
+// can show, and to plain text when the output is not a terminal at all.
+package render
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/havill/AdventOfCode/grid"
+)
+
+// A Display shows the frames of an animation, such as a Renderer in the
+// terminal or a GIF. A Display keeps the first error from Draw and returns
+// it again from Close, so the days can draw without checking each frame
+// and the runner still hears of a screen that went away.
+type Display interface {
+	Draw(f *Frame) error
+	Close() error
+}
+
+// Screen is where the days draw their animations. It is nil unless the
+// runner was asked to animate, so drawing costs nothing otherwise.
+var Screen Display
+
+type Color struct {
+	R, G, B uint8
+	Set     bool // the zero Color leaves the terminal's own colour alone
+}
+
+func RGB(r, g, b uint8) Color {
+	return Color{R: r, G: g, B: b, Set: true}
+}
+
+var (
+	Black  = RGB(0, 0, 0)
+	White  = RGB(255, 255, 255)
+	Red    = RGB(255, 0, 0)
+	Yellow = RGB(255, 255, 0)
+	Blue   = RGB(0, 0, 255)
+	Green  = RGB(0, 255, 0)
+	Grey   = RGB(128, 128, 128)
+	Navy   = RGB(0, 0, 96)
+)
+
+type Cell struct {
+	Rune   rune
+	Fg, Bg Color
+}
+
+type Frame struct {
+	Width, Height int
+	Cells         []Cell
+}
+
+func NewFrame(width, height int) *Frame {
+	return &Frame{Width: width, Height: height, Cells: make([]Cell, width*height)}
+}
+
+// A Picture draws what a solver made of its input as a frame, such as the
+// loop of pipe on 2023 day 10, so that it can be looked at outside the
+// terminal.
+type Picture struct {
+	Name string // short enough for a link or a file name, such as "loop"
+	// Draw gives up soon after ctx is done, as a solver does.
+	Draw func(ctx context.Context, r io.Reader) (*Frame, error)
+}
+
+// FromGrid draws every cell of a grid into a frame of the same size.
+func FromGrid[T any](g *grid.Grid[T], draw func(p grid.Point, v T) Cell) *Frame {
+	f := NewFrame(g.Width(), g.Height())
+	for p, v := range g.All() {
+		f.Set(p.X, p.Y, draw(p, v))
+	}
+	return f
+}
+
+func (f *Frame) Set(x, y int, c Cell) {
+	if x >= 0 && x < f.Width && y >= 0 && y < f.Height {
+		f.Cells[y*f.Width+x] = c
+	}
+}
+
+func (f *Frame) At(x, y int) Cell {
+	return f.Cells[y*f.Width+x]
+}
+
+func (f *Frame) row(y int) []Cell {
+	return f.Cells[y*f.Width : (y+1)*f.Width]
+}
+
+// String is the frame as plain text, a row per line.
+func (f *Frame) String() string {
+	var sb strings.Builder
+	for y := 0; y < f.Height; y++ {
+		for _, c := range f.row(y) {
+			sb.WriteRune(printable(c.Rune))
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func printable(r rune) rune {
+	if r == 0 {
+		return ' '
+	}
+	return r
+}
+
+type Profile int
+
+const (
+	Plain Profile = iota
+	ANSI256
+	TrueColor
+)
+
+func (p Profile) String() string {
+	switch p {
+	case ANSI256:
+		return "256"
+	case TrueColor:
+		return "truecolor"
+	}
+	return "plain"
+}
+
+// ParseProfile reads a profile name as used by the -color flag. "auto"
+// detects what f can show.
+func ParseProfile(name string, f *os.File) (Profile, error) {
+	switch name {
+	case "auto", "":
+		return Detect(f), nil
+	case "truecolor", "24bit":
+		return TrueColor, nil
+	case "256":
+		return ANSI256, nil
+	case "plain", "none":
+		return Plain, nil
+	}
+	return Plain, fmt.Errorf("unknown colour profile %q", name)
+}
+
+// Detect works out how many colours f can show, going by the same
+// environment variables that most terminal programs honour.
+func Detect(f *os.File) Profile {
+	if os.Getenv("NO_COLOR") != "" {
+		return Plain
+	}
+	info, err := f.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return Plain // piped or redirected, e.g. a CI log
+	}
+	term := os.Getenv("TERM")
+	if term == "" || term == "dumb" {
+		return Plain
+	}
+	switch os.Getenv("COLORTERM") {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+	return ANSI256
+}
+
+// Renderer draws frames, redrawing only the rows that changed since the
+// previous frame and never faster than its frame rate.
+type Renderer struct {
+	w        io.Writer
+	buf      bytes.Buffer // the frame being drawn, written to w in one go
+	profile  Profile
+	interval time.Duration
+	last     time.Time
+	front    *Frame // what is on the screen now
+	fg, bg   Color  // the colours the terminal is currently set to
+	err      error  // the first write that failed
+}
+
+// New makes a renderer that draws at most fps frames a second, or as fast
+// as it can when fps is 0. If w has a Resize(width, height int) method, it
+// is told the size of the screen whenever that changes.
+func New(w io.Writer, profile Profile, fps int) *Renderer {
+	r := &Renderer{w: w, profile: profile}
+	if fps > 0 {
+		r.interval = time.Second / time.Duration(fps)
+	}
+	return r
+}
+
+func (r *Renderer) Profile() Profile { return r.profile }
+
+// Draw shows the next frame, waiting first if the previous one was drawn
+// less than a frame ago. Once a write has failed it draws nothing more.
+func (r *Renderer) Draw(f *Frame) error {
+	if r.err != nil {
+		return r.err
+	}
+	if wait := r.interval - time.Since(r.last); !r.last.IsZero() && wait > 0 {
+		time.Sleep(wait)
+	}
+	r.last = time.Now()
+
+	if r.profile == Plain {
+		r.drawPlain(f)
+	} else {
+		r.drawANSI(f)
+	}
+	r.front = f
+	r.err = r.flush()
+	return r.err
+}
+
+type resizer interface {
+	Resize(width, height int)
+}
+
+func (r *Renderer) resize(f *Frame) {
+	if rs, ok := r.w.(resizer); ok {
+		if r.front == nil || r.front.Width != f.Width || r.front.Height != f.Height {
+			rs.Resize(f.Width, f.Height+1) // one more line for the cursor
+		}
+	}
+}
+
+func (r *Renderer) flush() error {
+	if r.buf.Len() == 0 {
+		return nil
+	}
+	_, err := r.w.Write(r.buf.Bytes())
+	r.buf.Reset()
+	return err
+}
+
+func (r *Renderer) drawPlain(f *Frame) {
+	if r.front != nil && r.front.String() == f.String() {
+		return // nothing visible changed, so keep the log short
+	}
+	r.resize(f)
+	r.buf.WriteString(f.String())
+	r.buf.WriteByte('\n')
+}
+
+func (r *Renderer) drawANSI(f *Frame) {
+	full := r.front == nil || r.front.Width != f.Width || r.front.Height != f.Height
+	r.resize(f)
+	if full {
+		r.buf.WriteString("\033[?25l\033[H\033[2J") // hide the cursor and clear the screen
+	}
+	for y := 0; y < f.Height; y++ {
+		if !full && rowsEqual(r.front.row(y), f.row(y)) {
+			continue
+		}
+		fmt.Fprintf(&r.buf, "\033[%d;1H", y+1) // move cursor to Ln y+1, Col 1
+		for _, c := range f.row(y) {
+			r.setColors(c.Fg, c.Bg)
+			r.buf.WriteRune(printable(c.Rune))
+		}
+		r.setColors(Color{}, Color{})
+	}
+	fmt.Fprintf(&r.buf, "\033[%d;1H", f.Height+1)
+}
+
+func rowsEqual(a, b []Cell) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Renderer) setColors(fg, bg Color) {
+	if fg == r.fg && bg == r.bg {
+		return
+	}
+	if (r.fg.Set && !fg.Set) || (r.bg.Set && !bg.Set) {
+		r.buf.WriteString("\033[0m")
+		r.fg, r.bg = Color{}, Color{}
+	}
+	if fg.Set && fg != r.fg {
+		r.buf.WriteString(r.escape(38, fg))
+	}
+	if bg.Set && bg != r.bg {
+		r.buf.WriteString(r.escape(48, bg))
+	}
+	r.fg, r.bg = fg, bg
+}
+
+// escape sets the foreground (38) or background (48) colour.
+func (r *Renderer) escape(layer int, c Color) string {
+	if r.profile == TrueColor {
+		return fmt.Sprintf("\033[%d;2;%d;%d;%dm", layer, c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("\033[%d;5;%dm", layer, to256(c))
+}
+
+// to256 picks the nearest colour in the 6x6x6 cube of the 256 colour palette.
+func to256(c Color) int {
+	scale := func(v uint8) int { return (int(v)*5 + 127) / 255 }
+	return 16 + 36*scale(c.R) + 6*scale(c.G) + scale(c.B)
+}
+
+// Close puts the terminal back the way it was found, and returns the first
+// error from Draw if there was one.
+func (r *Renderer) Close() error {
+	if r.profile != Plain && r.front != nil {
+		r.setColors(Color{}, Color{})
+		r.buf.WriteString("\033[?25h") // show the cursor again
+	}
+	if err := r.flush(); r.err == nil {
+		r.err = err
+	}
+	return r.err
+}
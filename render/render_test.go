@@ -0,0 +1,159 @@
+package render
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// flag is a frame of a red cell on blue and a plain one, over a row of
+// default cells.
+func flag() *Frame {
+	f := NewFrame(2, 2)
+	f.Set(0, 0, Cell{Rune: '#', Fg: Red, Bg: Blue})
+	f.Set(1, 0, Cell{Rune: '.'})
+	return f
+}
+
+// TestColorFallback draws the same frame at each profile, which should use
+// the colours it can show and nothing more.
+func TestColorFallback(t *testing.T) {
+	tests := []struct {
+		profile Profile
+		want    []string
+		not     []string
+	}{
+		{TrueColor, []string{"\033[38;2;255;0;0m", "\033[48;2;0;0;255m#", "\033[0m."}, []string{"\033[38;5;"}},
+		{ANSI256, []string{"\033[38;5;196m", "\033[48;5;21m#", "\033[0m."}, []string{"\033[38;2;"}},
+		{Plain, []string{"#.\n  \n\n"}, []string{"\033["}},
+	}
+	for _, tt := range tests {
+		var b bytes.Buffer
+		r := New(&b, tt.profile, 0)
+		if err := r.Draw(flag()); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Close(); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(b.String(), want) {
+				t.Errorf("%v: no %q in %q", tt.profile, want, b.String())
+			}
+		}
+		for _, not := range tt.not {
+			if strings.Contains(b.String(), not) {
+				t.Errorf("%v: %q in %q", tt.profile, not, b.String())
+			}
+		}
+	}
+}
+
+// TestRedraw checks that only the rows that changed are drawn again, and
+// that the cursor comes back when the renderer is closed.
+func TestRedraw(t *testing.T) {
+	var b bytes.Buffer
+	r := New(&b, ANSI256, 0)
+	r.Draw(flag())
+	if !strings.HasPrefix(b.String(), "\033[?25l\033[H\033[2J") {
+		t.Errorf("the first frame doesn't clear the screen: %q", b.String())
+	}
+
+	b.Reset()
+	f := flag()
+	f.Set(1, 1, Cell{Rune: 'x'})
+	r.Draw(f)
+	if got, want := b.String(), "\033[2;1H x\033[3;1H"; got != want {
+		t.Errorf("redrawing row 2 wrote %q, want %q", got, want)
+	}
+
+	b.Reset()
+	r.Draw(f)
+	if got, want := b.String(), "\033[3;1H"; got != want {
+		t.Errorf("drawing the same frame again wrote %q, want %q", got, want)
+	}
+
+	b.Reset()
+	r.Close()
+	if got, want := b.String(), "\033[?25h"; got != want {
+		t.Errorf("closing wrote %q, want %q", got, want)
+	}
+}
+
+func TestPlainSkipsRepeats(t *testing.T) {
+	var b bytes.Buffer
+	r := New(&b, Plain, 0)
+	r.Draw(flag())
+	r.Draw(flag())
+	if got, want := b.String(), "#.\n  \n\n"; got != want {
+		t.Errorf("the same frame twice wrote %q, want %q once", got, want)
+	}
+}
+
+func TestParseProfile(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("COLORTERM", "truecolor")
+
+	tests := []struct {
+		name string
+		want Profile
+	}{
+		{"truecolor", TrueColor},
+		{"24bit", TrueColor},
+		{"256", ANSI256},
+		{"none", Plain},
+		{"auto", Plain}, // a file is never a terminal
+	}
+	for _, tt := range tests {
+		if got, err := ParseProfile(tt.name, file); err != nil || got != tt.want {
+			t.Errorf("ParseProfile(%q) = %v, %v, want %v", tt.name, got, err, tt.want)
+		}
+	}
+	if _, err := ParseProfile("sepia", file); err == nil {
+		t.Errorf("ParseProfile(\"sepia\") succeeded")
+	}
+}
+
+// brokenPipe fails every write after the first n.
+type brokenPipe struct{ n int }
+
+func (b *brokenPipe) Write(p []byte) (int, error) {
+	if b.n == 0 {
+		return 0, os.ErrClosed
+	}
+	b.n--
+	return len(p), nil
+}
+
+// TestDrawError checks that the first failed write is kept, that nothing
+// more is drawn after it, and that Close returns it.
+func TestDrawError(t *testing.T) {
+	for _, profile := range []Profile{Plain, ANSI256} {
+		w := &brokenPipe{n: 1}
+		r := New(w, profile, 0)
+		if err := r.Draw(flag()); err != nil {
+			t.Fatalf("%v: the first frame: %v", profile, err)
+		}
+		f := flag()
+		f.Set(1, 1, Cell{Rune: 'x'})
+		if err := r.Draw(f); err != os.ErrClosed {
+			t.Errorf("%v: the second frame gave %v, want %v", profile, err, os.ErrClosed)
+		}
+		w.n = 1
+		f.Set(0, 1, Cell{Rune: 'y'})
+		if err := r.Draw(f); err != os.ErrClosed || w.n != 1 {
+			t.Errorf("%v: drawing after the failed write gave %v and wrote %d times", profile, err, 1-w.n)
+		}
+		if err := r.Close(); err != os.ErrClosed {
+			t.Errorf("%v: Close gave %v, want %v", profile, err, os.ErrClosed)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Package search finds shortest paths through the states of a puzzle. The
+// states can be anything comparable, such as a grid.Point or a position and
+// a heading, and a puzzle describes its moves with a neighbors function.
+package search
+
+import (
+	"container/heap"
+	"iter"
+)
+
+type hqi[T any] struct {
+	v T
+	p int
+}
+
+// HeapQ is a priority queue that pops the value pushed with the lowest
+// priority first.
+type HeapQ[T any] []hqi[T]
+
+func (q HeapQ[_]) Len() int           { return len(q) }
+func (q HeapQ[_]) Less(i, j int) bool { return q[i].p < q[j].p }
+func (q HeapQ[_]) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *HeapQ[T]) Push(x any)        { *q = append(*q, x.(hqi[T])) }
+func (q *HeapQ[_]) Pop() (x any)      { x, *q = (*q)[len(*q)-1], (*q)[:len(*q)-1]; return x }
+func (q *HeapQ[T]) GPush(v T, p int)  { heap.Push(q, hqi[T]{v, p}) }
+func (q *HeapQ[T]) GPop() (T, int)    { x := heap.Pop(q).(hqi[T]); return x.v, x.p }
+
+// Neighbors lists the states one move away from s, with what each move costs.
+type Neighbors[S comparable] func(s S) iter.Seq2[S, int]
+
+// Tree is what a search learned: how far each state it reached is from the
+// nearest start, and the way back.
+type Tree[S comparable] struct {
+	Dist map[S]int
+	prev map[S]S
+}
+
+func newTree[S comparable]() *Tree[S] {
+	return &Tree[S]{Dist: map[S]int{}, prev: map[S]S{}}
+}
+
+// Reached reports whether s was reached, and how far away it is.
+func (t *Tree[S]) Reached(s S) (int, bool) {
+	d, ok := t.Dist[s]
+	return d, ok
+}
+
+// Path is the route from a start to s, both included, or nil if s was not
+// reached.
+func (t *Tree[S]) Path(s S) []S {
+	if _, ok := t.Dist[s]; !ok {
+		return nil
+	}
+	path := []S{s}
+	for {
+		p, ok := t.prev[s]
+		if !ok {
+			break
+		}
+		path = append(path, p)
+		s = p
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// Result is the outcome of a search for a goal.
+type Result[S comparable] struct {
+	Found bool
+	Cost  int
+	End   S // the goal that was reached
+	*Tree[S]
+}
+
+// Route is the path to the goal that was found, or nil.
+func (r Result[S]) Route() []S {
+	if !r.Found {
+		return nil
+	}
+	return r.Path(r.End)
+}
+
+type step[S comparable] struct {
+	state, from S
+	first       bool // a start, so it has no from
+	cost        int
+}
+
+// AStar finds the cheapest route from any of the starts to a state that
+// satisfies goal. The heuristic guesses the cost left from a state to the
+// goal; it must never guess too high or the route found may not be the
+// cheapest. Costs must not be negative.
+func AStar[S comparable](starts []S, neighbors Neighbors[S], goal func(S) bool, heuristic func(S) int) Result[S] {
+	t := newTree[S]()
+	queue := HeapQ[step[S]]{}
+	for _, s := range starts {
+		queue.GPush(step[S]{state: s, first: true}, heuristic(s))
+	}
+	for len(queue) > 0 {
+		st, _ := queue.GPop()
+		if _, ok := t.Dist[st.state]; ok {
+			continue
+		}
+		t.Dist[st.state] = st.cost
+		if !st.first {
+			t.prev[st.state] = st.from
+		}
+		if goal != nil && goal(st.state) {
+			return Result[S]{Found: true, Cost: st.cost, End: st.state, Tree: t}
+		}
+		for n, c := range neighbors(st.state) {
+			if _, ok := t.Dist[n]; !ok {
+				cost := st.cost + c
+				queue.GPush(step[S]{state: n, from: st.state, cost: cost}, cost+heuristic(n))
+			}
+		}
+	}
+	return Result[S]{Tree: t}
+}
+
+// Dijkstra is AStar without a heuristic.
+func Dijkstra[S comparable](starts []S, neighbors Neighbors[S], goal func(S) bool) Result[S] {
+	return AStar(starts, neighbors, goal, func(S) int { return 0 })
+}
+
+// ShortestPaths is Dijkstra without a goal, finding the cheapest route to
+// every state that can be reached.
+func ShortestPaths[S comparable](starts []S, neighbors Neighbors[S]) *Tree[S] {
+	return Dijkstra(starts, neighbors, nil).Tree
+}
+
+// BFS finds the fewest moves to every state that can be reached, when every
+// move costs the same.
+func BFS[S comparable](starts []S, neighbors func(s S) iter.Seq[S]) *Tree[S] {
+	t := newTree[S]()
+	var queue []S
+	for _, s := range starts {
+		if _, ok := t.Dist[s]; !ok {
+			t.Dist[s] = 0
+			queue = append(queue, s)
+		}
+	}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		for n := range neighbors(s) {
+			if _, ok := t.Dist[n]; !ok {
+				t.Dist[n] = t.Dist[s] + 1
+				t.prev[n] = s
+				queue = append(queue, n)
+			}
+		}
+	}
+	return t
+}
@@ -0,0 +1,167 @@
+package search
+
+import (
+	"image"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// roads are two-way, with how long each is; g is on its own.
+var roads = map[string]map[string]int{}
+
+func init() {
+	for _, r := range []struct {
+		a, b string
+		cost int
+	}{
+		{"a", "b", 7}, {"a", "c", 9}, {"a", "f", 14}, {"b", "c", 10}, {"b", "d", 15},
+		{"c", "d", 11}, {"c", "f", 2}, {"d", "e", 6}, {"e", "f", 9},
+	} {
+		for _, e := range [][2]string{{r.a, r.b}, {r.b, r.a}} {
+			if roads[e[0]] == nil {
+				roads[e[0]] = map[string]int{}
+			}
+			roads[e[0]][e[1]] = r.cost
+		}
+	}
+	roads["g"] = map[string]int{}
+}
+
+func drive(town string) iter.Seq2[string, int] {
+	return func(yield func(string, int) bool) {
+		for _, next := range slices.Sorted(maps.Keys(roads[town])) {
+			if !yield(next, roads[town][next]) {
+				return
+			}
+		}
+	}
+}
+
+func TestDijkstra(t *testing.T) {
+	tests := []struct {
+		starts []string
+		goal   string
+		found  bool
+		cost   int
+		route  string
+	}{
+		{[]string{"a"}, "e", true, 20, "a c f e"},
+		{[]string{"a"}, "a", true, 0, "a"},
+		{[]string{"a", "d"}, "e", true, 6, "d e"}, // from the nearer start
+		{[]string{"b", "f"}, "a", true, 7, "b a"},
+		{[]string{"a"}, "g", false, 0, ""},
+		{nil, "a", false, 0, ""},
+	}
+	for _, tt := range tests {
+		r := Dijkstra(tt.starts, drive, func(s string) bool { return s == tt.goal })
+		if r.Found != tt.found || r.Cost != tt.cost {
+			t.Errorf("%v to %s: found %v at %d, want %v at %d", tt.starts, tt.goal, r.Found, r.Cost, tt.found, tt.cost)
+		}
+		if got := strings.Join(r.Route(), " "); got != tt.route {
+			t.Errorf("%v to %s: went %q, want %q", tt.starts, tt.goal, got, tt.route)
+		}
+		if !tt.found && r.Route() != nil {
+			t.Errorf("%v to %s: a route to nowhere", tt.starts, tt.goal)
+		}
+	}
+}
+
+func TestShortestPaths(t *testing.T) {
+	tree := ShortestPaths([]string{"a"}, drive)
+	want := map[string]int{"a": 0, "b": 7, "c": 9, "d": 20, "e": 20, "f": 11}
+	for town, cost := range want {
+		if got, ok := tree.Reached(town); !ok || got != cost {
+			t.Errorf("%s is %d away (reached %v), want %d", town, got, ok, cost)
+		}
+	}
+	if _, ok := tree.Reached("g"); ok {
+		t.Errorf("g was reached")
+	}
+	if got := strings.Join(tree.Path("d"), " "); got != "a c d" {
+		t.Errorf("the way to d is %q, want \"a c d\"", got)
+	}
+	if tree.Path("g") != nil {
+		t.Errorf("there is a way to g")
+	}
+}
+
+// maze is walked with each step costing the digit on the tile stepped on.
+var maze = []string{
+	"1119111",
+	"9919191",
+	"1111191",
+	"1999991",
+	"1111111",
+}
+
+func walk(p image.Point) iter.Seq2[image.Point, int] {
+	return func(yield func(image.Point, int) bool) {
+		for _, d := range []image.Point{{1, 0}, {0, 1}, {-1, 0}, {0, -1}} {
+			n := p.Add(d)
+			if n.In(image.Rect(0, 0, len(maze[0]), len(maze))) && !yield(n, int(maze[n.Y][n.X]-'0')) {
+				return
+			}
+		}
+	}
+}
+
+// TestAStar checks that a heuristic that never guesses too high finds
+// routes as cheap as Dijkstra's, and that they cost what they say.
+func TestAStar(t *testing.T) {
+	start := []image.Point{{0, 0}}
+	for y := range maze {
+		for x := range maze[y] {
+			goal := image.Pt(x, y)
+			at := func(p image.Point) bool { return p == goal }
+			manhattan := func(p image.Point) int {
+				d := p.Sub(goal)
+				return max(d.X, -d.X) + max(d.Y, -d.Y)
+			}
+			want := Dijkstra(start, walk, at)
+			got := AStar(start, walk, at, manhattan)
+			if !got.Found || got.Cost != want.Cost {
+				t.Errorf("to %v: A* found %v at %d, Dijkstra at %d", goal, got.Found, got.Cost, want.Cost)
+				continue
+			}
+			route := got.Route()
+			cost := 0
+			for i, p := range route[1:] {
+				if d := p.Sub(route[i]); max(d.X, -d.X)+max(d.Y, -d.Y) != 1 {
+					t.Errorf("to %v: jumped from %v to %v", goal, route[i], p)
+				}
+				cost += int(maze[p.Y][p.X] - '0')
+			}
+			if route[0] != start[0] || route[len(route)-1] != goal || cost != got.Cost {
+				t.Errorf("to %v: route %v costs %d, want %d", goal, route, cost, got.Cost)
+			}
+		}
+	}
+	if got := Dijkstra(start, walk, func(p image.Point) bool { return p == image.Pt(6, 0) }); got.Cost != 10 {
+		t.Errorf("to the top right costs %d, want 10", got.Cost)
+	}
+}
+
+func TestBFS(t *testing.T) {
+	open := func(p image.Point) iter.Seq[image.Point] {
+		return func(yield func(image.Point) bool) {
+			for n, cost := range walk(p) {
+				if cost == 1 && !yield(n) {
+					return
+				}
+			}
+		}
+	}
+	tree := BFS([]image.Point{{0, 0}}, open)
+	if d, ok := tree.Reached(image.Pt(6, 0)); !ok || d != 10 {
+		t.Errorf("the top right is %d steps away (reached %v), want 10", d, ok)
+	}
+	if _, ok := tree.Reached(image.Pt(3, 0)); ok {
+		t.Errorf("a 9 was walked on")
+	}
+	if path := tree.Path(image.Pt(0, 2)); len(path) != 7 {
+		t.Errorf("the way to 0,2 is %v, want 7 tiles", path)
+	}
+}
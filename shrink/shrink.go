@@ -0,0 +1,204 @@
+// Package shrink cuts an input that shows a problem down to a small one that
+// still does, by delta debugging: leaving out parts of it, first in big
+// pieces and then in smaller ones, and keeping each cut that the problem
+// survives.
+package shrink
+
+import (
+	"bytes"
+	"strings"
+)
+
+// A Cut is one way of taking an input apart, into lines, columns and so on.
+// It returns how many parts there are and a function that puts the input
+// back together from the ones given, in order. An input it doesn't fit has
+// no parts.
+type Cut func(input []byte) (parts int, rebuild func(kept []int) []byte)
+
+// Cuts are the ones Minimize tries when it is given none.
+var Cuts = []Cut{Lines, Columns, Items}
+
+// Minimize makes input as small as it can while fails still holds for it,
+// trying each of cuts in turn until none of them makes any difference.
+// Nothing is checked twice, so fails can be slow. Inputs a solver can't
+// read have to be reported as not failing, and then everything Minimize
+// returns is still well formed.
+func Minimize(input []byte, fails func(input []byte) bool, cuts ...Cut) []byte {
+	if len(cuts) == 0 {
+		cuts = Cuts
+	}
+	tried := make(map[string]bool)
+	check := func(input []byte) bool {
+		result, ok := tried[string(input)]
+		if !ok {
+			result = fails(input)
+			tried[string(input)] = result
+		}
+		return result
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, cut := range cuts {
+			parts, rebuild := cut(input)
+			kept := ddmin(parts, func(kept []int) bool { return check(rebuild(kept)) })
+			if len(kept) < parts {
+				input = rebuild(kept)
+				changed = true
+			}
+		}
+	}
+	return input
+}
+
+// ddmin finds a small set of the parts for which fails still holds, none of
+// which can be left out on its own. It splits what is kept into chunks and
+// tries each chunk alone and then everything but each chunk, splitting more
+// finely whenever neither helps.
+func ddmin(parts int, fails func(kept []int) bool) []int {
+	kept := make([]int, parts)
+	for i := range kept {
+		kept[i] = i
+	}
+	for chunks := 2; len(kept) >= 2; {
+		size := (len(kept) + chunks - 1) / chunks
+		reduced := false
+		for start := 0; start < len(kept) && !reduced; start += size {
+			end := min(start+size, len(kept))
+			chunk := kept[start:end]
+			rest := append(append([]int(nil), kept[:start]...), kept[end:]...)
+			switch {
+			case fails(chunk):
+				kept, chunks, reduced = chunk, 2, true
+			case chunks > 2 && fails(rest):
+				kept, chunks, reduced = rest, max(chunks-1, 2), true
+			}
+		}
+		if !reduced {
+			if chunks >= len(kept) {
+				break
+			}
+			chunks = min(2*chunks, len(kept))
+		}
+	}
+	return kept
+}
+
+// lines splits input into its lines, and reports whether the last one ended
+// with a newline.
+func lines(input []byte) ([]string, bool) {
+	text := string(input)
+	ended := strings.HasSuffix(text, "\n")
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" && !ended {
+		return nil, false
+	}
+	return strings.Split(text, "\n"), ended
+}
+
+// join is the reverse of lines.
+func join(lines []string, ended bool) []byte {
+	var b bytes.Buffer
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(line)
+	}
+	if ended && len(lines) > 0 {
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
+
+// Lines leaves out whole lines, which are the rows of a grid.
+func Lines(input []byte) (int, func(kept []int) []byte) {
+	all, ended := lines(input)
+	return len(all), func(kept []int) []byte {
+		some := make([]string, len(kept))
+		for i, k := range kept {
+			some[i] = all[k]
+		}
+		return join(some, ended)
+	}
+}
+
+// Columns leaves out columns of a grid, an input whose lines are all the
+// same length. Any other input has none.
+func Columns(input []byte) (int, func(kept []int) []byte) {
+	all, ended := lines(input)
+	width := 0
+	if len(all) > 1 {
+		width = len(all[0])
+	}
+	for _, line := range all {
+		if len(line) != width {
+			width = 0
+		}
+	}
+	return width, func(kept []int) []byte {
+		some := make([]string, len(all))
+		for y, line := range all {
+			row := make([]byte, len(kept))
+			for i, x := range kept {
+				row[i] = line[x]
+			}
+			some[y] = string(row)
+		}
+		return join(some, ended)
+	}
+}
+
+// separators are what Items splits a list by, the first one a line has
+// being the one it uses.
+var separators = []string{", ", ",", " "}
+
+// Items leaves out items of the lists on each line: the numbers of
+// "seeds: 1 2 3", the pages of "75,47,61", the wires of "jqt: rhn xhk" and
+// the like. Anything up to a colon and a space is a label that stays.
+func Items(input []byte) (int, func(kept []int) []byte) {
+	type list struct {
+		label, separator string
+		items            []string
+	}
+	all, ended := lines(input)
+	lists := make([]list, len(all))
+	var where [][2]int // the line and place in it of each item
+	for y, line := range all {
+		if i := strings.Index(line, ": "); i >= 0 {
+			lists[y].label, line = line[:i+2], line[i+2:]
+		}
+		lists[y].items = []string{line}
+		for _, sep := range separators {
+			if strings.Contains(line, sep) {
+				lists[y].separator = sep
+				lists[y].items = strings.Split(line, sep)
+				for i := range lists[y].items {
+					where = append(where, [2]int{y, i})
+				}
+				break
+			}
+		}
+	}
+	return len(where), func(kept []int) []byte {
+		keep := make(map[[2]int]bool, len(kept))
+		for _, k := range kept {
+			keep[where[k]] = true
+		}
+		some := make([]string, len(all))
+		for y, l := range lists {
+			if l.separator == "" {
+				some[y] = all[y]
+				continue
+			}
+			var items []string
+			for i, item := range l.items {
+				if keep[[2]int{y, i}] {
+					items = append(items, item)
+				}
+			}
+			some[y] = l.label + strings.Join(items, l.separator)
+		}
+		return join(some, ended)
+	}
+}
@@ -0,0 +1,55 @@
+package shrink
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMinimize(t *testing.T) {
+	tests := []struct {
+		name, input, want string
+		fails             func(input string) bool
+		cuts              []Cut
+	}{
+		{
+			name:  "lines",
+			input: "a\nb\nc\nd\ne\nf\ng\n",
+			want:  "b\nf\n",
+			fails: func(input string) bool { return strings.Contains(input, "b") && strings.Contains(input, "f") },
+		},
+		{
+			name:  "columns",
+			input: "..#..\n.....\n....#\n",
+			want:  "#.\n..\n.#\n",
+			fails: func(input string) bool { return strings.Count(input, "#") == 2 && !strings.Contains(input, "##") },
+			cuts:  []Cut{Columns},
+		},
+		{
+			name:  "items",
+			input: "seeds: 1 2 3 4\n75,47,61,53\n",
+			want:  "seeds: 3\n47\n",
+			fails: func(input string) bool { return strings.Contains(input, ": 3") && strings.Contains(input, "47") },
+			cuts:  []Cut{Items},
+		},
+		{
+			name:  "no newline at the end",
+			input: "1,2,3\n4,5,6",
+			want:  "5",
+			fails: func(input string) bool { return strings.Contains(input, "5") },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			fails := func(input []byte) bool {
+				calls++
+				return tt.fails(string(input))
+			}
+			got := Minimize([]byte(tt.input), fails, tt.cuts...)
+			if !bytes.Equal(got, []byte(tt.want)) {
+				t.Errorf("got %q, want %q after %d tries", got, tt.want, calls)
+			}
+		})
+	}
+}